@@ -0,0 +1,50 @@
+package clipboard
+
+import (
+	"client/pkg/supervisor"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"supervisor/api"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// CopyCmd represents the clipboard copy command.
+var CopyCmd = &cobra.Command{
+	Use:   "copy [text]...",
+	Short: "Copy the arguments or stdin to the workspace clipboard",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var content []byte
+		if len(args) > 0 {
+			content = []byte(strings.Join(args, " "))
+		} else {
+			var err error
+			content, err = io.ReadAll(os.Stdin)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Set a timeout for the request
+		ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Second)
+		defer cancel()
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Set the clipboard, clients receive it through ClipboardService.Watch.
+		// Don't emit OSC 52 as well, supervisor would set the clipboard a second time.
+		_, err = client.Clipboard.Copy(ctx, &api.CopyClipboardRequest{
+			Content: content,
+			Source:  "oc",
+		})
+		return err
+	},
+}
@@ -0,0 +1,38 @@
+package clipboard
+
+import (
+	"client/pkg/supervisor"
+	"context"
+	"os"
+	"supervisor/api"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// PasteCmd represents the clipboard paste command.
+var PasteCmd = &cobra.Command{
+	Use:   "paste",
+	Short: "Print the content of the workspace clipboard",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set a timeout for the request
+		ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Second)
+		defer cancel()
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Fetch the clipboard
+		data, err := client.Clipboard.Paste(ctx, &api.PasteClipboardRequest{})
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data.Clipboard.Content)
+		return err
+	},
+}
@@ -0,0 +1,23 @@
+package clipboard
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the "clipboard" command used to share the clipboard with the workspace.
+var Cmd = &cobra.Command{
+	Use:   "clipboard",
+	Short: "Copy and paste through the workspace clipboard",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return cmd.Help()
+		}
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(CopyCmd)
+	Cmd.AddCommand(PasteCmd)
+}
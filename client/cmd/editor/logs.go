@@ -0,0 +1,94 @@
+package editor
+
+import (
+	"client/pkg/supervisor"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"supervisor/api"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var logsOpts struct {
+	Follow     bool
+	Tail       uint32
+	Since      time.Duration
+	Timestamps bool
+}
+
+func init() {
+	LogsCmd.Flags().BoolVarP(&logsOpts.Follow, "follow", "f", false, "Keep printing new output of the editor")
+	LogsCmd.Flags().Uint32VarP(&logsOpts.Tail, "tail", "n", 0, "Number of most recent lines to print, all if 0")
+	LogsCmd.Flags().DurationVarP(&logsOpts.Since, "since", "s", 0, "Only print output written within this duration, e.g. 10m")
+	LogsCmd.Flags().BoolVarP(&logsOpts.Timestamps, "timestamps", "t", false, "Prefix every line with the time it was written at")
+	LogsCmd.Flags().BoolVarP(&jsonFormat, "json", "j", false, "Output in JSON format")
+}
+
+// LogsCmd represents the editor logs command.
+var LogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Print the output of the editor",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if !logsOpts.Follow {
+			// Set a timeout for the request
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+		}
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		req := &api.EditorLogsRequest{
+			Tail:   logsOpts.Tail,
+			Follow: logsOpts.Follow,
+		}
+		if logsOpts.Since > 0 {
+			req.Since = time.Now().Add(-logsOpts.Since).Unix()
+		}
+		stream, err := client.Editor.Logs(ctx, req)
+		if err != nil {
+			return err
+		}
+		for {
+			line, err := stream.Recv()
+			if err != nil {
+				if ctx.Err() != nil || errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			printLogLine(line)
+		}
+	},
+}
+
+// printLogLine prints a line of editor output to stdout or stderr, like the editor wrote it.
+func printLogLine(line *api.EditorLogLine) {
+	if jsonFormat {
+		content, _ := json.Marshal(line)
+		fmt.Println(string(content))
+		return
+	}
+
+	out := os.Stdout
+	if line.Stream == api.EditorLogStream_stderr {
+		out = os.Stderr
+	}
+	if logsOpts.Timestamps {
+		_, _ = fmt.Fprintf(out, "%s %s\n", time.UnixMilli(line.Time).Format(time.RFC3339Nano), line.Text)
+		return
+	}
+	_, _ = fmt.Fprintln(out, line.Text)
+}
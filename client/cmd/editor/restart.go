@@ -0,0 +1,38 @@
+package editor
+
+import (
+	"client/pkg/supervisor"
+	"context"
+	"fmt"
+	"supervisor/api"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// RestartCmd represents the editor restart command.
+var RestartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restart the editor without restarting the workspace",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set a timeout for the request, stopping the editor can take a while
+		ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+		defer cancel()
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Restart the editor
+		_, err = client.Editor.Restart(ctx, &api.RestartEditorRequest{})
+		if err != nil {
+			return err
+		}
+		fmt.Println("editor restarted")
+		return nil
+	},
+}
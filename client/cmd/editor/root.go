@@ -0,0 +1,28 @@
+package editor
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var jsonFormat bool
+var noColor bool
+
+// Cmd represents the "editor" command used to manage the workspace editor.
+var Cmd = &cobra.Command{
+	Use:   "editor",
+	Short: "Inspect and control the workspace editor",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return cmd.Help()
+		}
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(StatusCmd)
+	Cmd.AddCommand(RestartCmd)
+	Cmd.AddCommand(StopCmd)
+	Cmd.AddCommand(LogsCmd)
+}
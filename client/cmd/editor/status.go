@@ -0,0 +1,129 @@
+package editor
+
+import (
+	"client/pkg/supervisor"
+	"client/pkg/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"supervisor/api"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+type statusCmd struct{}
+
+var statusOpts struct {
+	Watch bool
+}
+
+func init() {
+	StatusCmd.Flags().BoolVarP(&statusOpts.Watch, "watch", "w", false, "Print the status on every change")
+	StatusCmd.Flags().BoolVarP(&noColor, "no-color", "", false, "Disable output colorization")
+	StatusCmd.Flags().BoolVarP(&jsonFormat, "json", "j", false, "Output in JSON format")
+}
+
+// StatusCmd represents the editor status command.
+var StatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Display the status of the editor",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if !statusOpts.Watch {
+			// Set a timeout for the request
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+		}
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		if !statusOpts.Watch {
+			data, err := client.Editor.GetStatus(ctx, &api.GetEditorStatusRequest{})
+			if err != nil {
+				return err
+			}
+			statusCmd{}.Print(data)
+			return nil
+		}
+
+		// Print every status change until interrupted
+		stream, err := client.Editor.WatchStatus(ctx, &api.WatchEditorStatusRequest{})
+		if err != nil {
+			return err
+		}
+		for {
+			data, err := stream.Recv()
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			statusCmd{}.Print(data)
+		}
+	},
+}
+
+// Print outputs the status in JSON or table format
+func (sc statusCmd) Print(status *api.EditorStatus) {
+	if jsonFormat {
+		content, _ := json.Marshal(status)
+		fmt.Println(string(content))
+		return
+	}
+	sc.PrintTable(status)
+}
+
+// PrintTable renders the editor status in a table format
+func (sc statusCmd) PrintTable(status *api.EditorStatus) {
+	phase := status.Phase.String()
+	if !noColor && utils.ColorsEnabled() {
+		phase = sc.getColor(status.Phase) + phase + "\033[0m"
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	_ = table.Append([]string{"Editor", fmt.Sprintf("%s %s", status.Name, status.Version)})
+	_ = table.Append([]string{"Phase", phase})
+	_ = table.Append([]string{"Ready", fmt.Sprint(status.Ready)})
+	if status.Pid != 0 {
+		_ = table.Append([]string{"Pid", fmt.Sprint(status.Pid)})
+		_ = table.Append([]string{"Uptime", (time.Duration(status.Uptime) * time.Second).String()})
+	}
+	_ = table.Append([]string{"Restarts", fmt.Sprint(status.Restarts)})
+	if status.Reason != "" {
+		_ = table.Append([]string{"Last stop", status.Reason})
+	}
+	if status.NextRestart != 0 {
+		_ = table.Append([]string{"Next restart", time.Unix(status.NextRestart, 0).Format(time.TimeOnly)})
+	}
+	for _, ext := range status.Extensions {
+		state := ext.State.String()
+		if ext.Error != "" {
+			state = fmt.Sprintf("%s: %s", state, ext.Error)
+		}
+		_ = table.Append([]string{"Extension " + ext.Ref, state})
+	}
+	_ = table.Render()
+}
+
+// getColor returns the ANSI color code for a given phase.
+func (sc statusCmd) getColor(phase api.EditorPhase) string {
+	switch phase {
+	case api.EditorPhase_running:
+		return "\033[32m" // green
+	case api.EditorPhase_failed:
+		return "\033[31m" // red
+	default:
+		return "\033[33m" // yellow
+	}
+}
@@ -0,0 +1,38 @@
+package editor
+
+import (
+	"client/pkg/supervisor"
+	"context"
+	"fmt"
+	"supervisor/api"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// StopCmd represents the editor stop command.
+var StopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the editor until it is restarted",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set a timeout for the request, stopping the editor can take a while
+		ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+		defer cancel()
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Stop the editor
+		_, err = client.Editor.Stop(ctx, &api.StopEditorRequest{})
+		if err != nil {
+			return err
+		}
+		fmt.Println("editor stopped")
+		return nil
+	},
+}
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"client/cmd/clipboard"
+	"client/cmd/editor"
 	"client/cmd/ping"
 	"client/cmd/pkg"
 	"client/cmd/system"
 	"client/cmd/tasks"
+	"client/cmd/terminal"
 	"client/cmd/workspace"
 	"context"
 	"fmt"
@@ -50,9 +53,12 @@ var rootCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(ping.Cmd)
 	rootCmd.AddCommand(tasks.Cmd)
+	rootCmd.AddCommand(terminal.Cmd)
 	rootCmd.AddCommand(system.Cmd)
 	rootCmd.AddCommand(workspace.Cmd)
 	rootCmd.AddCommand(pkg.Cmd)
+	rootCmd.AddCommand(clipboard.Cmd)
+	rootCmd.AddCommand(editor.Cmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
@@ -4,19 +4,36 @@ import (
 	"client/pkg/supervisor"
 	"client/pkg/utils"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"supervisor/api"
 	"time"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 type resourceCmd struct{}
 
+// sparkBlocks are the characters of sparklines, from the lowest to the highest usage
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+var resourceOpts struct {
+	Watch   bool
+	History time.Duration
+	Step    time.Duration
+	CSV     bool
+}
+
 func init() {
+	ResourceCmd.Flags().BoolVarP(&resourceOpts.Watch, "watch", "w", false, "Refresh the resource usage every time it is sampled")
+	ResourceCmd.Flags().DurationVarP(&resourceOpts.History, "history", "", 0, "Display the resource usage over the given past duration, e.g. 15m")
+	ResourceCmd.Flags().DurationVarP(&resourceOpts.Step, "step", "", 0, "Time covered by each sample of the history, defaults to 1/60 of the history")
+	ResourceCmd.Flags().BoolVarP(&resourceOpts.CSV, "csv", "", false, "Output the history in CSV format")
 	ResourceCmd.Flags().BoolVarP(&noColor, "no-color", "", false, "Disable output colorization")
 	ResourceCmd.Flags().BoolVarP(&jsonFormat, "json", "j", false, "Output in JSON format")
 }
@@ -25,10 +42,22 @@ func init() {
 var ResourceCmd = &cobra.Command{
 	Use:   "resources",
 	Short: "Display workspace resource usage (CPU, Memory and Disk)",
+	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Set a timeout for the request
-		ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Second)
-		defer cancel()
+		if resourceOpts.Watch && resourceOpts.History > 0 {
+			return fmt.Errorf("--watch and --history cannot be combined")
+		}
+		if resourceOpts.CSV && resourceOpts.History == 0 {
+			return fmt.Errorf("--csv requires --history")
+		}
+
+		ctx := cmd.Context()
+		if !resourceOpts.Watch {
+			// Set a timeout for the request
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+		}
 
 		// Create a supervisor client
 		client, err := supervisor.New(ctx)
@@ -37,23 +66,159 @@ var ResourceCmd = &cobra.Command{
 		}
 		defer client.Close()
 
-		// Fetch resources usage
-		data, err := client.System.ResourcesStatus(ctx, &api.ResourcesStatusRequest{})
+		if resourceOpts.History > 0 {
+			step := resourceOpts.Step
+			if step == 0 {
+				step = resourceOpts.History / 60
+			}
+			data, err := client.System.ResourcesHistory(ctx, &api.ResourcesHistoryRequest{
+				Window: uint32(resourceOpts.History / time.Second),
+				Step:   uint32(step / time.Second),
+			})
+			if err != nil {
+				return err
+			}
+			switch {
+			case jsonFormat:
+				content, _ := json.Marshal(data)
+				fmt.Println(string(content))
+			case resourceOpts.CSV:
+				return resourceCmd{}.PrintCSV(data.Samples)
+			default:
+				resourceCmd{}.PrintHistory(data.Samples)
+			}
+			return nil
+		}
+
+		if !resourceOpts.Watch {
+			// Fetch resources usage
+			data, err := client.System.ResourcesStatus(ctx, &api.ResourcesStatusRequest{})
+			if err != nil {
+				return err
+			}
+			resourceCmd{}.Print(data)
+			return nil
+		}
+
+		// Refresh the resource usage until interrupted
+		stream, err := client.System.WatchResources(ctx, &api.WatchResourcesRequest{})
 		if err != nil {
 			return err
 		}
-
-		// Output in JSON or table format
-		if jsonFormat {
-			content, _ := json.Marshal(data)
-			fmt.Println(string(content))
-		} else {
-			resourceCmd{}.PrintTable(data)
+		clear := !jsonFormat && term.IsTerminal(int(os.Stdout.Fd()))
+		for {
+			data, err := stream.Recv()
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			if clear {
+				fmt.Print("\033[H\033[2J")
+			}
+			resourceCmd{}.Print(data)
 		}
-		return nil
 	},
 }
 
+// Print outputs the resource usage in JSON or table format
+func (rc resourceCmd) Print(resources *api.ResourcesStatusResponse) {
+	if jsonFormat {
+		content, _ := json.Marshal(resources)
+		fmt.Println(string(content))
+	} else {
+		rc.PrintTable(resources)
+	}
+}
+
+// PrintHistory renders the resource usage history as sparklines
+func (rc resourceCmd) PrintHistory(samples []*api.ResourcesSample) {
+	if len(samples) == 0 {
+		fmt.Println("No resource usage has been sampled yet")
+		return
+	}
+	from, to := time.Unix(samples[0].Time, 0), time.Unix(samples[len(samples)-1].Time, 0)
+	fmt.Printf("Resource usage from %s to %s\n", from.Format(time.TimeOnly), to.Format(time.TimeOnly))
+
+	metrics := []struct {
+		name   string
+		status func(s *api.ResourcesSample) *api.ResourceStatus
+		format func(used, limit int64) string
+	}{
+		{"CPU", func(s *api.ResourcesSample) *api.ResourceStatus { return s.Cpu }, func(used, limit int64) string {
+			return fmt.Sprintf("%dm/%dm", used, limit)
+		}},
+		{"Memory", func(s *api.ResourcesSample) *api.ResourceStatus { return s.Memory }, func(used, limit int64) string {
+			return fmt.Sprintf("%dMi/%dMi", used/(1024*1024), limit/(1024*1024))
+		}},
+		{"Disk", func(s *api.ResourcesSample) *api.ResourceStatus { return s.Disk }, func(used, limit int64) string {
+			return fmt.Sprintf("%dGi/%dGi", used/(1024*1024*1024), limit/(1024*1024*1024))
+		}},
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Resource", "Usage", "Peak", "Latest"})
+	for _, metric := range metrics {
+		var (
+			line     []rune
+			peak     = metric.status(samples[0])
+			severity api.ResourceStatusSeverity
+		)
+		for _, sample := range samples {
+			status := metric.status(sample)
+			line = append(line, rc.sparkBlock(status))
+			if status.GetUsed() > peak.GetUsed() {
+				peak = status
+			}
+			if status.GetSeverity() > severity {
+				severity = status.GetSeverity()
+			}
+		}
+		latest := metric.status(samples[len(samples)-1])
+		usage := string(line)
+		if !noColor && utils.ColorsEnabled() {
+			usage = rc.getColor(severity) + usage + "\033[0m"
+		}
+		_ = table.Append([]string{
+			metric.name,
+			usage,
+			metric.format(peak.GetUsed(), peak.GetLimit()),
+			metric.format(latest.GetUsed(), latest.GetLimit()),
+		})
+	}
+	_ = table.Render()
+}
+
+// sparkBlock returns the sparkline character of the usage relative to the limit
+func (rc resourceCmd) sparkBlock(status *api.ResourceStatus) rune {
+	if status.GetLimit() <= 0 {
+		return sparkBlocks[0]
+	}
+	i := int(float64(status.GetUsed()) / float64(status.GetLimit()) * float64(len(sparkBlocks)-1))
+	i = max(0, min(i, len(sparkBlocks)-1))
+	return sparkBlocks[i]
+}
+
+// PrintCSV outputs the resource usage history as CSV
+func (rc resourceCmd) PrintCSV(samples []*api.ResourcesSample) error {
+	w := csv.NewWriter(os.Stdout)
+	_ = w.Write([]string{"time", "cpu_millicores", "cpu_limit_millicores", "memory_bytes", "memory_limit_bytes", "disk_bytes", "disk_limit_bytes"})
+	for _, s := range samples {
+		_ = w.Write([]string{
+			time.Unix(s.Time, 0).UTC().Format(time.RFC3339),
+			strconv.FormatInt(s.Cpu.GetUsed(), 10),
+			strconv.FormatInt(s.Cpu.GetLimit(), 10),
+			strconv.FormatInt(s.Memory.GetUsed(), 10),
+			strconv.FormatInt(s.Memory.GetLimit(), 10),
+			strconv.FormatInt(s.Disk.GetUsed(), 10),
+			strconv.FormatInt(s.Disk.GetLimit(), 10),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
 // PrintTable renders resource usage in a table format
 func (rc resourceCmd) PrintTable(resources *api.ResourcesStatusResponse) {
 	// Format Cpu and Memory data
@@ -75,9 +240,45 @@ func (rc resourceCmd) PrintTable(resources *api.ResourcesStatusResponse) {
 	_ = table.Append([]string{"CPU (millicores)", cpu})
 	_ = table.Append([]string{"Memory (MiB)", memory})
 	_ = table.Append([]string{"Disk (GiB)", disk})
+	for _, pressure := range []struct {
+		name     string
+		pressure *api.ResourcePressure
+	}{
+		{"CPU pressure", resources.CpuPressure},
+		{"Memory pressure", resources.MemoryPressure},
+		{"IO pressure", resources.IoPressure},
+	} {
+		if pressure.pressure != nil {
+			_ = table.Append([]string{pressure.name, rc.formatPressure(pressure.pressure)})
+		}
+	}
+	for _, group := range resources.Groups {
+		usage := rc.formatGroup(group)
+		if !noColor && utils.ColorsEnabled() {
+			usage = rc.getColor(group.Memory.Severity) + usage + "\033[0m"
+		}
+		_ = table.Append([]string{"Cgroup " + group.Name, usage})
+	}
 	_ = table.Render()
 }
 
+// formatGroup returns a human-readable string for the usage of a cgroup
+func (rc resourceCmd) formatGroup(g *api.ResourceGroupStatus) string {
+	memory := fmt.Sprintf("%dMi", g.Memory.Used/(1024*1024))
+	if g.Memory.Limit > 0 {
+		percent := int64((float64(g.Memory.Used) / float64(g.Memory.Limit)) * 100)
+		memory = fmt.Sprintf("%dMi/%dMi (%d%%)", g.Memory.Used/(1024*1024), g.Memory.Limit/(1024*1024), percent)
+	}
+	cpuTime := time.Duration(g.CpuUsage) * time.Microsecond
+	return fmt.Sprintf("%s, cpu weight %d, cpu time %s", memory, g.CpuWeight, cpuTime.Round(time.Second))
+}
+
+// formatPressure returns a human-readable string for the share of the last 10 and 60 seconds
+// in which some or all tasks were stalled on a resource
+func (rc resourceCmd) formatPressure(p *api.ResourcePressure) string {
+	return fmt.Sprintf("some %.1f%%/%.1f%%, full %.1f%%/%.1f%% (10s/60s)", p.SomeAvg10, p.SomeAvg60, p.FullAvg10, p.FullAvg60)
+}
+
 // formatCPU returns a human-readable string for CPU usage
 func (rc resourceCmd) formatCPU(r *api.ResourcesStatusResponse) string {
 	used, limit := r.Cpu.Used, r.Cpu.Limit
@@ -0,0 +1,161 @@
+package terminal
+
+import (
+	"bytes"
+	"client/pkg/supervisor"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"supervisor/api"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// detachKey is Ctrl-], which detaches from the terminal without closing it.
+const detachKey = 0x1d
+
+var attachOpts struct {
+	ReadOnly bool
+	Token    string
+}
+
+func init() {
+	AttachCmd.Flags().BoolVarP(&attachOpts.ReadOnly, "read-only", "r", false, "Only show the output, don't forward input")
+	AttachCmd.Flags().StringVarP(&attachOpts.Token, "token", "t", "", "Share token of the terminal, e.g. from oc terminal share")
+}
+
+// AttachCmd represents the attach terminal command.
+var AttachCmd = &cobra.Command{
+	Use:   "attach <alias|name|key=value>",
+	Short: "Attach to a terminal, press Ctrl-] to detach",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+
+		// Create a supervisor client, authenticated with the share token if given
+		var client *supervisor.SupervisorClient
+		var err error
+		if attachOpts.Token != "" {
+			client, err = supervisor.NewWithToken(ctx, attachOpts.Token)
+		} else {
+			client, err = supervisor.New(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Resolve the name or selector once, s.t. we stay attached to the same terminal
+		getCtx, getCancel := context.WithTimeout(ctx, 5*time.Second)
+		info, err := client.Terminal.Get(getCtx, &api.GetTerminalRequest{Alias: args[0]})
+		getCancel()
+		if err != nil {
+			return err
+		}
+		alias := info.Alias
+
+		stream, err := client.Terminal.Listen(ctx, &api.ListenTerminalRequest{Alias: alias})
+		if err != nil {
+			return err
+		}
+
+		stdin := int(os.Stdin.Fd())
+		interactive := !attachOpts.ReadOnly && term.IsTerminal(stdin)
+		if interactive {
+			state, err := term.MakeRaw(stdin)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = term.Restore(stdin, state) }()
+
+			resize := func() {
+				cols, rows, err := term.GetSize(stdin)
+				if err != nil {
+					return
+				}
+				_, _ = client.Terminal.SetSize(ctx, &api.SetTerminalSizeRequest{
+					Alias:    alias,
+					Priority: &api.SetTerminalSizeRequest_Force{Force: true},
+					Size:     &api.TerminalSize{Cols: uint32(cols), Rows: uint32(rows)},
+				})
+			}
+			resize()
+			winch := make(chan os.Signal, 1)
+			signal.Notify(winch, syscall.SIGWINCH)
+			defer signal.Stop(winch)
+			go func() {
+				for {
+					select {
+					case <-winch:
+						resize()
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		}
+		if !attachOpts.ReadOnly {
+			go forwardInput(ctx, cancel, client, alias)
+		}
+
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			switch out := resp.Output.(type) {
+			case *api.ListenTerminalResponse_Data:
+				_, _ = os.Stdout.Write(out.Data)
+			case *api.ListenTerminalResponse_ExitCode:
+				if interactive {
+					fmt.Fprintf(os.Stderr, "\r\n[terminal exited with code %d]\r\n", out.ExitCode)
+				}
+				return nil
+			}
+		}
+	},
+}
+
+// forwardInput writes stdin to the terminal until stdin is closed. The attachment
+// is cancelled once the detach key is pressed.
+func forwardInput(ctx context.Context, cancel context.CancelFunc, client *supervisor.SupervisorClient, alias string) {
+	buf := make([]byte, 4096)
+	readOnly := false
+	for {
+		n, err := os.Stdin.Read(buf)
+		data := buf[:n]
+		detach := false
+		if i := bytes.IndexByte(data, detachKey); i != -1 {
+			data, detach = data[:i], true
+		}
+		if len(data) > 0 && !readOnly {
+			_, werr := client.Terminal.Write(ctx, &api.WriteTerminalRequest{Alias: alias, Stdin: data})
+			if status.Code(werr) == codes.PermissionDenied {
+				// a read-only share token, keep showing the output until detached
+				fmt.Fprintf(os.Stderr, "\r\n[read-only access, input is ignored]\r\n")
+				readOnly = true
+			} else if werr != nil {
+				cancel()
+				return
+			}
+		}
+		if detach {
+			cancel()
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package terminal
+
+import (
+	"client/pkg/supervisor"
+	"context"
+	"os"
+	"supervisor/api"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var backlogOpts struct {
+	From int64
+	To   int64
+}
+
+func init() {
+	BacklogCmd.Flags().Int64Var(&backlogOpts.From, "from", 0, "Offset of the first byte to read")
+	BacklogCmd.Flags().Int64Var(&backlogOpts.To, "to", 0, "Offset right after the last byte to read, 0 reads up to the end")
+}
+
+// BacklogCmd represents the command reading the output history of a terminal.
+var BacklogCmd = &cobra.Command{
+	Use:   "backlog <alias>",
+	Short: "Print a range of the output history of a terminal",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set a timeout for the request
+		ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+		defer cancel()
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Read the backlog page by page
+		from := backlogOpts.From
+		for {
+			data, err := client.Terminal.ReadBacklog(ctx, &api.ReadTerminalBacklogRequest{
+				Alias: args[0],
+				From:  from,
+				To:    backlogOpts.To,
+			})
+			if err != nil {
+				return err
+			}
+			if _, err := os.Stdout.Write(data.Data); err != nil {
+				return err
+			}
+
+			to := backlogOpts.To
+			if to == 0 || to > data.Total {
+				to = data.Total
+			}
+			if len(data.Data) == 0 || data.To >= to {
+				return nil
+			}
+			from = data.To
+		}
+	},
+}
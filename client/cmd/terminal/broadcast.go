@@ -0,0 +1,90 @@
+package terminal
+
+import (
+	"client/pkg/supervisor"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"supervisor/api"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+type broadcastCmd struct{}
+
+var broadcastOpts struct {
+	Aliases   []string
+	Selector  []string
+	NoNewline bool
+}
+
+func init() {
+	BroadcastCmd.Flags().StringArrayVarP(&broadcastOpts.Aliases, "alias", "a", nil, "Alias of a terminal to write to (repeatable)")
+	BroadcastCmd.Flags().StringArrayVarP(&broadcastOpts.Selector, "selector", "l", nil, "Write to terminals with this annotation, KEY=VALUE (repeatable)")
+	BroadcastCmd.Flags().BoolVarP(&broadcastOpts.NoNewline, "no-newline", "n", false, "Do not append a newline to the input")
+	BroadcastCmd.Flags().BoolVarP(&jsonFormat, "json", "j", false, "Output in JSON format")
+}
+
+// BroadcastCmd represents the command writing the same input to several terminals.
+var BroadcastCmd = &cobra.Command{
+	Use:   "broadcast <input>...",
+	Short: "Write the same input to several terminals",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		selector, err := parseKeyValues(broadcastOpts.Selector)
+		if err != nil {
+			return err
+		}
+		if len(broadcastOpts.Aliases) == 0 && len(selector) == 0 {
+			return fmt.Errorf("either --alias or --selector is required")
+		}
+		stdin := strings.Join(args, " ")
+		if !broadcastOpts.NoNewline {
+			stdin += "\n"
+		}
+
+		// Set a timeout for the request
+		ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Second)
+		defer cancel()
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Broadcast the input
+		data, err := client.Terminal.Broadcast(ctx, &api.BroadcastTerminalRequest{
+			Aliases:  broadcastOpts.Aliases,
+			Selector: selector,
+			Stdin:    []byte(stdin),
+		})
+		if err != nil {
+			return err
+		}
+
+		// Output in JSON or table format
+		if jsonFormat {
+			content, _ := json.Marshal(data)
+			fmt.Println(string(content))
+		} else {
+			broadcastCmd{}.PrintTable(data)
+		}
+		return nil
+	},
+}
+
+// PrintTable renders the broadcast results in a table format
+func (bc broadcastCmd) PrintTable(resources *api.BroadcastTerminalResponse) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Alias", "Bytes", "Error"})
+	for _, res := range resources.Results {
+		_ = table.Append(res.Alias, res.BytesWritten, res.Error)
+	}
+	_ = table.Render()
+}
@@ -0,0 +1,61 @@
+package terminal
+
+import (
+	"client/pkg/supervisor"
+	"context"
+	"fmt"
+	"os"
+	"supervisor/api"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var exportOpts struct {
+	Format string
+	Output string
+}
+
+func init() {
+	ExportCmd.Flags().StringVarP(&exportOpts.Format, "format", "f", "text", "Output format: text, html or cast")
+	ExportCmd.Flags().StringVarP(&exportOpts.Output, "output", "o", "", "Write to file instead of stdout")
+}
+
+// ExportCmd represents the export terminal command.
+var ExportCmd = &cobra.Command{
+	Use:   "export <alias>",
+	Short: "Export the output of a terminal as text, HTML or asciicast",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, ok := api.TerminalExportFormat_value[exportOpts.Format]
+		if !ok {
+			return fmt.Errorf("unsupported format %q, expected text, html or cast", exportOpts.Format)
+		}
+
+		// Set a timeout for the request
+		ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Second)
+		defer cancel()
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Export the terminal backlog
+		data, err := client.Terminal.Export(ctx, &api.ExportTerminalRequest{
+			Alias:  args[0],
+			Format: api.TerminalExportFormat(format),
+		})
+		if err != nil {
+			return err
+		}
+
+		if exportOpts.Output != "" {
+			return os.WriteFile(exportOpts.Output, data.Content, 0o644)
+		}
+		_, err = os.Stdout.Write(data.Content)
+		return err
+	},
+}
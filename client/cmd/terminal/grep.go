@@ -0,0 +1,83 @@
+package terminal
+
+import (
+	"client/pkg/supervisor"
+	"context"
+	"encoding/json"
+	"fmt"
+	"supervisor/api"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var grepOpts struct {
+	Alias      string
+	Context    uint32
+	IgnoreCase bool
+	MaxMatches uint32
+}
+
+func init() {
+	GrepCmd.Flags().StringVarP(&grepOpts.Alias, "alias", "a", "", "Only search the terminal with this alias")
+	GrepCmd.Flags().Uint32VarP(&grepOpts.Context, "context", "C", 0, "Print lines of context around each match")
+	GrepCmd.Flags().BoolVarP(&grepOpts.IgnoreCase, "ignore-case", "i", false, "Ignore case distinctions")
+	GrepCmd.Flags().Uint32VarP(&grepOpts.MaxMatches, "max-count", "m", 0, "Stop after this many matches")
+	GrepCmd.Flags().BoolVarP(&jsonFormat, "json", "j", false, "Output in JSON format")
+}
+
+// GrepCmd represents the command searching the output of terminals.
+var GrepCmd = &cobra.Command{
+	Use:   "grep <pattern>",
+	Short: "Search the output of all terminals for a regular expression",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set a timeout for the request
+		ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+		defer cancel()
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Search the terminals
+		data, err := client.Terminal.Search(ctx, &api.SearchTerminalsRequest{
+			Alias:      grepOpts.Alias,
+			Pattern:    args[0],
+			Context:    grepOpts.Context,
+			IgnoreCase: grepOpts.IgnoreCase,
+			MaxMatches: grepOpts.MaxMatches,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Output in JSON format if requested
+		if jsonFormat {
+			content, _ := json.Marshal(data)
+			fmt.Println(string(content))
+			return nil
+		}
+
+		// Print matches like grep, prefixed with the alias and the offset to resume listening at
+		for i, match := range data.Matches {
+			if grepOpts.Context > 0 && i > 0 {
+				fmt.Println("--")
+			}
+			for _, line := range match.Before {
+				fmt.Printf("%s-%s\n", match.Alias, line)
+			}
+			fmt.Printf("%s:%d:%s\n", match.Alias, match.Offset, match.Line)
+			for _, line := range match.After {
+				fmt.Printf("%s-%s\n", match.Alias, line)
+			}
+		}
+		if data.Truncated {
+			fmt.Println("(more matches omitted)")
+		}
+		return nil
+	},
+}
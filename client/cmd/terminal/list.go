@@ -0,0 +1,82 @@
+package terminal
+
+import (
+	"client/pkg/supervisor"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"supervisor/api"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+type listCmd struct{}
+
+func init() {
+	ListCmd.Flags().BoolVarP(&jsonFormat, "json", "j", false, "Output in JSON format")
+}
+
+// ListCmd represents the list terminals command.
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List open terminals",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set a timeout for the request
+		ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Second)
+		defer cancel()
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Fetch terminals
+		data, err := client.Terminal.List(ctx, &api.ListTerminalsRequest{})
+		if err != nil {
+			return err
+		}
+
+		// Output in JSON or table format
+		if jsonFormat {
+			content, _ := json.Marshal(data)
+			fmt.Println(string(content))
+		} else {
+			listCmd{}.PrintTable(data)
+		}
+		return nil
+	},
+}
+
+// PrintTable renders terminals in a table format
+func (lc listCmd) PrintTable(resources *api.ListTerminalsResponse) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Alias", "Name", "Title", "Pid", "Workdir", "Viewers"})
+	for _, term := range resources.Terminals {
+		var viewers []string
+		for _, viewer := range term.Viewers {
+			viewers = append(viewers, fmt.Sprintf("%s (%s)", viewer.Name, viewer.Scope))
+		}
+		_ = table.Append(term.Alias, term.Name, term.Title, term.Pid, term.CurrentWorkdir, strings.Join(viewers, ", "))
+	}
+	_ = table.Render()
+
+	if limits := resources.Limits; limits != nil {
+		var res []string
+		if limits.MaxTerminals > 0 {
+			res = append(res, fmt.Sprintf("%d/%d terminals", len(resources.Terminals), limits.MaxTerminals))
+		}
+		if limits.AnnotationKey != "" && limits.MaxPerAnnotation > 0 {
+			res = append(res, fmt.Sprintf("%d per %s", limits.MaxPerAnnotation, limits.AnnotationKey))
+		}
+		if len(res) > 0 {
+			fmt.Printf("Limits: %s\n", strings.Join(res, ", "))
+		}
+	}
+}
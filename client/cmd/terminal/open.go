@@ -0,0 +1,101 @@
+package terminal
+
+import (
+	"client/pkg/supervisor"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"supervisor/api"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var openOpts struct {
+	Name        string
+	Profile     string
+	Workdir     string
+	Shell       string
+	Env         []string
+	Annotations []string
+	Record      bool
+	IdleTimeout time.Duration
+}
+
+func init() {
+	OpenCmd.Flags().StringVarP(&openOpts.Name, "name", "n", "", "Unique name the terminal can be referred to by instead of its alias")
+	OpenCmd.Flags().StringVarP(&openOpts.Profile, "profile", "p", "", "Name of the terminal profile defined in .opencoder.yml")
+	OpenCmd.Flags().StringVarP(&openOpts.Workdir, "workdir", "w", "", "Working directory of the terminal")
+	OpenCmd.Flags().StringVarP(&openOpts.Shell, "shell", "s", "", "Shell to start in the terminal")
+	OpenCmd.Flags().StringArrayVarP(&openOpts.Env, "env", "e", nil, "Environment variable in KEY=VALUE format (repeatable)")
+	OpenCmd.Flags().StringArrayVarP(&openOpts.Annotations, "annotation", "a", nil, "Annotation in KEY=VALUE format (repeatable)")
+	OpenCmd.Flags().DurationVarP(&openOpts.IdleTimeout, "idle-timeout", "i", 0, "Close the terminal once it has been idle for that long, e.g. 30m")
+	OpenCmd.Flags().BoolVarP(&openOpts.Record, "record", "r", false, "Record the terminal session into an asciicast v2 file")
+	OpenCmd.Flags().BoolVarP(&jsonFormat, "json", "j", false, "Output in JSON format")
+}
+
+// OpenCmd represents the open terminal command.
+var OpenCmd = &cobra.Command{
+	Use:   "open [-- shell args]",
+	Short: "Open a new terminal and print its alias",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env, err := parseKeyValues(openOpts.Env)
+		if err != nil {
+			return err
+		}
+		annotations, err := parseKeyValues(openOpts.Annotations)
+		if err != nil {
+			return err
+		}
+
+		// Set a timeout for the request
+		ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Second)
+		defer cancel()
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Open the terminal
+		data, err := client.Terminal.Open(ctx, &api.OpenTerminalRequest{
+			Name:        openOpts.Name,
+			Workdir:     openOpts.Workdir,
+			Env:         env,
+			Annotations: annotations,
+			Shell:       openOpts.Shell,
+			ShellArgs:   args,
+			Profile:     openOpts.Profile,
+			Record:      openOpts.Record,
+			IdleTimeout: uint32(openOpts.IdleTimeout / time.Second),
+		})
+		if err != nil {
+			return err
+		}
+
+		// Output in JSON or plain format
+		if jsonFormat {
+			content, _ := json.Marshal(data.Terminal)
+			fmt.Println(string(content))
+		} else {
+			fmt.Println(data.Terminal.Alias)
+		}
+		return nil
+	},
+}
+
+// parseKeyValues converts a list of KEY=VALUE pairs into a map.
+func parseKeyValues(pairs []string) (map[string]string, error) {
+	res := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid value %q, expected KEY=VALUE", pair)
+		}
+		res[key] = value
+	}
+	return res, nil
+}
@@ -0,0 +1,50 @@
+package terminal
+
+import (
+	"client/pkg/supervisor"
+	"context"
+	"fmt"
+	"supervisor/api"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var stopRecording bool
+
+func init() {
+	RecordCmd.Flags().BoolVarP(&stopRecording, "stop", "", false, "Stop recording the terminal")
+}
+
+// RecordCmd represents the record terminal command.
+var RecordCmd = &cobra.Command{
+	Use:   "record <alias>",
+	Short: "Record a terminal session into an asciicast v2 file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set a timeout for the request
+		ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Second)
+		defer cancel()
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Toggle the recording
+		data, err := client.Terminal.SetRecording(ctx, &api.SetTerminalRecordingRequest{
+			Alias:   args[0],
+			Enabled: !stopRecording,
+		})
+		if err != nil {
+			return err
+		}
+
+		if data.Path != "" {
+			fmt.Println(data.Path)
+		}
+		return nil
+	},
+}
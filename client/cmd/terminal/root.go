@@ -0,0 +1,33 @@
+package terminal
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var jsonFormat bool
+
+// Cmd represents the "terminal" command used to interact with workspace terminals.
+var Cmd = &cobra.Command{
+	Use:   "terminal",
+	Short: "Interact with workspace terminals",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return cmd.Help()
+		}
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(OpenCmd)
+	Cmd.AddCommand(ListCmd)
+	Cmd.AddCommand(AttachCmd)
+	Cmd.AddCommand(ExportCmd)
+	Cmd.AddCommand(RecordCmd)
+	Cmd.AddCommand(ShareCmd)
+	Cmd.AddCommand(UnshareCmd)
+	Cmd.AddCommand(BacklogCmd)
+	Cmd.AddCommand(GrepCmd)
+	Cmd.AddCommand(BroadcastCmd)
+}
@@ -0,0 +1,95 @@
+package terminal
+
+import (
+	"client/pkg/supervisor"
+	"context"
+	"encoding/json"
+	"fmt"
+	"supervisor/api"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var shareOpts struct {
+	Write bool
+	TTL   time.Duration
+	Name  string
+}
+
+func init() {
+	ShareCmd.Flags().BoolVarP(&shareOpts.Write, "write", "w", false, "Allow writing to the terminal")
+	ShareCmd.Flags().DurationVarP(&shareOpts.TTL, "ttl", "t", time.Hour, "Lifetime of the token")
+	ShareCmd.Flags().StringVarP(&shareOpts.Name, "name", "n", "", "Name of the person the terminal is shared with")
+	ShareCmd.Flags().BoolVarP(&jsonFormat, "json", "j", false, "Output in JSON format")
+}
+
+// ShareCmd represents the share terminal command.
+var ShareCmd = &cobra.Command{
+	Use:   "share <alias>",
+	Short: "Share a terminal and print the access token",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scope := api.TerminalAccessScope_read_only
+		if shareOpts.Write {
+			scope = api.TerminalAccessScope_read_write
+		}
+
+		// Set a timeout for the request
+		ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Second)
+		defer cancel()
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Create the share
+		data, err := client.Terminal.ShareTerminal(ctx, &api.ShareTerminalRequest{
+			Alias:      args[0],
+			Scope:      scope,
+			TtlSeconds: int64(shareOpts.TTL.Seconds()),
+			Name:       shareOpts.Name,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Output in JSON or plain format
+		if jsonFormat {
+			content, _ := json.Marshal(data)
+			fmt.Println(string(content))
+		} else {
+			fmt.Println(data.Token)
+		}
+		return nil
+	},
+}
+
+// UnshareCmd represents the command revoking a terminal share.
+var UnshareCmd = &cobra.Command{
+	Use:   "unshare <alias> <token>",
+	Short: "Revoke a terminal share and disconnect its viewers",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Set a timeout for the request
+		ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Second)
+		defer cancel()
+
+		// Create a supervisor client
+		client, err := supervisor.New(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// Revoke the share
+		_, err = client.Terminal.RevokeTerminalShare(ctx, &api.RevokeTerminalShareRequest{
+			Alias: args[0],
+			Token: args[1],
+		})
+		return err
+	},
+}
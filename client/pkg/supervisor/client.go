@@ -17,31 +17,63 @@ type SupervisorClient struct {
 	closeOnce sync.Once
 
 	// Service clients
-	Package api.PackageServiceClient
-	System  api.SystemServiceClient
-	Utility api.UtilityServiceClient
+	Clipboard api.ClipboardServiceClient
+	Editor    api.EditorServiceClient
+	Package   api.PackageServiceClient
+	System    api.SystemServiceClient
+	Terminal  api.TerminalServiceClient
+	Utility   api.UtilityServiceClient
 }
 
-// New creates a new SupervisorClient using the supervisor address.
+// New creates a new SupervisorClient using the supervisor address, authenticated with the
+// owner token supervisor passed in the environment or wrote for the workspace user.
 func New(ctx context.Context) (*SupervisorClient, error) {
+	// clients without access to the token, e.g. of other users, can only use shared terminals
+	// if supervisor requires the owner token
+	return NewWithToken(ctx, util.GetOwnerToken())
+}
+
+// NewWithToken creates a new SupervisorClient passing token with every call, e.g. a share
+// token returned by ShareTerminal.
+func NewWithToken(ctx context.Context, token string) (*SupervisorClient, error) {
 	address := util.GetSupervisorAddress()
 
-	conn, err := grpc.NewClient(
-		address,
+	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	}
+	if token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials(token)))
+	}
+	conn, err := grpc.NewClient(address, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client for %s: %w", address, err)
 	}
 
 	return &SupervisorClient{
-		conn:    conn,
-		Package: api.NewPackageServiceClient(conn),
-		System:  api.NewSystemServiceClient(conn),
-		Utility: api.NewUtilityServiceClient(conn),
+		conn:      conn,
+		Clipboard: api.NewClipboardServiceClient(conn),
+		Editor:    api.NewEditorServiceClient(conn),
+		Package:   api.NewPackageServiceClient(conn),
+		System:    api.NewSystemServiceClient(conn),
+		Terminal:  api.NewTerminalServiceClient(conn),
+		Utility:   api.NewUtilityServiceClient(conn),
 	}, nil
 }
 
+// tokenCredentials passes a token as metadata of every call.
+type tokenCredentials string
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{util.OwnerTokenMetadataKey: string(t)}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials. Supervisor is reached
+// on the loopback interface or through a tunnel without TLS.
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
 // Close terminates the underlying gRPC connection to the Supervisor service.
 func (c *SupervisorClient) Close() {
 	c.closeOnce.Do(func() {
@@ -3,6 +3,7 @@ package util
 import (
 	"fmt"
 	"os"
+	"strings"
 )
 
 const (
@@ -20,3 +21,35 @@ func GetSupervisorAddress() string {
 
 	return addr
 }
+
+const (
+	// OwnerTokenMetadataKey is the gRPC metadata key of the token which identifies the
+	// workspace owner, or of a share token granting access to a single terminal.
+	OwnerTokenMetadataKey = "supervisor-token"
+	// OwnerTokenEnv is the environment variable supervisor passes the owner token to the
+	// editor and terminals in.
+	OwnerTokenEnv = "SUPERVISOR_TOKEN"
+	// defaultOwnerTokenLocation is where supervisor writes the owner token by default.
+	defaultOwnerTokenLocation = "/tmp/opencoder/supervisor-token"
+)
+
+// GetOwnerTokenLocation returns the path of the file supervisor writes the owner token to.
+// Custom values can be defined using the environment variable SUPERVISOR_TOKEN_FILE.
+func GetOwnerTokenLocation() string {
+	loc := os.Getenv("SUPERVISOR_TOKEN_FILE")
+	if loc == "" {
+		loc = defaultOwnerTokenLocation
+	}
+
+	return loc
+}
+
+// GetOwnerToken returns the owner token from the environment variable SUPERVISOR_TOKEN,
+// or from the file supervisor writes it to. It is empty if neither is accessible.
+func GetOwnerToken() string {
+	if token := os.Getenv(OwnerTokenEnv); token != "" {
+		return token
+	}
+	token, _ := os.ReadFile(GetOwnerTokenLocation())
+	return strings.TrimSpace(string(token))
+}
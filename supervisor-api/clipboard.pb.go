@@ -0,0 +1,373 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.31.1
+// source: clipboard.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ClipboardContent struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Content []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	// source describes who set the content, e.g. terminal/<alias> if a program
+	// running in a terminal set it through OSC 52
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	// updated_at is the unix timestamp in seconds the content was set at
+	UpdatedAt     int64 `protobuf:"varint,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClipboardContent) Reset() {
+	*x = ClipboardContent{}
+	mi := &file_clipboard_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClipboardContent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClipboardContent) ProtoMessage() {}
+
+func (x *ClipboardContent) ProtoReflect() protoreflect.Message {
+	mi := &file_clipboard_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClipboardContent.ProtoReflect.Descriptor instead.
+func (*ClipboardContent) Descriptor() ([]byte, []int) {
+	return file_clipboard_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ClipboardContent) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *ClipboardContent) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ClipboardContent) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type CopyClipboardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Content       []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyClipboardRequest) Reset() {
+	*x = CopyClipboardRequest{}
+	mi := &file_clipboard_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyClipboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyClipboardRequest) ProtoMessage() {}
+
+func (x *CopyClipboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clipboard_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyClipboardRequest.ProtoReflect.Descriptor instead.
+func (*CopyClipboardRequest) Descriptor() ([]byte, []int) {
+	return file_clipboard_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CopyClipboardRequest) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *CopyClipboardRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type CopyClipboardResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyClipboardResponse) Reset() {
+	*x = CopyClipboardResponse{}
+	mi := &file_clipboard_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyClipboardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyClipboardResponse) ProtoMessage() {}
+
+func (x *CopyClipboardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_clipboard_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyClipboardResponse.ProtoReflect.Descriptor instead.
+func (*CopyClipboardResponse) Descriptor() ([]byte, []int) {
+	return file_clipboard_proto_rawDescGZIP(), []int{2}
+}
+
+type PasteClipboardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PasteClipboardRequest) Reset() {
+	*x = PasteClipboardRequest{}
+	mi := &file_clipboard_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PasteClipboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PasteClipboardRequest) ProtoMessage() {}
+
+func (x *PasteClipboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clipboard_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PasteClipboardRequest.ProtoReflect.Descriptor instead.
+func (*PasteClipboardRequest) Descriptor() ([]byte, []int) {
+	return file_clipboard_proto_rawDescGZIP(), []int{3}
+}
+
+type PasteClipboardResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clipboard     *ClipboardContent      `protobuf:"bytes,1,opt,name=clipboard,proto3" json:"clipboard,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PasteClipboardResponse) Reset() {
+	*x = PasteClipboardResponse{}
+	mi := &file_clipboard_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PasteClipboardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PasteClipboardResponse) ProtoMessage() {}
+
+func (x *PasteClipboardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_clipboard_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PasteClipboardResponse.ProtoReflect.Descriptor instead.
+func (*PasteClipboardResponse) Descriptor() ([]byte, []int) {
+	return file_clipboard_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PasteClipboardResponse) GetClipboard() *ClipboardContent {
+	if x != nil {
+		return x.Clipboard
+	}
+	return nil
+}
+
+type WatchClipboardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchClipboardRequest) Reset() {
+	*x = WatchClipboardRequest{}
+	mi := &file_clipboard_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchClipboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchClipboardRequest) ProtoMessage() {}
+
+func (x *WatchClipboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_clipboard_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchClipboardRequest.ProtoReflect.Descriptor instead.
+func (*WatchClipboardRequest) Descriptor() ([]byte, []int) {
+	return file_clipboard_proto_rawDescGZIP(), []int{5}
+}
+
+var File_clipboard_proto protoreflect.FileDescriptor
+
+const file_clipboard_proto_rawDesc = "" +
+	"\n" +
+	"\x0fclipboard.proto\x12\n" +
+	"supervisor\"c\n" +
+	"\x10ClipboardContent\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\fR\acontent\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\x03R\tupdatedAt\"H\n" +
+	"\x14CopyClipboardRequest\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\fR\acontent\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\"\x17\n" +
+	"\x15CopyClipboardResponse\"\x17\n" +
+	"\x15PasteClipboardRequest\"T\n" +
+	"\x16PasteClipboardResponse\x12:\n" +
+	"\tclipboard\x18\x01 \x01(\v2\x1c.supervisor.ClipboardContentR\tclipboard\"\x17\n" +
+	"\x15WatchClipboardRequest2\x81\x02\n" +
+	"\x10ClipboardService\x12M\n" +
+	"\x04Copy\x12 .supervisor.CopyClipboardRequest\x1a!.supervisor.CopyClipboardResponse\"\x00\x12P\n" +
+	"\x05Paste\x12!.supervisor.PasteClipboardRequest\x1a\".supervisor.PasteClipboardResponse\"\x00\x12L\n" +
+	"\x05Watch\x12!.supervisor.WatchClipboardRequest\x1a\x1c.supervisor.ClipboardContent\"\x000\x01B\x10Z\x0esupervisor/apib\x06proto3"
+
+var (
+	file_clipboard_proto_rawDescOnce sync.Once
+	file_clipboard_proto_rawDescData []byte
+)
+
+func file_clipboard_proto_rawDescGZIP() []byte {
+	file_clipboard_proto_rawDescOnce.Do(func() {
+		file_clipboard_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_clipboard_proto_rawDesc), len(file_clipboard_proto_rawDesc)))
+	})
+	return file_clipboard_proto_rawDescData
+}
+
+var file_clipboard_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_clipboard_proto_goTypes = []any{
+	(*ClipboardContent)(nil),       // 0: supervisor.ClipboardContent
+	(*CopyClipboardRequest)(nil),   // 1: supervisor.CopyClipboardRequest
+	(*CopyClipboardResponse)(nil),  // 2: supervisor.CopyClipboardResponse
+	(*PasteClipboardRequest)(nil),  // 3: supervisor.PasteClipboardRequest
+	(*PasteClipboardResponse)(nil), // 4: supervisor.PasteClipboardResponse
+	(*WatchClipboardRequest)(nil),  // 5: supervisor.WatchClipboardRequest
+}
+var file_clipboard_proto_depIdxs = []int32{
+	0, // 0: supervisor.PasteClipboardResponse.clipboard:type_name -> supervisor.ClipboardContent
+	1, // 1: supervisor.ClipboardService.Copy:input_type -> supervisor.CopyClipboardRequest
+	3, // 2: supervisor.ClipboardService.Paste:input_type -> supervisor.PasteClipboardRequest
+	5, // 3: supervisor.ClipboardService.Watch:input_type -> supervisor.WatchClipboardRequest
+	2, // 4: supervisor.ClipboardService.Copy:output_type -> supervisor.CopyClipboardResponse
+	4, // 5: supervisor.ClipboardService.Paste:output_type -> supervisor.PasteClipboardResponse
+	0, // 6: supervisor.ClipboardService.Watch:output_type -> supervisor.ClipboardContent
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_clipboard_proto_init() }
+func file_clipboard_proto_init() {
+	if File_clipboard_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_clipboard_proto_rawDesc), len(file_clipboard_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_clipboard_proto_goTypes,
+		DependencyIndexes: file_clipboard_proto_depIdxs,
+		MessageInfos:      file_clipboard_proto_msgTypes,
+	}.Build()
+	File_clipboard_proto = out.File
+	file_clipboard_proto_goTypes = nil
+	file_clipboard_proto_depIdxs = nil
+}
@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.31.1
+// source: clipboard.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ClipboardService_Copy_FullMethodName  = "/supervisor.ClipboardService/Copy"
+	ClipboardService_Paste_FullMethodName = "/supervisor.ClipboardService/Paste"
+	ClipboardService_Watch_FullMethodName = "/supervisor.ClipboardService/Watch"
+)
+
+// ClipboardServiceClient is the client API for ClipboardService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ClipboardServiceClient interface {
+	// Copy sets the content of the workspace clipboard
+	Copy(ctx context.Context, in *CopyClipboardRequest, opts ...grpc.CallOption) (*CopyClipboardResponse, error)
+	// Paste returns the content of the workspace clipboard
+	Paste(ctx context.Context, in *PasteClipboardRequest, opts ...grpc.CallOption) (*PasteClipboardResponse, error)
+	// Watch streams the content of the workspace clipboard, starting with the
+	// current content, s.t. clients can keep their local clipboard in sync
+	Watch(ctx context.Context, in *WatchClipboardRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ClipboardContent], error)
+}
+
+type clipboardServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewClipboardServiceClient(cc grpc.ClientConnInterface) ClipboardServiceClient {
+	return &clipboardServiceClient{cc}
+}
+
+func (c *clipboardServiceClient) Copy(ctx context.Context, in *CopyClipboardRequest, opts ...grpc.CallOption) (*CopyClipboardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CopyClipboardResponse)
+	err := c.cc.Invoke(ctx, ClipboardService_Copy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clipboardServiceClient) Paste(ctx context.Context, in *PasteClipboardRequest, opts ...grpc.CallOption) (*PasteClipboardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PasteClipboardResponse)
+	err := c.cc.Invoke(ctx, ClipboardService_Paste_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clipboardServiceClient) Watch(ctx context.Context, in *WatchClipboardRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ClipboardContent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ClipboardService_ServiceDesc.Streams[0], ClipboardService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchClipboardRequest, ClipboardContent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ClipboardService_WatchClient = grpc.ServerStreamingClient[ClipboardContent]
+
+// ClipboardServiceServer is the server API for ClipboardService service.
+// All implementations must embed UnimplementedClipboardServiceServer
+// for forward compatibility.
+type ClipboardServiceServer interface {
+	// Copy sets the content of the workspace clipboard
+	Copy(context.Context, *CopyClipboardRequest) (*CopyClipboardResponse, error)
+	// Paste returns the content of the workspace clipboard
+	Paste(context.Context, *PasteClipboardRequest) (*PasteClipboardResponse, error)
+	// Watch streams the content of the workspace clipboard, starting with the
+	// current content, s.t. clients can keep their local clipboard in sync
+	Watch(*WatchClipboardRequest, grpc.ServerStreamingServer[ClipboardContent]) error
+	mustEmbedUnimplementedClipboardServiceServer()
+}
+
+// UnimplementedClipboardServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedClipboardServiceServer struct{}
+
+func (UnimplementedClipboardServiceServer) Copy(context.Context, *CopyClipboardRequest) (*CopyClipboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Copy not implemented")
+}
+func (UnimplementedClipboardServiceServer) Paste(context.Context, *PasteClipboardRequest) (*PasteClipboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Paste not implemented")
+}
+func (UnimplementedClipboardServiceServer) Watch(*WatchClipboardRequest, grpc.ServerStreamingServer[ClipboardContent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedClipboardServiceServer) mustEmbedUnimplementedClipboardServiceServer() {}
+func (UnimplementedClipboardServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeClipboardServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ClipboardServiceServer will
+// result in compilation errors.
+type UnsafeClipboardServiceServer interface {
+	mustEmbedUnimplementedClipboardServiceServer()
+}
+
+func RegisterClipboardServiceServer(s grpc.ServiceRegistrar, srv ClipboardServiceServer) {
+	// If the following call pancis, it indicates UnimplementedClipboardServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ClipboardService_ServiceDesc, srv)
+}
+
+func _ClipboardService_Copy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CopyClipboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClipboardServiceServer).Copy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClipboardService_Copy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClipboardServiceServer).Copy(ctx, req.(*CopyClipboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClipboardService_Paste_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PasteClipboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClipboardServiceServer).Paste(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClipboardService_Paste_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClipboardServiceServer).Paste(ctx, req.(*PasteClipboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClipboardService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchClipboardRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ClipboardServiceServer).Watch(m, &grpc.GenericServerStream[WatchClipboardRequest, ClipboardContent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ClipboardService_WatchServer = grpc.ServerStreamingServer[ClipboardContent]
+
+// ClipboardService_ServiceDesc is the grpc.ServiceDesc for ClipboardService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ClipboardService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "supervisor.ClipboardService",
+	HandlerType: (*ClipboardServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Copy",
+			Handler:    _ClipboardService_Copy_Handler,
+		},
+		{
+			MethodName: "Paste",
+			Handler:    _ClipboardService_Paste_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _ClipboardService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "clipboard.proto",
+}
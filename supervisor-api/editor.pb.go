@@ -0,0 +1,875 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.31.1
+// source: editor.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EditorPhase int32
+
+const (
+	// The editor process runs but is not ready yet
+	EditorPhase_starting EditorPhase = 0
+	// The editor is ready
+	EditorPhase_running EditorPhase = 1
+	// The editor stopped and waits to be restarted
+	EditorPhase_backoff EditorPhase = 2
+	// The editor stopped too often and is not restarted anymore
+	EditorPhase_failed EditorPhase = 3
+	// The editor has been stopped
+	EditorPhase_stopped EditorPhase = 4
+)
+
+// Enum value maps for EditorPhase.
+var (
+	EditorPhase_name = map[int32]string{
+		0: "starting",
+		1: "running",
+		2: "backoff",
+		3: "failed",
+		4: "stopped",
+	}
+	EditorPhase_value = map[string]int32{
+		"starting": 0,
+		"running":  1,
+		"backoff":  2,
+		"failed":   3,
+		"stopped":  4,
+	}
+)
+
+func (x EditorPhase) Enum() *EditorPhase {
+	p := new(EditorPhase)
+	*p = x
+	return p
+}
+
+func (x EditorPhase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EditorPhase) Descriptor() protoreflect.EnumDescriptor {
+	return file_editor_proto_enumTypes[0].Descriptor()
+}
+
+func (EditorPhase) Type() protoreflect.EnumType {
+	return &file_editor_proto_enumTypes[0]
+}
+
+func (x EditorPhase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EditorPhase.Descriptor instead.
+func (EditorPhase) EnumDescriptor() ([]byte, []int) {
+	return file_editor_proto_rawDescGZIP(), []int{0}
+}
+
+type ExtensionState int32
+
+const (
+	// The extension waits to be installed
+	ExtensionState_queued ExtensionState = 0
+	// The extension is being downloaded or installed
+	ExtensionState_installing ExtensionState = 1
+	// The extension has been installed
+	ExtensionState_installed ExtensionState = 2
+	// The extension was installed already
+	ExtensionState_skipped ExtensionState = 3
+	// The extension could not be installed
+	ExtensionState_install_failed ExtensionState = 4
+)
+
+// Enum value maps for ExtensionState.
+var (
+	ExtensionState_name = map[int32]string{
+		0: "queued",
+		1: "installing",
+		2: "installed",
+		3: "skipped",
+		4: "install_failed",
+	}
+	ExtensionState_value = map[string]int32{
+		"queued":         0,
+		"installing":     1,
+		"installed":      2,
+		"skipped":        3,
+		"install_failed": 4,
+	}
+)
+
+func (x ExtensionState) Enum() *ExtensionState {
+	p := new(ExtensionState)
+	*p = x
+	return p
+}
+
+func (x ExtensionState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExtensionState) Descriptor() protoreflect.EnumDescriptor {
+	return file_editor_proto_enumTypes[1].Descriptor()
+}
+
+func (ExtensionState) Type() protoreflect.EnumType {
+	return &file_editor_proto_enumTypes[1]
+}
+
+func (x ExtensionState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExtensionState.Descriptor instead.
+func (ExtensionState) EnumDescriptor() ([]byte, []int) {
+	return file_editor_proto_rawDescGZIP(), []int{1}
+}
+
+type EditorLogStream int32
+
+const (
+	EditorLogStream_stdout EditorLogStream = 0
+	EditorLogStream_stderr EditorLogStream = 1
+)
+
+// Enum value maps for EditorLogStream.
+var (
+	EditorLogStream_name = map[int32]string{
+		0: "stdout",
+		1: "stderr",
+	}
+	EditorLogStream_value = map[string]int32{
+		"stdout": 0,
+		"stderr": 1,
+	}
+)
+
+func (x EditorLogStream) Enum() *EditorLogStream {
+	p := new(EditorLogStream)
+	*p = x
+	return p
+}
+
+func (x EditorLogStream) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EditorLogStream) Descriptor() protoreflect.EnumDescriptor {
+	return file_editor_proto_enumTypes[2].Descriptor()
+}
+
+func (EditorLogStream) Type() protoreflect.EnumType {
+	return &file_editor_proto_enumTypes[2]
+}
+
+func (x EditorLogStream) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EditorLogStream.Descriptor instead.
+func (EditorLogStream) EnumDescriptor() ([]byte, []int) {
+	return file_editor_proto_rawDescGZIP(), []int{2}
+}
+
+type EditorStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name of the editor from the editor config
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// version of the editor from the editor config
+	Version string      `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Phase   EditorPhase `protobuf:"varint,3,opt,name=phase,proto3,enum=supervisor.EditorPhase" json:"phase,omitempty"`
+	Ready   bool        `protobuf:"varint,4,opt,name=ready,proto3" json:"ready,omitempty"`
+	// pid of the editor process, 0 if it doesn't run
+	Pid int64 `protobuf:"varint,5,opt,name=pid,proto3" json:"pid,omitempty"`
+	// restarts is the number of times the editor has been restarted
+	Restarts uint32 `protobuf:"varint,6,opt,name=restarts,proto3" json:"restarts,omitempty"`
+	// uptime is the number of seconds the editor process runs for
+	Uptime int64 `protobuf:"varint,7,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	// reason explains why the editor last stopped, e.g. "exited with code 1"
+	Reason string `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	// since is the unix timestamp in seconds the editor entered its phase at
+	Since int64 `protobuf:"varint,9,opt,name=since,proto3" json:"since,omitempty"`
+	// next_restart is the unix timestamp in seconds the editor is restarted at in the backoff phase
+	NextRestart int64 `protobuf:"varint,10,opt,name=next_restart,json=nextRestart,proto3" json:"next_restart,omitempty"`
+	// extensions reports the installation of the extensions of the runtime config
+	Extensions    []*EditorExtension `protobuf:"bytes,11,rep,name=extensions,proto3" json:"extensions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditorStatus) Reset() {
+	*x = EditorStatus{}
+	mi := &file_editor_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditorStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditorStatus) ProtoMessage() {}
+
+func (x *EditorStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_editor_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditorStatus.ProtoReflect.Descriptor instead.
+func (*EditorStatus) Descriptor() ([]byte, []int) {
+	return file_editor_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EditorStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EditorStatus) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *EditorStatus) GetPhase() EditorPhase {
+	if x != nil {
+		return x.Phase
+	}
+	return EditorPhase_starting
+}
+
+func (x *EditorStatus) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *EditorStatus) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *EditorStatus) GetRestarts() uint32 {
+	if x != nil {
+		return x.Restarts
+	}
+	return 0
+}
+
+func (x *EditorStatus) GetUptime() int64 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+func (x *EditorStatus) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *EditorStatus) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *EditorStatus) GetNextRestart() int64 {
+	if x != nil {
+		return x.NextRestart
+	}
+	return 0
+}
+
+func (x *EditorStatus) GetExtensions() []*EditorExtension {
+	if x != nil {
+		return x.Extensions
+	}
+	return nil
+}
+
+type EditorExtension struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ref is the extension as listed in the runtime config
+	Ref string `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	// id is the extension ID publisher.name
+	Id    string         `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	State ExtensionState `protobuf:"varint,3,opt,name=state,proto3,enum=supervisor.ExtensionState" json:"state,omitempty"`
+	// error explains why the installation failed
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditorExtension) Reset() {
+	*x = EditorExtension{}
+	mi := &file_editor_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditorExtension) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditorExtension) ProtoMessage() {}
+
+func (x *EditorExtension) ProtoReflect() protoreflect.Message {
+	mi := &file_editor_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditorExtension.ProtoReflect.Descriptor instead.
+func (*EditorExtension) Descriptor() ([]byte, []int) {
+	return file_editor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EditorExtension) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *EditorExtension) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EditorExtension) GetState() ExtensionState {
+	if x != nil {
+		return x.State
+	}
+	return ExtensionState_queued
+}
+
+func (x *EditorExtension) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetEditorStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEditorStatusRequest) Reset() {
+	*x = GetEditorStatusRequest{}
+	mi := &file_editor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEditorStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEditorStatusRequest) ProtoMessage() {}
+
+func (x *GetEditorStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_editor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEditorStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetEditorStatusRequest) Descriptor() ([]byte, []int) {
+	return file_editor_proto_rawDescGZIP(), []int{2}
+}
+
+type WatchEditorStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEditorStatusRequest) Reset() {
+	*x = WatchEditorStatusRequest{}
+	mi := &file_editor_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEditorStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEditorStatusRequest) ProtoMessage() {}
+
+func (x *WatchEditorStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_editor_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEditorStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchEditorStatusRequest) Descriptor() ([]byte, []int) {
+	return file_editor_proto_rawDescGZIP(), []int{3}
+}
+
+type RestartEditorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestartEditorRequest) Reset() {
+	*x = RestartEditorRequest{}
+	mi := &file_editor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestartEditorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartEditorRequest) ProtoMessage() {}
+
+func (x *RestartEditorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_editor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartEditorRequest.ProtoReflect.Descriptor instead.
+func (*RestartEditorRequest) Descriptor() ([]byte, []int) {
+	return file_editor_proto_rawDescGZIP(), []int{4}
+}
+
+type RestartEditorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestartEditorResponse) Reset() {
+	*x = RestartEditorResponse{}
+	mi := &file_editor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestartEditorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartEditorResponse) ProtoMessage() {}
+
+func (x *RestartEditorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_editor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartEditorResponse.ProtoReflect.Descriptor instead.
+func (*RestartEditorResponse) Descriptor() ([]byte, []int) {
+	return file_editor_proto_rawDescGZIP(), []int{5}
+}
+
+type StopEditorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopEditorRequest) Reset() {
+	*x = StopEditorRequest{}
+	mi := &file_editor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopEditorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopEditorRequest) ProtoMessage() {}
+
+func (x *StopEditorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_editor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopEditorRequest.ProtoReflect.Descriptor instead.
+func (*StopEditorRequest) Descriptor() ([]byte, []int) {
+	return file_editor_proto_rawDescGZIP(), []int{6}
+}
+
+type StopEditorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopEditorResponse) Reset() {
+	*x = StopEditorResponse{}
+	mi := &file_editor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopEditorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopEditorResponse) ProtoMessage() {}
+
+func (x *StopEditorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_editor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopEditorResponse.ProtoReflect.Descriptor instead.
+func (*StopEditorResponse) Descriptor() ([]byte, []int) {
+	return file_editor_proto_rawDescGZIP(), []int{7}
+}
+
+type EditorLogsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// tail is the number of most recent lines to return, all lines kept in memory if 0
+	Tail uint32 `protobuf:"varint,1,opt,name=tail,proto3" json:"tail,omitempty"`
+	// follow keeps streaming new output until the request is cancelled
+	Follow bool `protobuf:"varint,2,opt,name=follow,proto3" json:"follow,omitempty"`
+	// since is the unix timestamp in seconds of the oldest line to return
+	Since         int64 `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditorLogsRequest) Reset() {
+	*x = EditorLogsRequest{}
+	mi := &file_editor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditorLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditorLogsRequest) ProtoMessage() {}
+
+func (x *EditorLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_editor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditorLogsRequest.ProtoReflect.Descriptor instead.
+func (*EditorLogsRequest) Descriptor() ([]byte, []int) {
+	return file_editor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *EditorLogsRequest) GetTail() uint32 {
+	if x != nil {
+		return x.Tail
+	}
+	return 0
+}
+
+func (x *EditorLogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+func (x *EditorLogsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+type EditorLogLine struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// time is the unix timestamp in milliseconds the line was written at
+	Time          int64           `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Stream        EditorLogStream `protobuf:"varint,2,opt,name=stream,proto3,enum=supervisor.EditorLogStream" json:"stream,omitempty"`
+	Text          string          `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditorLogLine) Reset() {
+	*x = EditorLogLine{}
+	mi := &file_editor_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditorLogLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditorLogLine) ProtoMessage() {}
+
+func (x *EditorLogLine) ProtoReflect() protoreflect.Message {
+	mi := &file_editor_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditorLogLine.ProtoReflect.Descriptor instead.
+func (*EditorLogLine) Descriptor() ([]byte, []int) {
+	return file_editor_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *EditorLogLine) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *EditorLogLine) GetStream() EditorLogStream {
+	if x != nil {
+		return x.Stream
+	}
+	return EditorLogStream_stdout
+}
+
+func (x *EditorLogLine) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+var File_editor_proto protoreflect.FileDescriptor
+
+const file_editor_proto_rawDesc = "" +
+	"\n" +
+	"\feditor.proto\x12\n" +
+	"supervisor\"\xd5\x02\n" +
+	"\fEditorStatus\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12-\n" +
+	"\x05phase\x18\x03 \x01(\x0e2\x17.supervisor.EditorPhaseR\x05phase\x12\x14\n" +
+	"\x05ready\x18\x04 \x01(\bR\x05ready\x12\x10\n" +
+	"\x03pid\x18\x05 \x01(\x03R\x03pid\x12\x1a\n" +
+	"\brestarts\x18\x06 \x01(\rR\brestarts\x12\x16\n" +
+	"\x06uptime\x18\a \x01(\x03R\x06uptime\x12\x16\n" +
+	"\x06reason\x18\b \x01(\tR\x06reason\x12\x14\n" +
+	"\x05since\x18\t \x01(\x03R\x05since\x12!\n" +
+	"\fnext_restart\x18\n" +
+	" \x01(\x03R\vnextRestart\x12;\n" +
+	"\n" +
+	"extensions\x18\v \x03(\v2\x1b.supervisor.EditorExtensionR\n" +
+	"extensions\"{\n" +
+	"\x0fEditorExtension\x12\x10\n" +
+	"\x03ref\x18\x01 \x01(\tR\x03ref\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x120\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x1a.supervisor.ExtensionStateR\x05state\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\x18\n" +
+	"\x16GetEditorStatusRequest\"\x1a\n" +
+	"\x18WatchEditorStatusRequest\"\x16\n" +
+	"\x14RestartEditorRequest\"\x17\n" +
+	"\x15RestartEditorResponse\"\x13\n" +
+	"\x11StopEditorRequest\"\x14\n" +
+	"\x12StopEditorResponse\"U\n" +
+	"\x11EditorLogsRequest\x12\x12\n" +
+	"\x04tail\x18\x01 \x01(\rR\x04tail\x12\x16\n" +
+	"\x06follow\x18\x02 \x01(\bR\x06follow\x12\x14\n" +
+	"\x05since\x18\x03 \x01(\x03R\x05since\"l\n" +
+	"\rEditorLogLine\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\x03R\x04time\x123\n" +
+	"\x06stream\x18\x02 \x01(\x0e2\x1b.supervisor.EditorLogStreamR\x06stream\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text*N\n" +
+	"\vEditorPhase\x12\f\n" +
+	"\bstarting\x10\x00\x12\v\n" +
+	"\arunning\x10\x01\x12\v\n" +
+	"\abackoff\x10\x02\x12\n" +
+	"\n" +
+	"\x06failed\x10\x03\x12\v\n" +
+	"\astopped\x10\x04*\\\n" +
+	"\x0eExtensionState\x12\n" +
+	"\n" +
+	"\x06queued\x10\x00\x12\x0e\n" +
+	"\n" +
+	"installing\x10\x01\x12\r\n" +
+	"\tinstalled\x10\x02\x12\v\n" +
+	"\askipped\x10\x03\x12\x12\n" +
+	"\x0einstall_failed\x10\x04*)\n" +
+	"\x0fEditorLogStream\x12\n" +
+	"\n" +
+	"\x06stdout\x10\x00\x12\n" +
+	"\n" +
+	"\x06stderr\x10\x012\x90\x03\n" +
+	"\rEditorService\x12K\n" +
+	"\tGetStatus\x12\".supervisor.GetEditorStatusRequest\x1a\x18.supervisor.EditorStatus\"\x00\x12Q\n" +
+	"\vWatchStatus\x12$.supervisor.WatchEditorStatusRequest\x1a\x18.supervisor.EditorStatus\"\x000\x01\x12P\n" +
+	"\aRestart\x12 .supervisor.RestartEditorRequest\x1a!.supervisor.RestartEditorResponse\"\x00\x12G\n" +
+	"\x04Stop\x12\x1d.supervisor.StopEditorRequest\x1a\x1e.supervisor.StopEditorResponse\"\x00\x12D\n" +
+	"\x04Logs\x12\x1d.supervisor.EditorLogsRequest\x1a\x19.supervisor.EditorLogLine\"\x000\x01B\x10Z\x0esupervisor/apib\x06proto3"
+
+var (
+	file_editor_proto_rawDescOnce sync.Once
+	file_editor_proto_rawDescData []byte
+)
+
+func file_editor_proto_rawDescGZIP() []byte {
+	file_editor_proto_rawDescOnce.Do(func() {
+		file_editor_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_editor_proto_rawDesc), len(file_editor_proto_rawDesc)))
+	})
+	return file_editor_proto_rawDescData
+}
+
+var file_editor_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_editor_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_editor_proto_goTypes = []any{
+	(EditorPhase)(0),                 // 0: supervisor.EditorPhase
+	(ExtensionState)(0),              // 1: supervisor.ExtensionState
+	(EditorLogStream)(0),             // 2: supervisor.EditorLogStream
+	(*EditorStatus)(nil),             // 3: supervisor.EditorStatus
+	(*EditorExtension)(nil),          // 4: supervisor.EditorExtension
+	(*GetEditorStatusRequest)(nil),   // 5: supervisor.GetEditorStatusRequest
+	(*WatchEditorStatusRequest)(nil), // 6: supervisor.WatchEditorStatusRequest
+	(*RestartEditorRequest)(nil),     // 7: supervisor.RestartEditorRequest
+	(*RestartEditorResponse)(nil),    // 8: supervisor.RestartEditorResponse
+	(*StopEditorRequest)(nil),        // 9: supervisor.StopEditorRequest
+	(*StopEditorResponse)(nil),       // 10: supervisor.StopEditorResponse
+	(*EditorLogsRequest)(nil),        // 11: supervisor.EditorLogsRequest
+	(*EditorLogLine)(nil),            // 12: supervisor.EditorLogLine
+}
+var file_editor_proto_depIdxs = []int32{
+	0,  // 0: supervisor.EditorStatus.phase:type_name -> supervisor.EditorPhase
+	4,  // 1: supervisor.EditorStatus.extensions:type_name -> supervisor.EditorExtension
+	1,  // 2: supervisor.EditorExtension.state:type_name -> supervisor.ExtensionState
+	2,  // 3: supervisor.EditorLogLine.stream:type_name -> supervisor.EditorLogStream
+	5,  // 4: supervisor.EditorService.GetStatus:input_type -> supervisor.GetEditorStatusRequest
+	6,  // 5: supervisor.EditorService.WatchStatus:input_type -> supervisor.WatchEditorStatusRequest
+	7,  // 6: supervisor.EditorService.Restart:input_type -> supervisor.RestartEditorRequest
+	9,  // 7: supervisor.EditorService.Stop:input_type -> supervisor.StopEditorRequest
+	11, // 8: supervisor.EditorService.Logs:input_type -> supervisor.EditorLogsRequest
+	3,  // 9: supervisor.EditorService.GetStatus:output_type -> supervisor.EditorStatus
+	3,  // 10: supervisor.EditorService.WatchStatus:output_type -> supervisor.EditorStatus
+	8,  // 11: supervisor.EditorService.Restart:output_type -> supervisor.RestartEditorResponse
+	10, // 12: supervisor.EditorService.Stop:output_type -> supervisor.StopEditorResponse
+	12, // 13: supervisor.EditorService.Logs:output_type -> supervisor.EditorLogLine
+	9,  // [9:14] is the sub-list for method output_type
+	4,  // [4:9] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_editor_proto_init() }
+func file_editor_proto_init() {
+	if File_editor_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_editor_proto_rawDesc), len(file_editor_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_editor_proto_goTypes,
+		DependencyIndexes: file_editor_proto_depIdxs,
+		EnumInfos:         file_editor_proto_enumTypes,
+		MessageInfos:      file_editor_proto_msgTypes,
+	}.Build()
+	File_editor_proto = out.File
+	file_editor_proto_goTypes = nil
+	file_editor_proto_depIdxs = nil
+}
@@ -0,0 +1,292 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.31.1
+// source: editor.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	EditorService_GetStatus_FullMethodName   = "/supervisor.EditorService/GetStatus"
+	EditorService_WatchStatus_FullMethodName = "/supervisor.EditorService/WatchStatus"
+	EditorService_Restart_FullMethodName     = "/supervisor.EditorService/Restart"
+	EditorService_Stop_FullMethodName        = "/supervisor.EditorService/Stop"
+	EditorService_Logs_FullMethodName        = "/supervisor.EditorService/Logs"
+)
+
+// EditorServiceClient is the client API for EditorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EditorServiceClient interface {
+	// GetStatus returns the status of the editor process.
+	GetStatus(ctx context.Context, in *GetEditorStatusRequest, opts ...grpc.CallOption) (*EditorStatus, error)
+	// WatchStatus streams the status of the editor on every change, starting with the current one.
+	WatchStatus(ctx context.Context, in *WatchEditorStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EditorStatus], error)
+	// Restart stops the editor process and starts it again right away.
+	// A failed or stopped editor is started again.
+	Restart(ctx context.Context, in *RestartEditorRequest, opts ...grpc.CallOption) (*RestartEditorResponse, error)
+	// Stop stops the editor process. It is not restarted until Restart is called.
+	Stop(ctx context.Context, in *StopEditorRequest, opts ...grpc.CallOption) (*StopEditorResponse, error)
+	// Logs streams the output of the editor process kept in memory, and new output if follow is set.
+	Logs(ctx context.Context, in *EditorLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EditorLogLine], error)
+}
+
+type editorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEditorServiceClient(cc grpc.ClientConnInterface) EditorServiceClient {
+	return &editorServiceClient{cc}
+}
+
+func (c *editorServiceClient) GetStatus(ctx context.Context, in *GetEditorStatusRequest, opts ...grpc.CallOption) (*EditorStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EditorStatus)
+	err := c.cc.Invoke(ctx, EditorService_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *editorServiceClient) WatchStatus(ctx context.Context, in *WatchEditorStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EditorStatus], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EditorService_ServiceDesc.Streams[0], EditorService_WatchStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEditorStatusRequest, EditorStatus]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EditorService_WatchStatusClient = grpc.ServerStreamingClient[EditorStatus]
+
+func (c *editorServiceClient) Restart(ctx context.Context, in *RestartEditorRequest, opts ...grpc.CallOption) (*RestartEditorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestartEditorResponse)
+	err := c.cc.Invoke(ctx, EditorService_Restart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *editorServiceClient) Stop(ctx context.Context, in *StopEditorRequest, opts ...grpc.CallOption) (*StopEditorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StopEditorResponse)
+	err := c.cc.Invoke(ctx, EditorService_Stop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *editorServiceClient) Logs(ctx context.Context, in *EditorLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EditorLogLine], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EditorService_ServiceDesc.Streams[1], EditorService_Logs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[EditorLogsRequest, EditorLogLine]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EditorService_LogsClient = grpc.ServerStreamingClient[EditorLogLine]
+
+// EditorServiceServer is the server API for EditorService service.
+// All implementations must embed UnimplementedEditorServiceServer
+// for forward compatibility.
+type EditorServiceServer interface {
+	// GetStatus returns the status of the editor process.
+	GetStatus(context.Context, *GetEditorStatusRequest) (*EditorStatus, error)
+	// WatchStatus streams the status of the editor on every change, starting with the current one.
+	WatchStatus(*WatchEditorStatusRequest, grpc.ServerStreamingServer[EditorStatus]) error
+	// Restart stops the editor process and starts it again right away.
+	// A failed or stopped editor is started again.
+	Restart(context.Context, *RestartEditorRequest) (*RestartEditorResponse, error)
+	// Stop stops the editor process. It is not restarted until Restart is called.
+	Stop(context.Context, *StopEditorRequest) (*StopEditorResponse, error)
+	// Logs streams the output of the editor process kept in memory, and new output if follow is set.
+	Logs(*EditorLogsRequest, grpc.ServerStreamingServer[EditorLogLine]) error
+	mustEmbedUnimplementedEditorServiceServer()
+}
+
+// UnimplementedEditorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEditorServiceServer struct{}
+
+func (UnimplementedEditorServiceServer) GetStatus(context.Context, *GetEditorStatusRequest) (*EditorStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedEditorServiceServer) WatchStatus(*WatchEditorStatusRequest, grpc.ServerStreamingServer[EditorStatus]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStatus not implemented")
+}
+func (UnimplementedEditorServiceServer) Restart(context.Context, *RestartEditorRequest) (*RestartEditorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restart not implemented")
+}
+func (UnimplementedEditorServiceServer) Stop(context.Context, *StopEditorRequest) (*StopEditorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
+}
+func (UnimplementedEditorServiceServer) Logs(*EditorLogsRequest, grpc.ServerStreamingServer[EditorLogLine]) error {
+	return status.Errorf(codes.Unimplemented, "method Logs not implemented")
+}
+func (UnimplementedEditorServiceServer) mustEmbedUnimplementedEditorServiceServer() {}
+func (UnimplementedEditorServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeEditorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EditorServiceServer will
+// result in compilation errors.
+type UnsafeEditorServiceServer interface {
+	mustEmbedUnimplementedEditorServiceServer()
+}
+
+func RegisterEditorServiceServer(s grpc.ServiceRegistrar, srv EditorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedEditorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EditorService_ServiceDesc, srv)
+}
+
+func _EditorService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEditorStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EditorServiceServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EditorService_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EditorServiceServer).GetStatus(ctx, req.(*GetEditorStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EditorService_WatchStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEditorStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EditorServiceServer).WatchStatus(m, &grpc.GenericServerStream[WatchEditorStatusRequest, EditorStatus]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EditorService_WatchStatusServer = grpc.ServerStreamingServer[EditorStatus]
+
+func _EditorService_Restart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestartEditorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EditorServiceServer).Restart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EditorService_Restart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EditorServiceServer).Restart(ctx, req.(*RestartEditorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EditorService_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopEditorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EditorServiceServer).Stop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EditorService_Stop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EditorServiceServer).Stop(ctx, req.(*StopEditorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EditorService_Logs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EditorLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EditorServiceServer).Logs(m, &grpc.GenericServerStream[EditorLogsRequest, EditorLogLine]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EditorService_LogsServer = grpc.ServerStreamingServer[EditorLogLine]
+
+// EditorService_ServiceDesc is the grpc.ServiceDesc for EditorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EditorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "supervisor.EditorService",
+	HandlerType: (*EditorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _EditorService_GetStatus_Handler,
+		},
+		{
+			MethodName: "Restart",
+			Handler:    _EditorService_Restart_Handler,
+		},
+		{
+			MethodName: "Stop",
+			Handler:    _EditorService_Stop_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchStatus",
+			Handler:       _EditorService_WatchStatus_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Logs",
+			Handler:       _EditorService_Logs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "editor.proto",
+}
@@ -259,9 +259,15 @@ type ResourcesStatusResponse struct {
 	// Used CPU and limit in millicores.
 	Cpu *ResourceStatus `protobuf:"bytes,3,opt,name=cpu,proto3" json:"cpu,omitempty"`
 	// Used Disk and limit in bytes
-	Disk          *ResourceStatus `protobuf:"bytes,4,opt,name=disk,proto3" json:"disk,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Disk *ResourceStatus `protobuf:"bytes,4,opt,name=disk,proto3" json:"disk,omitempty"`
+	// Usage of the editor, tasks and terminals cgroups, if supervisor runs them in dedicated cgroups
+	Groups []*ResourceGroupStatus `protobuf:"bytes,5,rep,name=groups,proto3" json:"groups,omitempty"`
+	// Pressure stall information of the workspace, unset if the kernel doesn't report it
+	CpuPressure    *ResourcePressure `protobuf:"bytes,6,opt,name=cpu_pressure,json=cpuPressure,proto3" json:"cpu_pressure,omitempty"`
+	MemoryPressure *ResourcePressure `protobuf:"bytes,7,opt,name=memory_pressure,json=memoryPressure,proto3" json:"memory_pressure,omitempty"`
+	IoPressure     *ResourcePressure `protobuf:"bytes,8,opt,name=io_pressure,json=ioPressure,proto3" json:"io_pressure,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ResourcesStatusResponse) Reset() {
@@ -322,6 +328,194 @@ func (x *ResourcesStatusResponse) GetDisk() *ResourceStatus {
 	return nil
 }
 
+func (x *ResourcesStatusResponse) GetGroups() []*ResourceGroupStatus {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *ResourcesStatusResponse) GetCpuPressure() *ResourcePressure {
+	if x != nil {
+		return x.CpuPressure
+	}
+	return nil
+}
+
+func (x *ResourcesStatusResponse) GetMemoryPressure() *ResourcePressure {
+	if x != nil {
+		return x.MemoryPressure
+	}
+	return nil
+}
+
+func (x *ResourcesStatusResponse) GetIoPressure() *ResourcePressure {
+	if x != nil {
+		return x.IoPressure
+	}
+	return nil
+}
+
+type ResourcePressure struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Total time in microseconds in which some tasks were stalled on the resource
+	SomeTotal uint64 `protobuf:"varint,1,opt,name=some_total,json=someTotal,proto3" json:"some_total,omitempty"`
+	// Total time in microseconds in which all tasks were stalled on the resource at the same time
+	FullTotal uint64 `protobuf:"varint,2,opt,name=full_total,json=fullTotal,proto3" json:"full_total,omitempty"`
+	// Share of the last 10 and 60 seconds in percent in which some tasks were stalled
+	SomeAvg10 float64 `protobuf:"fixed64,3,opt,name=some_avg10,json=someAvg10,proto3" json:"some_avg10,omitempty"`
+	SomeAvg60 float64 `protobuf:"fixed64,4,opt,name=some_avg60,json=someAvg60,proto3" json:"some_avg60,omitempty"`
+	// Share of the last 10 and 60 seconds in percent in which all tasks were stalled
+	FullAvg10     float64 `protobuf:"fixed64,5,opt,name=full_avg10,json=fullAvg10,proto3" json:"full_avg10,omitempty"`
+	FullAvg60     float64 `protobuf:"fixed64,6,opt,name=full_avg60,json=fullAvg60,proto3" json:"full_avg60,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResourcePressure) Reset() {
+	*x = ResourcePressure{}
+	mi := &file_system_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourcePressure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourcePressure) ProtoMessage() {}
+
+func (x *ResourcePressure) ProtoReflect() protoreflect.Message {
+	mi := &file_system_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourcePressure.ProtoReflect.Descriptor instead.
+func (*ResourcePressure) Descriptor() ([]byte, []int) {
+	return file_system_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ResourcePressure) GetSomeTotal() uint64 {
+	if x != nil {
+		return x.SomeTotal
+	}
+	return 0
+}
+
+func (x *ResourcePressure) GetFullTotal() uint64 {
+	if x != nil {
+		return x.FullTotal
+	}
+	return 0
+}
+
+func (x *ResourcePressure) GetSomeAvg10() float64 {
+	if x != nil {
+		return x.SomeAvg10
+	}
+	return 0
+}
+
+func (x *ResourcePressure) GetSomeAvg60() float64 {
+	if x != nil {
+		return x.SomeAvg60
+	}
+	return 0
+}
+
+func (x *ResourcePressure) GetFullAvg10() float64 {
+	if x != nil {
+		return x.FullAvg10
+	}
+	return 0
+}
+
+func (x *ResourcePressure) GetFullAvg60() float64 {
+	if x != nil {
+		return x.FullAvg60
+	}
+	return 0
+}
+
+type ResourceGroupStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the cgroup relative to the workspace cgroup, e.g. "editor" or "tasks/build"
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Used memory and memory.high throttling limit in bytes, the limit is 0 if the group is not throttled
+	Memory *ResourceStatus `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	// CPU time used by the group in microseconds
+	CpuUsage uint64 `protobuf:"varint,3,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
+	// Share of CPU time of the group relative to the other groups, 100 by default
+	CpuWeight     uint64 `protobuf:"varint,4,opt,name=cpu_weight,json=cpuWeight,proto3" json:"cpu_weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResourceGroupStatus) Reset() {
+	*x = ResourceGroupStatus{}
+	mi := &file_system_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourceGroupStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceGroupStatus) ProtoMessage() {}
+
+func (x *ResourceGroupStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_system_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceGroupStatus.ProtoReflect.Descriptor instead.
+func (*ResourceGroupStatus) Descriptor() ([]byte, []int) {
+	return file_system_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResourceGroupStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ResourceGroupStatus) GetMemory() *ResourceStatus {
+	if x != nil {
+		return x.Memory
+	}
+	return nil
+}
+
+func (x *ResourceGroupStatus) GetCpuUsage() uint64 {
+	if x != nil {
+		return x.CpuUsage
+	}
+	return 0
+}
+
+func (x *ResourceGroupStatus) GetCpuWeight() uint64 {
+	if x != nil {
+		return x.CpuWeight
+	}
+	return 0
+}
+
 type ResourceStatus struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Used          int64                  `protobuf:"varint,1,opt,name=used,proto3" json:"used,omitempty"`
@@ -333,7 +527,7 @@ type ResourceStatus struct {
 
 func (x *ResourceStatus) Reset() {
 	*x = ResourceStatus{}
-	mi := &file_system_proto_msgTypes[4]
+	mi := &file_system_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -345,7 +539,7 @@ func (x *ResourceStatus) String() string {
 func (*ResourceStatus) ProtoMessage() {}
 
 func (x *ResourceStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_system_proto_msgTypes[4]
+	mi := &file_system_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -358,7 +552,7 @@ func (x *ResourceStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResourceStatus.ProtoReflect.Descriptor instead.
 func (*ResourceStatus) Descriptor() ([]byte, []int) {
-	return file_system_proto_rawDescGZIP(), []int{4}
+	return file_system_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ResourceStatus) GetUsed() int64 {
@@ -382,6 +576,214 @@ func (x *ResourceStatus) GetSeverity() ResourceStatusSeverity {
 	return ResourceStatusSeverity_normal
 }
 
+type ResourcesHistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of seconds of history to return, all samples which are kept if 0
+	Window uint32 `protobuf:"varint,1,opt,name=window,proto3" json:"window,omitempty"`
+	// Number of seconds covered by each returned sample, which holds the highest usage within
+	// these seconds s.t. spikes are not averaged away. Every sample is returned if 0.
+	Step          uint32 `protobuf:"varint,2,opt,name=step,proto3" json:"step,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResourcesHistoryRequest) Reset() {
+	*x = ResourcesHistoryRequest{}
+	mi := &file_system_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourcesHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourcesHistoryRequest) ProtoMessage() {}
+
+func (x *ResourcesHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_system_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourcesHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ResourcesHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_system_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ResourcesHistoryRequest) GetWindow() uint32 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
+}
+
+func (x *ResourcesHistoryRequest) GetStep() uint32 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+type ResourcesHistoryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Samples ordered from the oldest to the latest one
+	Samples       []*ResourcesSample `protobuf:"bytes,1,rep,name=samples,proto3" json:"samples,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResourcesHistoryResponse) Reset() {
+	*x = ResourcesHistoryResponse{}
+	mi := &file_system_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourcesHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourcesHistoryResponse) ProtoMessage() {}
+
+func (x *ResourcesHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_system_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourcesHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ResourcesHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_system_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ResourcesHistoryResponse) GetSamples() []*ResourcesSample {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+type ResourcesSample struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Time of the sample as unix timestamp in seconds
+	Time int64 `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	// Used memory and limit in bytes
+	Memory *ResourceStatus `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	// Used CPU and limit in millicores.
+	Cpu *ResourceStatus `protobuf:"bytes,3,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	// Used Disk and limit in bytes
+	Disk          *ResourceStatus `protobuf:"bytes,4,opt,name=disk,proto3" json:"disk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResourcesSample) Reset() {
+	*x = ResourcesSample{}
+	mi := &file_system_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourcesSample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourcesSample) ProtoMessage() {}
+
+func (x *ResourcesSample) ProtoReflect() protoreflect.Message {
+	mi := &file_system_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourcesSample.ProtoReflect.Descriptor instead.
+func (*ResourcesSample) Descriptor() ([]byte, []int) {
+	return file_system_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ResourcesSample) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *ResourcesSample) GetMemory() *ResourceStatus {
+	if x != nil {
+		return x.Memory
+	}
+	return nil
+}
+
+func (x *ResourcesSample) GetCpu() *ResourceStatus {
+	if x != nil {
+		return x.Cpu
+	}
+	return nil
+}
+
+func (x *ResourcesSample) GetDisk() *ResourceStatus {
+	if x != nil {
+		return x.Disk
+	}
+	return nil
+}
+
+type WatchResourcesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchResourcesRequest) Reset() {
+	*x = WatchResourcesRequest{}
+	mi := &file_system_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchResourcesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchResourcesRequest) ProtoMessage() {}
+
+func (x *WatchResourcesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_system_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchResourcesRequest.ProtoReflect.Descriptor instead.
+func (*WatchResourcesRequest) Descriptor() ([]byte, []int) {
+	return file_system_proto_rawDescGZIP(), []int{10}
+}
+
 var File_system_proto protoreflect.FileDescriptor
 
 const file_system_proto_rawDesc = "" +
@@ -398,25 +800,62 @@ const file_system_proto_rawDesc = "" +
 	"\tide_alias\x18\x06 \x01(\tR\bideAlias\x12\x19\n" +
 	"\bide_port\x18\a \x01(\rR\aidePort\x12\x19\n" +
 	"\bowner_id\x18\b \x01(\x03R\aownerId\"\x18\n" +
-	"\x16ResourcesStatusRequest\"\xc3\x01\n" +
+	"\x16ResourcesStatusRequest\"\xc3\x03\n" +
 	"\x17ResourcesStatusResponse\x12\x16\n" +
 	"\x06flavor\x18\x01 \x01(\tR\x06flavor\x122\n" +
 	"\x06memory\x18\x02 \x01(\v2\x1a.supervisor.ResourceStatusR\x06memory\x12,\n" +
 	"\x03cpu\x18\x03 \x01(\v2\x1a.supervisor.ResourceStatusR\x03cpu\x12.\n" +
-	"\x04disk\x18\x04 \x01(\v2\x1a.supervisor.ResourceStatusR\x04disk\"z\n" +
+	"\x04disk\x18\x04 \x01(\v2\x1a.supervisor.ResourceStatusR\x04disk\x127\n" +
+	"\x06groups\x18\x05 \x03(\v2\x1f.supervisor.ResourceGroupStatusR\x06groups\x12?\n" +
+	"\fcpu_pressure\x18\x06 \x01(\v2\x1c.supervisor.ResourcePressureR\vcpuPressure\x12E\n" +
+	"\x0fmemory_pressure\x18\a \x01(\v2\x1c.supervisor.ResourcePressureR\x0ememoryPressure\x12=\n" +
+	"\vio_pressure\x18\b \x01(\v2\x1c.supervisor.ResourcePressureR\n" +
+	"ioPressure\"\xcc\x01\n" +
+	"\x10ResourcePressure\x12\x1d\n" +
+	"\n" +
+	"some_total\x18\x01 \x01(\x04R\tsomeTotal\x12\x1d\n" +
+	"\n" +
+	"full_total\x18\x02 \x01(\x04R\tfullTotal\x12\x1d\n" +
+	"\n" +
+	"some_avg10\x18\x03 \x01(\x01R\tsomeAvg10\x12\x1d\n" +
+	"\n" +
+	"some_avg60\x18\x04 \x01(\x01R\tsomeAvg60\x12\x1d\n" +
+	"\n" +
+	"full_avg10\x18\x05 \x01(\x01R\tfullAvg10\x12\x1d\n" +
+	"\n" +
+	"full_avg60\x18\x06 \x01(\x01R\tfullAvg60\"\x99\x01\n" +
+	"\x13ResourceGroupStatus\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x122\n" +
+	"\x06memory\x18\x02 \x01(\v2\x1a.supervisor.ResourceStatusR\x06memory\x12\x1b\n" +
+	"\tcpu_usage\x18\x03 \x01(\x04R\bcpuUsage\x12\x1d\n" +
+	"\n" +
+	"cpu_weight\x18\x04 \x01(\x04R\tcpuWeight\"z\n" +
 	"\x0eResourceStatus\x12\x12\n" +
 	"\x04used\x18\x01 \x01(\x03R\x04used\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x03R\x05limit\x12>\n" +
-	"\bseverity\x18\x03 \x01(\x0e2\".supervisor.ResourceStatusSeverityR\bseverity*=\n" +
+	"\bseverity\x18\x03 \x01(\x0e2\".supervisor.ResourceStatusSeverityR\bseverity\"E\n" +
+	"\x17ResourcesHistoryRequest\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\rR\x06window\x12\x12\n" +
+	"\x04step\x18\x02 \x01(\rR\x04step\"Q\n" +
+	"\x18ResourcesHistoryResponse\x125\n" +
+	"\asamples\x18\x01 \x03(\v2\x1b.supervisor.ResourcesSampleR\asamples\"\xb7\x01\n" +
+	"\x0fResourcesSample\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\x03R\x04time\x122\n" +
+	"\x06memory\x18\x02 \x01(\v2\x1a.supervisor.ResourceStatusR\x06memory\x12,\n" +
+	"\x03cpu\x18\x03 \x01(\v2\x1a.supervisor.ResourceStatusR\x03cpu\x12.\n" +
+	"\x04disk\x18\x04 \x01(\v2\x1a.supervisor.ResourceStatusR\x04disk\"\x17\n" +
+	"\x15WatchResourcesRequest*=\n" +
 	"\x16ResourceStatusSeverity\x12\n" +
 	"\n" +
 	"\x06normal\x10\x00\x12\v\n" +
 	"\awarning\x10\x01\x12\n" +
 	"\n" +
-	"\x06danger\x10\x022\xc5\x01\n" +
+	"\x06danger\x10\x022\x84\x03\n" +
 	"\rSystemService\x12V\n" +
 	"\rWorkspaceInfo\x12 .supervisor.WorkspaceInfoRequest\x1a!.supervisor.WorkspaceInfoResponse\"\x00\x12\\\n" +
-	"\x0fResourcesStatus\x12\".supervisor.ResourcesStatusRequest\x1a#.supervisor.ResourcesStatusResponse\"\x00B\x10Z\x0esupervisor/apib\x06proto3"
+	"\x0fResourcesStatus\x12\".supervisor.ResourcesStatusRequest\x1a#.supervisor.ResourcesStatusResponse\"\x00\x12_\n" +
+	"\x10ResourcesHistory\x12#.supervisor.ResourcesHistoryRequest\x1a$.supervisor.ResourcesHistoryResponse\"\x00\x12\\\n" +
+	"\x0eWatchResources\x12!.supervisor.WatchResourcesRequest\x1a#.supervisor.ResourcesStatusResponse\"\x000\x01B\x10Z\x0esupervisor/apib\x06proto3"
 
 var (
 	file_system_proto_rawDescOnce sync.Once
@@ -431,29 +870,48 @@ func file_system_proto_rawDescGZIP() []byte {
 }
 
 var file_system_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_system_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_system_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_system_proto_goTypes = []any{
-	(ResourceStatusSeverity)(0),     // 0: supervisor.ResourceStatusSeverity
-	(*WorkspaceInfoRequest)(nil),    // 1: supervisor.WorkspaceInfoRequest
-	(*WorkspaceInfoResponse)(nil),   // 2: supervisor.WorkspaceInfoResponse
-	(*ResourcesStatusRequest)(nil),  // 3: supervisor.ResourcesStatusRequest
-	(*ResourcesStatusResponse)(nil), // 4: supervisor.ResourcesStatusResponse
-	(*ResourceStatus)(nil),          // 5: supervisor.ResourceStatus
+	(ResourceStatusSeverity)(0),      // 0: supervisor.ResourceStatusSeverity
+	(*WorkspaceInfoRequest)(nil),     // 1: supervisor.WorkspaceInfoRequest
+	(*WorkspaceInfoResponse)(nil),    // 2: supervisor.WorkspaceInfoResponse
+	(*ResourcesStatusRequest)(nil),   // 3: supervisor.ResourcesStatusRequest
+	(*ResourcesStatusResponse)(nil),  // 4: supervisor.ResourcesStatusResponse
+	(*ResourcePressure)(nil),         // 5: supervisor.ResourcePressure
+	(*ResourceGroupStatus)(nil),      // 6: supervisor.ResourceGroupStatus
+	(*ResourceStatus)(nil),           // 7: supervisor.ResourceStatus
+	(*ResourcesHistoryRequest)(nil),  // 8: supervisor.ResourcesHistoryRequest
+	(*ResourcesHistoryResponse)(nil), // 9: supervisor.ResourcesHistoryResponse
+	(*ResourcesSample)(nil),          // 10: supervisor.ResourcesSample
+	(*WatchResourcesRequest)(nil),    // 11: supervisor.WatchResourcesRequest
 }
 var file_system_proto_depIdxs = []int32{
-	5, // 0: supervisor.ResourcesStatusResponse.memory:type_name -> supervisor.ResourceStatus
-	5, // 1: supervisor.ResourcesStatusResponse.cpu:type_name -> supervisor.ResourceStatus
-	5, // 2: supervisor.ResourcesStatusResponse.disk:type_name -> supervisor.ResourceStatus
-	0, // 3: supervisor.ResourceStatus.severity:type_name -> supervisor.ResourceStatusSeverity
-	1, // 4: supervisor.SystemService.WorkspaceInfo:input_type -> supervisor.WorkspaceInfoRequest
-	3, // 5: supervisor.SystemService.ResourcesStatus:input_type -> supervisor.ResourcesStatusRequest
-	2, // 6: supervisor.SystemService.WorkspaceInfo:output_type -> supervisor.WorkspaceInfoResponse
-	4, // 7: supervisor.SystemService.ResourcesStatus:output_type -> supervisor.ResourcesStatusResponse
-	6, // [6:8] is the sub-list for method output_type
-	4, // [4:6] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	7,  // 0: supervisor.ResourcesStatusResponse.memory:type_name -> supervisor.ResourceStatus
+	7,  // 1: supervisor.ResourcesStatusResponse.cpu:type_name -> supervisor.ResourceStatus
+	7,  // 2: supervisor.ResourcesStatusResponse.disk:type_name -> supervisor.ResourceStatus
+	6,  // 3: supervisor.ResourcesStatusResponse.groups:type_name -> supervisor.ResourceGroupStatus
+	5,  // 4: supervisor.ResourcesStatusResponse.cpu_pressure:type_name -> supervisor.ResourcePressure
+	5,  // 5: supervisor.ResourcesStatusResponse.memory_pressure:type_name -> supervisor.ResourcePressure
+	5,  // 6: supervisor.ResourcesStatusResponse.io_pressure:type_name -> supervisor.ResourcePressure
+	7,  // 7: supervisor.ResourceGroupStatus.memory:type_name -> supervisor.ResourceStatus
+	0,  // 8: supervisor.ResourceStatus.severity:type_name -> supervisor.ResourceStatusSeverity
+	10, // 9: supervisor.ResourcesHistoryResponse.samples:type_name -> supervisor.ResourcesSample
+	7,  // 10: supervisor.ResourcesSample.memory:type_name -> supervisor.ResourceStatus
+	7,  // 11: supervisor.ResourcesSample.cpu:type_name -> supervisor.ResourceStatus
+	7,  // 12: supervisor.ResourcesSample.disk:type_name -> supervisor.ResourceStatus
+	1,  // 13: supervisor.SystemService.WorkspaceInfo:input_type -> supervisor.WorkspaceInfoRequest
+	3,  // 14: supervisor.SystemService.ResourcesStatus:input_type -> supervisor.ResourcesStatusRequest
+	8,  // 15: supervisor.SystemService.ResourcesHistory:input_type -> supervisor.ResourcesHistoryRequest
+	11, // 16: supervisor.SystemService.WatchResources:input_type -> supervisor.WatchResourcesRequest
+	2,  // 17: supervisor.SystemService.WorkspaceInfo:output_type -> supervisor.WorkspaceInfoResponse
+	4,  // 18: supervisor.SystemService.ResourcesStatus:output_type -> supervisor.ResourcesStatusResponse
+	9,  // 19: supervisor.SystemService.ResourcesHistory:output_type -> supervisor.ResourcesHistoryResponse
+	4,  // 20: supervisor.SystemService.WatchResources:output_type -> supervisor.ResourcesStatusResponse
+	17, // [17:21] is the sub-list for method output_type
+	13, // [13:17] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_system_proto_init() }
@@ -467,7 +925,7 @@ func file_system_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_system_proto_rawDesc), len(file_system_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   5,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,17 +19,24 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	SystemService_WorkspaceInfo_FullMethodName   = "/supervisor.SystemService/WorkspaceInfo"
-	SystemService_ResourcesStatus_FullMethodName = "/supervisor.SystemService/ResourcesStatus"
+	SystemService_WorkspaceInfo_FullMethodName    = "/supervisor.SystemService/WorkspaceInfo"
+	SystemService_ResourcesStatus_FullMethodName  = "/supervisor.SystemService/ResourcesStatus"
+	SystemService_ResourcesHistory_FullMethodName = "/supervisor.SystemService/ResourcesHistory"
+	SystemService_WatchResources_FullMethodName   = "/supervisor.SystemService/WatchResources"
 )
 
 // SystemServiceClient is the client API for SystemService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SystemServiceClient interface {
+	//
 	WorkspaceInfo(ctx context.Context, in *WorkspaceInfoRequest, opts ...grpc.CallOption) (*WorkspaceInfoResponse, error)
 	// ResourcesStatus provides workspace resources status information.
 	ResourcesStatus(ctx context.Context, in *ResourcesStatusRequest, opts ...grpc.CallOption) (*ResourcesStatusResponse, error)
+	// ResourcesHistory returns the resource usage of the workspace sampled over a past time window.
+	ResourcesHistory(ctx context.Context, in *ResourcesHistoryRequest, opts ...grpc.CallOption) (*ResourcesHistoryResponse, error)
+	// WatchResources streams the resource usage of the workspace every time it is sampled.
+	WatchResources(ctx context.Context, in *WatchResourcesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ResourcesStatusResponse], error)
 }
 
 type systemServiceClient struct {
@@ -60,13 +67,47 @@ func (c *systemServiceClient) ResourcesStatus(ctx context.Context, in *Resources
 	return out, nil
 }
 
+func (c *systemServiceClient) ResourcesHistory(ctx context.Context, in *ResourcesHistoryRequest, opts ...grpc.CallOption) (*ResourcesHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResourcesHistoryResponse)
+	err := c.cc.Invoke(ctx, SystemService_ResourcesHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *systemServiceClient) WatchResources(ctx context.Context, in *WatchResourcesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ResourcesStatusResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SystemService_ServiceDesc.Streams[0], SystemService_WatchResources_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchResourcesRequest, ResourcesStatusResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SystemService_WatchResourcesClient = grpc.ServerStreamingClient[ResourcesStatusResponse]
+
 // SystemServiceServer is the server API for SystemService service.
 // All implementations must embed UnimplementedSystemServiceServer
 // for forward compatibility.
 type SystemServiceServer interface {
+	//
 	WorkspaceInfo(context.Context, *WorkspaceInfoRequest) (*WorkspaceInfoResponse, error)
 	// ResourcesStatus provides workspace resources status information.
 	ResourcesStatus(context.Context, *ResourcesStatusRequest) (*ResourcesStatusResponse, error)
+	// ResourcesHistory returns the resource usage of the workspace sampled over a past time window.
+	ResourcesHistory(context.Context, *ResourcesHistoryRequest) (*ResourcesHistoryResponse, error)
+	// WatchResources streams the resource usage of the workspace every time it is sampled.
+	WatchResources(*WatchResourcesRequest, grpc.ServerStreamingServer[ResourcesStatusResponse]) error
 	mustEmbedUnimplementedSystemServiceServer()
 }
 
@@ -83,6 +124,12 @@ func (UnimplementedSystemServiceServer) WorkspaceInfo(context.Context, *Workspac
 func (UnimplementedSystemServiceServer) ResourcesStatus(context.Context, *ResourcesStatusRequest) (*ResourcesStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ResourcesStatus not implemented")
 }
+func (UnimplementedSystemServiceServer) ResourcesHistory(context.Context, *ResourcesHistoryRequest) (*ResourcesHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResourcesHistory not implemented")
+}
+func (UnimplementedSystemServiceServer) WatchResources(*WatchResourcesRequest, grpc.ServerStreamingServer[ResourcesStatusResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchResources not implemented")
+}
 func (UnimplementedSystemServiceServer) mustEmbedUnimplementedSystemServiceServer() {}
 func (UnimplementedSystemServiceServer) testEmbeddedByValue()                       {}
 
@@ -140,6 +187,35 @@ func _SystemService_ResourcesStatus_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SystemService_ResourcesHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourcesHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemServiceServer).ResourcesHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SystemService_ResourcesHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemServiceServer).ResourcesHistory(ctx, req.(*ResourcesHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SystemService_WatchResources_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchResourcesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SystemServiceServer).WatchResources(m, &grpc.GenericServerStream[WatchResourcesRequest, ResourcesStatusResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SystemService_WatchResourcesServer = grpc.ServerStreamingServer[ResourcesStatusResponse]
+
 // SystemService_ServiceDesc is the grpc.ServiceDesc for SystemService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -155,7 +231,17 @@ var SystemService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ResourcesStatus",
 			Handler:    _SystemService_ResourcesStatus_Handler,
 		},
+		{
+			MethodName: "ResourcesHistory",
+			Handler:    _SystemService_ResourcesHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchResources",
+			Handler:       _SystemService_WatchResources_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "system.proto",
 }
@@ -69,6 +69,106 @@ func (TerminalTitleSource) EnumDescriptor() ([]byte, []int) {
 	return file_terminal_proto_rawDescGZIP(), []int{0}
 }
 
+type TerminalExportFormat int32
+
+const (
+	// Plain text with all escape sequences removed
+	TerminalExportFormat_text TerminalExportFormat = 0
+	// HTML document with colors rendered
+	TerminalExportFormat_html TerminalExportFormat = 1
+	// asciicast v2 document
+	TerminalExportFormat_cast TerminalExportFormat = 2
+)
+
+// Enum value maps for TerminalExportFormat.
+var (
+	TerminalExportFormat_name = map[int32]string{
+		0: "text",
+		1: "html",
+		2: "cast",
+	}
+	TerminalExportFormat_value = map[string]int32{
+		"text": 0,
+		"html": 1,
+		"cast": 2,
+	}
+)
+
+func (x TerminalExportFormat) Enum() *TerminalExportFormat {
+	p := new(TerminalExportFormat)
+	*p = x
+	return p
+}
+
+func (x TerminalExportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TerminalExportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_terminal_proto_enumTypes[1].Descriptor()
+}
+
+func (TerminalExportFormat) Type() protoreflect.EnumType {
+	return &file_terminal_proto_enumTypes[1]
+}
+
+func (x TerminalExportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TerminalExportFormat.Descriptor instead.
+func (TerminalExportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{1}
+}
+
+type TerminalAccessScope int32
+
+const (
+	// Listen only
+	TerminalAccessScope_read_only TerminalAccessScope = 0
+	// Listen and write
+	TerminalAccessScope_read_write TerminalAccessScope = 1
+)
+
+// Enum value maps for TerminalAccessScope.
+var (
+	TerminalAccessScope_name = map[int32]string{
+		0: "read_only",
+		1: "read_write",
+	}
+	TerminalAccessScope_value = map[string]int32{
+		"read_only":  0,
+		"read_write": 1,
+	}
+)
+
+func (x TerminalAccessScope) Enum() *TerminalAccessScope {
+	p := new(TerminalAccessScope)
+	*p = x
+	return p
+}
+
+func (x TerminalAccessScope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TerminalAccessScope) Descriptor() protoreflect.EnumDescriptor {
+	return file_terminal_proto_enumTypes[2].Descriptor()
+}
+
+func (TerminalAccessScope) Type() protoreflect.EnumType {
+	return &file_terminal_proto_enumTypes[2]
+}
+
+func (x TerminalAccessScope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TerminalAccessScope.Descriptor instead.
+func (TerminalAccessScope) EnumDescriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{2}
+}
+
 type TerminalSize struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Rows          uint32                 `protobuf:"varint,1,opt,name=rows,proto3" json:"rows,omitempty"`
@@ -138,13 +238,24 @@ func (x *TerminalSize) GetHeightPx() uint32 {
 }
 
 type OpenTerminalRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Workdir       string                 `protobuf:"bytes,1,opt,name=workdir,proto3" json:"workdir,omitempty"`
-	Env           map[string]string      `protobuf:"bytes,2,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Annotations   map[string]string      `protobuf:"bytes,3,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Shell         string                 `protobuf:"bytes,4,opt,name=shell,proto3" json:"shell,omitempty"`
-	ShellArgs     []string               `protobuf:"bytes,5,rep,name=shell_args,json=shellArgs,proto3" json:"shell_args,omitempty"`
-	Size          *TerminalSize          `protobuf:"bytes,6,opt,name=size,proto3" json:"size,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Workdir     string                 `protobuf:"bytes,1,opt,name=workdir,proto3" json:"workdir,omitempty"`
+	Env         map[string]string      `protobuf:"bytes,2,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Annotations map[string]string      `protobuf:"bytes,3,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Shell       string                 `protobuf:"bytes,4,opt,name=shell,proto3" json:"shell,omitempty"`
+	ShellArgs   []string               `protobuf:"bytes,5,rep,name=shell_args,json=shellArgs,proto3" json:"shell_args,omitempty"`
+	Size        *TerminalSize          `protobuf:"bytes,6,opt,name=size,proto3" json:"size,omitempty"`
+	// profile is the name of a terminal profile defined in .opencoder.yml.
+	// Fields set on the request take precedence over the profile.
+	Profile string `protobuf:"bytes,7,opt,name=profile,proto3" json:"profile,omitempty"`
+	// record starts recording the terminal session into an asciicast v2 file in the workspace.
+	Record bool `protobuf:"varint,8,opt,name=record,proto3" json:"record,omitempty"`
+	// name is an optional unique name the terminal can be referred to by instead of its alias.
+	// It may contain letters, digits, '.', '_' and '-'.
+	Name string `protobuf:"bytes,9,opt,name=name,proto3" json:"name,omitempty"`
+	// idle_timeout closes the terminal once it had no listeners, no output and no foreground
+	// process other than its shell for the given number of seconds. 0 keeps the terminal open.
+	IdleTimeout   uint32 `protobuf:"varint,10,opt,name=idle_timeout,json=idleTimeout,proto3" json:"idle_timeout,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -221,6 +332,34 @@ func (x *OpenTerminalRequest) GetSize() *TerminalSize {
 	return nil
 }
 
+func (x *OpenTerminalRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *OpenTerminalRequest) GetRecord() bool {
+	if x != nil {
+		return x.Record
+	}
+	return false
+}
+
+func (x *OpenTerminalRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OpenTerminalRequest) GetIdleTimeout() uint32 {
+	if x != nil {
+		return x.IdleTimeout
+	}
+	return 0
+}
+
 type OpenTerminalResponse struct {
 	state    protoimpl.MessageState `protogen:"open.v1"`
 	Terminal *Terminal              `protobuf:"bytes,1,opt,name=terminal,proto3" json:"terminal,omitempty"`
@@ -276,9 +415,11 @@ func (x *OpenTerminalResponse) GetStarterToken() string {
 }
 
 type ShutdownTerminalRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Alias         string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
-	ForceSuccess  bool                   `protobuf:"varint,2,opt,name=force_success,json=forceSuccess,proto3" json:"force_success,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// alias is the terminal's alias, its name or an annotation selector
+	// (key=value[,key=value]) matching exactly one terminal
+	Alias         string `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	ForceSuccess  bool   `protobuf:"varint,2,opt,name=force_success,json=forceSuccess,proto3" json:"force_success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -373,8 +514,16 @@ type Terminal struct {
 	CurrentWorkdir string                 `protobuf:"bytes,6,opt,name=current_workdir,json=currentWorkdir,proto3" json:"current_workdir,omitempty"`
 	Annotations    map[string]string      `protobuf:"bytes,7,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	TitleSource    TerminalTitleSource    `protobuf:"varint,8,opt,name=title_source,json=titleSource,proto3,enum=supervisor.TerminalTitleSource" json:"title_source,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// recording is the path of the asciicast file the session is recorded to, if any
+	Recording string `protobuf:"bytes,9,opt,name=recording,proto3" json:"recording,omitempty"`
+	// viewers are the clients currently connected through a share token
+	Viewers []*TerminalViewer `protobuf:"bytes,10,rep,name=viewers,proto3" json:"viewers,omitempty"`
+	// name is the unique name given when opening the terminal, if any
+	Name string `protobuf:"bytes,11,opt,name=name,proto3" json:"name,omitempty"`
+	// idle_timeout is the number of idle seconds after which the terminal is closed, 0 if never
+	IdleTimeout   uint32 `protobuf:"varint,12,opt,name=idle_timeout,json=idleTimeout,proto3" json:"idle_timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Terminal) Reset() {
@@ -463,16 +612,117 @@ func (x *Terminal) GetTitleSource() TerminalTitleSource {
 	return TerminalTitleSource_process
 }
 
+func (x *Terminal) GetRecording() string {
+	if x != nil {
+		return x.Recording
+	}
+	return ""
+}
+
+func (x *Terminal) GetViewers() []*TerminalViewer {
+	if x != nil {
+		return x.Viewers
+	}
+	return nil
+}
+
+func (x *Terminal) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Terminal) GetIdleTimeout() uint32 {
+	if x != nil {
+		return x.IdleTimeout
+	}
+	return 0
+}
+
+type TerminalViewer struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name the share was created with
+	Name  string              `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scope TerminalAccessScope `protobuf:"varint,2,opt,name=scope,proto3,enum=supervisor.TerminalAccessScope" json:"scope,omitempty"`
+	// since is the unix timestamp in seconds the viewer connected at
+	Since int64 `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`
+	// expires_at is the unix timestamp in seconds the share expires at
+	ExpiresAt     int64 `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TerminalViewer) Reset() {
+	*x = TerminalViewer{}
+	mi := &file_terminal_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TerminalViewer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerminalViewer) ProtoMessage() {}
+
+func (x *TerminalViewer) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminalViewer.ProtoReflect.Descriptor instead.
+func (*TerminalViewer) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TerminalViewer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TerminalViewer) GetScope() TerminalAccessScope {
+	if x != nil {
+		return x.Scope
+	}
+	return TerminalAccessScope_read_only
+}
+
+func (x *TerminalViewer) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *TerminalViewer) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
 type GetTerminalRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Alias         string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// alias is the terminal's alias, its name or an annotation selector
+	// (key=value[,key=value]) matching exactly one terminal
+	Alias         string `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetTerminalRequest) Reset() {
 	*x = GetTerminalRequest{}
-	mi := &file_terminal_proto_msgTypes[6]
+	mi := &file_terminal_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -484,7 +734,7 @@ func (x *GetTerminalRequest) String() string {
 func (*GetTerminalRequest) ProtoMessage() {}
 
 func (x *GetTerminalRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_terminal_proto_msgTypes[6]
+	mi := &file_terminal_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -497,7 +747,7 @@ func (x *GetTerminalRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTerminalRequest.ProtoReflect.Descriptor instead.
 func (*GetTerminalRequest) Descriptor() ([]byte, []int) {
-	return file_terminal_proto_rawDescGZIP(), []int{6}
+	return file_terminal_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetTerminalRequest) GetAlias() string {
@@ -515,7 +765,7 @@ type ListTerminalsRequest struct {
 
 func (x *ListTerminalsRequest) Reset() {
 	*x = ListTerminalsRequest{}
-	mi := &file_terminal_proto_msgTypes[7]
+	mi := &file_terminal_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -527,7 +777,7 @@ func (x *ListTerminalsRequest) String() string {
 func (*ListTerminalsRequest) ProtoMessage() {}
 
 func (x *ListTerminalsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_terminal_proto_msgTypes[7]
+	mi := &file_terminal_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -540,19 +790,21 @@ func (x *ListTerminalsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTerminalsRequest.ProtoReflect.Descriptor instead.
 func (*ListTerminalsRequest) Descriptor() ([]byte, []int) {
-	return file_terminal_proto_rawDescGZIP(), []int{7}
+	return file_terminal_proto_rawDescGZIP(), []int{8}
 }
 
 type ListTerminalsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Terminals     []*Terminal            `protobuf:"bytes,1,rep,name=terminals,proto3" json:"terminals,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Terminals []*Terminal            `protobuf:"bytes,1,rep,name=terminals,proto3" json:"terminals,omitempty"`
+	// limits are the limits Open enforces
+	Limits        *TerminalLimits `protobuf:"bytes,2,opt,name=limits,proto3" json:"limits,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListTerminalsResponse) Reset() {
 	*x = ListTerminalsResponse{}
-	mi := &file_terminal_proto_msgTypes[8]
+	mi := &file_terminal_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -564,7 +816,7 @@ func (x *ListTerminalsResponse) String() string {
 func (*ListTerminalsResponse) ProtoMessage() {}
 
 func (x *ListTerminalsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_terminal_proto_msgTypes[8]
+	mi := &file_terminal_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -577,7 +829,7 @@ func (x *ListTerminalsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTerminalsResponse.ProtoReflect.Descriptor instead.
 func (*ListTerminalsResponse) Descriptor() ([]byte, []int) {
-	return file_terminal_proto_rawDescGZIP(), []int{8}
+	return file_terminal_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ListTerminalsResponse) GetTerminals() []*Terminal {
@@ -587,16 +839,95 @@ func (x *ListTerminalsResponse) GetTerminals() []*Terminal {
 	return nil
 }
 
+func (x *ListTerminalsResponse) GetLimits() *TerminalLimits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+// TerminalLimits cap the number of open terminals. Open fails with RESOURCE_EXHAUSTED
+// once a limit is reached. A limit of 0 means no limit.
+type TerminalLimits struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	MaxTerminals uint32                 `protobuf:"varint,1,opt,name=max_terminals,json=maxTerminals,proto3" json:"max_terminals,omitempty"`
+	// annotation_key groups terminals by the value of this annotation, e.g. the client that opened them
+	AnnotationKey string `protobuf:"bytes,2,opt,name=annotation_key,json=annotationKey,proto3" json:"annotation_key,omitempty"`
+	// max_per_annotation is the maximum number of terminals sharing a value of annotation_key
+	MaxPerAnnotation uint32 `protobuf:"varint,3,opt,name=max_per_annotation,json=maxPerAnnotation,proto3" json:"max_per_annotation,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TerminalLimits) Reset() {
+	*x = TerminalLimits{}
+	mi := &file_terminal_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TerminalLimits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerminalLimits) ProtoMessage() {}
+
+func (x *TerminalLimits) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminalLimits.ProtoReflect.Descriptor instead.
+func (*TerminalLimits) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TerminalLimits) GetMaxTerminals() uint32 {
+	if x != nil {
+		return x.MaxTerminals
+	}
+	return 0
+}
+
+func (x *TerminalLimits) GetAnnotationKey() string {
+	if x != nil {
+		return x.AnnotationKey
+	}
+	return ""
+}
+
+func (x *TerminalLimits) GetMaxPerAnnotation() uint32 {
+	if x != nil {
+		return x.MaxPerAnnotation
+	}
+	return 0
+}
+
 type ListenTerminalRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Alias         string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// alias is the terminal's alias, its name or an annotation selector
+	// (key=value[,key=value]) matching exactly one terminal
+	Alias string `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	// token is a share token returned by ShareTerminal. It can be omitted if the client
+	// passes its token as "supervisor-token" metadata of the call.
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	// offset resumes listening at the given offset of the terminal's output, e.g. one
+	// returned by Search, instead of replaying the in-memory backlog
+	Offset        int64 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListenTerminalRequest) Reset() {
 	*x = ListenTerminalRequest{}
-	mi := &file_terminal_proto_msgTypes[9]
+	mi := &file_terminal_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -608,7 +939,7 @@ func (x *ListenTerminalRequest) String() string {
 func (*ListenTerminalRequest) ProtoMessage() {}
 
 func (x *ListenTerminalRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_terminal_proto_msgTypes[9]
+	mi := &file_terminal_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -621,7 +952,7 @@ func (x *ListenTerminalRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListenTerminalRequest.ProtoReflect.Descriptor instead.
 func (*ListenTerminalRequest) Descriptor() ([]byte, []int) {
-	return file_terminal_proto_rawDescGZIP(), []int{9}
+	return file_terminal_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ListenTerminalRequest) GetAlias() string {
@@ -631,6 +962,20 @@ func (x *ListenTerminalRequest) GetAlias() string {
 	return ""
 }
 
+func (x *ListenTerminalRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ListenTerminalRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
 type ListenTerminalResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Output:
@@ -638,6 +983,7 @@ type ListenTerminalResponse struct {
 	//	*ListenTerminalResponse_Data
 	//	*ListenTerminalResponse_ExitCode
 	//	*ListenTerminalResponse_Title
+	//	*ListenTerminalResponse_Clipboard
 	Output isListenTerminalResponse_Output `protobuf_oneof:"output"`
 	// only present if output is title
 	TitleSource   TerminalTitleSource `protobuf:"varint,4,opt,name=title_source,json=titleSource,proto3,enum=supervisor.TerminalTitleSource" json:"title_source,omitempty"`
@@ -647,7 +993,7 @@ type ListenTerminalResponse struct {
 
 func (x *ListenTerminalResponse) Reset() {
 	*x = ListenTerminalResponse{}
-	mi := &file_terminal_proto_msgTypes[10]
+	mi := &file_terminal_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -659,7 +1005,7 @@ func (x *ListenTerminalResponse) String() string {
 func (*ListenTerminalResponse) ProtoMessage() {}
 
 func (x *ListenTerminalResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_terminal_proto_msgTypes[10]
+	mi := &file_terminal_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -672,7 +1018,7 @@ func (x *ListenTerminalResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListenTerminalResponse.ProtoReflect.Descriptor instead.
 func (*ListenTerminalResponse) Descriptor() ([]byte, []int) {
-	return file_terminal_proto_rawDescGZIP(), []int{10}
+	return file_terminal_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ListenTerminalResponse) GetOutput() isListenTerminalResponse_Output {
@@ -709,6 +1055,15 @@ func (x *ListenTerminalResponse) GetTitle() string {
 	return ""
 }
 
+func (x *ListenTerminalResponse) GetClipboard() *TerminalClipboard {
+	if x != nil {
+		if x, ok := x.Output.(*ListenTerminalResponse_Clipboard); ok {
+			return x.Clipboard
+		}
+	}
+	return nil
+}
+
 func (x *ListenTerminalResponse) GetTitleSource() TerminalTitleSource {
 	if x != nil {
 		return x.TitleSource
@@ -732,35 +1087,102 @@ type ListenTerminalResponse_Title struct {
 	Title string `protobuf:"bytes,3,opt,name=title,proto3,oneof"`
 }
 
+type ListenTerminalResponse_Clipboard struct {
+	Clipboard *TerminalClipboard `protobuf:"bytes,5,opt,name=clipboard,proto3,oneof"`
+}
+
 func (*ListenTerminalResponse_Data) isListenTerminalResponse_Output() {}
 
 func (*ListenTerminalResponse_ExitCode) isListenTerminalResponse_Output() {}
 
 func (*ListenTerminalResponse_Title) isListenTerminalResponse_Output() {}
 
-type WriteTerminalRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Alias         string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
-	Stdin         []byte                 `protobuf:"bytes,2,opt,name=stdin,proto3" json:"stdin,omitempty"`
+func (*ListenTerminalResponse_Clipboard) isListenTerminalResponse_Output() {}
+
+// TerminalClipboard is a clipboard update a program in the terminal requested
+// through an OSC 52 escape sequence.
+type TerminalClipboard struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// selection lists the targeted selections, e.g. c for the clipboard and p for
+	// the primary selection
+	Selection     string `protobuf:"bytes,1,opt,name=selection,proto3" json:"selection,omitempty"`
+	Content       []byte `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WriteTerminalRequest) Reset() {
-	*x = WriteTerminalRequest{}
-	mi := &file_terminal_proto_msgTypes[11]
+func (x *TerminalClipboard) Reset() {
+	*x = TerminalClipboard{}
+	mi := &file_terminal_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WriteTerminalRequest) String() string {
+func (x *TerminalClipboard) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WriteTerminalRequest) ProtoMessage() {}
+func (*TerminalClipboard) ProtoMessage() {}
+
+func (x *TerminalClipboard) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminalClipboard.ProtoReflect.Descriptor instead.
+func (*TerminalClipboard) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TerminalClipboard) GetSelection() string {
+	if x != nil {
+		return x.Selection
+	}
+	return ""
+}
+
+func (x *TerminalClipboard) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type WriteTerminalRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// alias is the terminal's alias, its name or an annotation selector
+	// (key=value[,key=value]) matching exactly one terminal
+	Alias string `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	Stdin []byte `protobuf:"bytes,2,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	// token is a share token with read_write scope. It can be omitted if the client
+	// passes its token as "supervisor-token" metadata of the call.
+	Token         string `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteTerminalRequest) Reset() {
+	*x = WriteTerminalRequest{}
+	mi := &file_terminal_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteTerminalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteTerminalRequest) ProtoMessage() {}
 
 func (x *WriteTerminalRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_terminal_proto_msgTypes[11]
+	mi := &file_terminal_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -773,7 +1195,7 @@ func (x *WriteTerminalRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WriteTerminalRequest.ProtoReflect.Descriptor instead.
 func (*WriteTerminalRequest) Descriptor() ([]byte, []int) {
-	return file_terminal_proto_rawDescGZIP(), []int{11}
+	return file_terminal_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *WriteTerminalRequest) GetAlias() string {
@@ -790,6 +1212,13 @@ func (x *WriteTerminalRequest) GetStdin() []byte {
 	return nil
 }
 
+func (x *WriteTerminalRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
 type WriteTerminalResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BytesWritten  uint32                 `protobuf:"varint,1,opt,name=bytes_written,json=bytesWritten,proto3" json:"bytes_written,omitempty"`
@@ -799,7 +1228,7 @@ type WriteTerminalResponse struct {
 
 func (x *WriteTerminalResponse) Reset() {
 	*x = WriteTerminalResponse{}
-	mi := &file_terminal_proto_msgTypes[12]
+	mi := &file_terminal_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -811,7 +1240,7 @@ func (x *WriteTerminalResponse) String() string {
 func (*WriteTerminalResponse) ProtoMessage() {}
 
 func (x *WriteTerminalResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_terminal_proto_msgTypes[12]
+	mi := &file_terminal_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -824,7 +1253,7 @@ func (x *WriteTerminalResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WriteTerminalResponse.ProtoReflect.Descriptor instead.
 func (*WriteTerminalResponse) Descriptor() ([]byte, []int) {
-	return file_terminal_proto_rawDescGZIP(), []int{12}
+	return file_terminal_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *WriteTerminalResponse) GetBytesWritten() uint32 {
@@ -853,7 +1282,7 @@ type SetTerminalSizeRequest struct {
 
 func (x *SetTerminalSizeRequest) Reset() {
 	*x = SetTerminalSizeRequest{}
-	mi := &file_terminal_proto_msgTypes[13]
+	mi := &file_terminal_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -865,7 +1294,7 @@ func (x *SetTerminalSizeRequest) String() string {
 func (*SetTerminalSizeRequest) ProtoMessage() {}
 
 func (x *SetTerminalSizeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_terminal_proto_msgTypes[13]
+	mi := &file_terminal_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -878,7 +1307,7 @@ func (x *SetTerminalSizeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetTerminalSizeRequest.ProtoReflect.Descriptor instead.
 func (*SetTerminalSizeRequest) Descriptor() ([]byte, []int) {
-	return file_terminal_proto_rawDescGZIP(), []int{13}
+	return file_terminal_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *SetTerminalSizeRequest) GetAlias() string {
@@ -944,7 +1373,7 @@ type SetTerminalSizeResponse struct {
 
 func (x *SetTerminalSizeResponse) Reset() {
 	*x = SetTerminalSizeResponse{}
-	mi := &file_terminal_proto_msgTypes[14]
+	mi := &file_terminal_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -956,7 +1385,7 @@ func (x *SetTerminalSizeResponse) String() string {
 func (*SetTerminalSizeResponse) ProtoMessage() {}
 
 func (x *SetTerminalSizeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_terminal_proto_msgTypes[14]
+	mi := &file_terminal_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -969,7 +1398,7 @@ func (x *SetTerminalSizeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetTerminalSizeResponse.ProtoReflect.Descriptor instead.
 func (*SetTerminalSizeResponse) Descriptor() ([]byte, []int) {
-	return file_terminal_proto_rawDescGZIP(), []int{14}
+	return file_terminal_proto_rawDescGZIP(), []int{17}
 }
 
 type SetTerminalTitleRequest struct {
@@ -983,7 +1412,7 @@ type SetTerminalTitleRequest struct {
 
 func (x *SetTerminalTitleRequest) Reset() {
 	*x = SetTerminalTitleRequest{}
-	mi := &file_terminal_proto_msgTypes[15]
+	mi := &file_terminal_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -995,7 +1424,7 @@ func (x *SetTerminalTitleRequest) String() string {
 func (*SetTerminalTitleRequest) ProtoMessage() {}
 
 func (x *SetTerminalTitleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_terminal_proto_msgTypes[15]
+	mi := &file_terminal_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1008,7 +1437,7 @@ func (x *SetTerminalTitleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetTerminalTitleRequest.ProtoReflect.Descriptor instead.
 func (*SetTerminalTitleRequest) Descriptor() ([]byte, []int) {
-	return file_terminal_proto_rawDescGZIP(), []int{15}
+	return file_terminal_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *SetTerminalTitleRequest) GetAlias() string {
@@ -1033,7 +1462,7 @@ type SetTerminalTitleResponse struct {
 
 func (x *SetTerminalTitleResponse) Reset() {
 	*x = SetTerminalTitleResponse{}
-	mi := &file_terminal_proto_msgTypes[16]
+	mi := &file_terminal_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1045,7 +1474,7 @@ func (x *SetTerminalTitleResponse) String() string {
 func (*SetTerminalTitleResponse) ProtoMessage() {}
 
 func (x *SetTerminalTitleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_terminal_proto_msgTypes[16]
+	mi := &file_terminal_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1058,7 +1487,7 @@ func (x *SetTerminalTitleResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetTerminalTitleResponse.ProtoReflect.Descriptor instead.
 func (*SetTerminalTitleResponse) Descriptor() ([]byte, []int) {
-	return file_terminal_proto_rawDescGZIP(), []int{16}
+	return file_terminal_proto_rawDescGZIP(), []int{19}
 }
 
 type UpdateTerminalAnnotationsRequest struct {
@@ -1074,7 +1503,7 @@ type UpdateTerminalAnnotationsRequest struct {
 
 func (x *UpdateTerminalAnnotationsRequest) Reset() {
 	*x = UpdateTerminalAnnotationsRequest{}
-	mi := &file_terminal_proto_msgTypes[17]
+	mi := &file_terminal_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1086,7 +1515,7 @@ func (x *UpdateTerminalAnnotationsRequest) String() string {
 func (*UpdateTerminalAnnotationsRequest) ProtoMessage() {}
 
 func (x *UpdateTerminalAnnotationsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_terminal_proto_msgTypes[17]
+	mi := &file_terminal_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1099,7 +1528,7 @@ func (x *UpdateTerminalAnnotationsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateTerminalAnnotationsRequest.ProtoReflect.Descriptor instead.
 func (*UpdateTerminalAnnotationsRequest) Descriptor() ([]byte, []int) {
-	return file_terminal_proto_rawDescGZIP(), []int{17}
+	return file_terminal_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *UpdateTerminalAnnotationsRequest) GetAlias() string {
@@ -1131,7 +1560,7 @@ type UpdateTerminalAnnotationsResponse struct {
 
 func (x *UpdateTerminalAnnotationsResponse) Reset() {
 	*x = UpdateTerminalAnnotationsResponse{}
-	mi := &file_terminal_proto_msgTypes[18]
+	mi := &file_terminal_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1143,7 +1572,7 @@ func (x *UpdateTerminalAnnotationsResponse) String() string {
 func (*UpdateTerminalAnnotationsResponse) ProtoMessage() {}
 
 func (x *UpdateTerminalAnnotationsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_terminal_proto_msgTypes[18]
+	mi := &file_terminal_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1156,202 +1585,1934 @@ func (x *UpdateTerminalAnnotationsResponse) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use UpdateTerminalAnnotationsResponse.ProtoReflect.Descriptor instead.
 func (*UpdateTerminalAnnotationsResponse) Descriptor() ([]byte, []int) {
-	return file_terminal_proto_rawDescGZIP(), []int{18}
+	return file_terminal_proto_rawDescGZIP(), []int{21}
 }
 
-var File_terminal_proto protoreflect.FileDescriptor
+type SetTerminalRecordingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alias         string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	Enabled       bool                   `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_terminal_proto_rawDesc = "" +
-	"\n" +
-	"\x0eterminal.proto\x12\n" +
-	"supervisor\"l\n" +
-	"\fTerminalSize\x12\x12\n" +
-	"\x04rows\x18\x01 \x01(\rR\x04rows\x12\x12\n" +
-	"\x04cols\x18\x02 \x01(\rR\x04cols\x12\x18\n" +
-	"\awidthPx\x18\x03 \x01(\rR\awidthPx\x12\x1a\n" +
-	"\bheightPx\x18\x04 \x01(\rR\bheightPx\"\x9a\x03\n" +
-	"\x13OpenTerminalRequest\x12\x18\n" +
-	"\aworkdir\x18\x01 \x01(\tR\aworkdir\x12:\n" +
-	"\x03env\x18\x02 \x03(\v2(.supervisor.OpenTerminalRequest.EnvEntryR\x03env\x12R\n" +
-	"\vannotations\x18\x03 \x03(\v20.supervisor.OpenTerminalRequest.AnnotationsEntryR\vannotations\x12\x14\n" +
-	"\x05shell\x18\x04 \x01(\tR\x05shell\x12\x1d\n" +
-	"\n" +
-	"shell_args\x18\x05 \x03(\tR\tshellArgs\x12,\n" +
-	"\x04size\x18\x06 \x01(\v2\x18.supervisor.TerminalSizeR\x04size\x1a6\n" +
-	"\bEnvEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a>\n" +
-	"\x10AnnotationsEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"m\n" +
-	"\x14OpenTerminalResponse\x120\n" +
-	"\bterminal\x18\x01 \x01(\v2\x14.supervisor.TerminalR\bterminal\x12#\n" +
-	"\rstarter_token\x18\x02 \x01(\tR\fstarterToken\"T\n" +
-	"\x17ShutdownTerminalRequest\x12\x14\n" +
-	"\x05alias\x18\x01 \x01(\tR\x05alias\x12#\n" +
-	"\rforce_success\x18\x02 \x01(\bR\fforceSuccess\"\x1a\n" +
-	"\x18ShutdownTerminalResponse\"\x81\x03\n" +
-	"\bTerminal\x12\x14\n" +
-	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x18\n" +
-	"\acommand\x18\x02 \x03(\tR\acommand\x12\x14\n" +
-	"\x05title\x18\x03 \x01(\tR\x05title\x12\x10\n" +
-	"\x03pid\x18\x04 \x01(\x03R\x03pid\x12'\n" +
-	"\x0finitial_workdir\x18\x05 \x01(\tR\x0einitialWorkdir\x12'\n" +
-	"\x0fcurrent_workdir\x18\x06 \x01(\tR\x0ecurrentWorkdir\x12G\n" +
-	"\vannotations\x18\a \x03(\v2%.supervisor.Terminal.AnnotationsEntryR\vannotations\x12B\n" +
-	"\ftitle_source\x18\b \x01(\x0e2\x1f.supervisor.TerminalTitleSourceR\vtitleSource\x1a>\n" +
-	"\x10AnnotationsEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"*\n" +
-	"\x12GetTerminalRequest\x12\x14\n" +
-	"\x05alias\x18\x01 \x01(\tR\x05alias\"\x16\n" +
-	"\x14ListTerminalsRequest\"K\n" +
-	"\x15ListTerminalsResponse\x122\n" +
-	"\tterminals\x18\x01 \x03(\v2\x14.supervisor.TerminalR\tterminals\"-\n" +
-	"\x15ListenTerminalRequest\x12\x14\n" +
-	"\x05alias\x18\x01 \x01(\tR\x05alias\"\xb3\x01\n" +
-	"\x16ListenTerminalResponse\x12\x14\n" +
-	"\x04data\x18\x01 \x01(\fH\x00R\x04data\x12\x1d\n" +
-	"\texit_code\x18\x02 \x01(\x05H\x00R\bexitCode\x12\x16\n" +
-	"\x05title\x18\x03 \x01(\tH\x00R\x05title\x12B\n" +
-	"\ftitle_source\x18\x04 \x01(\x0e2\x1f.supervisor.TerminalTitleSourceR\vtitleSourceB\b\n" +
-	"\x06output\"B\n" +
-	"\x14WriteTerminalRequest\x12\x14\n" +
-	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x14\n" +
-	"\x05stdin\x18\x02 \x01(\fR\x05stdin\"<\n" +
-	"\x15WriteTerminalResponse\x12#\n" +
-	"\rbytes_written\x18\x01 \x01(\rR\fbytesWritten\"\x98\x01\n" +
-	"\x16SetTerminalSizeRequest\x12\x14\n" +
-	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x16\n" +
-	"\x05token\x18\x02 \x01(\tH\x00R\x05token\x12\x16\n" +
-	"\x05force\x18\x03 \x01(\bH\x00R\x05force\x12,\n" +
-	"\x04size\x18\x04 \x01(\v2\x18.supervisor.TerminalSizeR\x04sizeB\n" +
-	"\n" +
-	"\bpriority\"\x19\n" +
-	"\x17SetTerminalSizeResponse\"E\n" +
-	"\x17SetTerminalTitleRequest\x12\x14\n" +
-	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\"\x1a\n" +
-	"\x18SetTerminalTitleResponse\"\xe3\x01\n" +
-	" UpdateTerminalAnnotationsRequest\x12\x14\n" +
-	"\x05alias\x18\x01 \x01(\tR\x05alias\x12S\n" +
-	"\achanged\x18\x02 \x03(\v29.supervisor.UpdateTerminalAnnotationsRequest.ChangedEntryR\achanged\x12\x18\n" +
-	"\adeleted\x18\x03 \x03(\tR\adeleted\x1a:\n" +
-	"\fChangedEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"#\n" +
-	"!UpdateTerminalAnnotationsResponse*+\n" +
-	"\x13TerminalTitleSource\x12\v\n" +
-	"\aprocess\x10\x00\x12\a\n" +
-	"\x03api\x10\x012\x8d\x06\n" +
-	"\x0fTerminalService\x12K\n" +
-	"\x04Open\x12\x1f.supervisor.OpenTerminalRequest\x1a .supervisor.OpenTerminalResponse\"\x00\x12W\n" +
-	"\bShutdown\x12#.supervisor.ShutdownTerminalRequest\x1a$.supervisor.ShutdownTerminalResponse\"\x00\x12=\n" +
-	"\x03Get\x12\x1e.supervisor.GetTerminalRequest\x1a\x14.supervisor.Terminal\"\x00\x12M\n" +
-	"\x04List\x12 .supervisor.ListTerminalsRequest\x1a!.supervisor.ListTerminalsResponse\"\x00\x12S\n" +
-	"\x06Listen\x12!.supervisor.ListenTerminalRequest\x1a\".supervisor.ListenTerminalResponse\"\x000\x01\x12N\n" +
-	"\x05Write\x12 .supervisor.WriteTerminalRequest\x1a!.supervisor.WriteTerminalResponse\"\x00\x12T\n" +
-	"\aSetSize\x12\".supervisor.SetTerminalSizeRequest\x1a#.supervisor.SetTerminalSizeResponse\"\x00\x12W\n" +
-	"\bSetTitle\x12#.supervisor.SetTerminalTitleRequest\x1a$.supervisor.SetTerminalTitleResponse\"\x00\x12r\n" +
-	"\x11UpdateAnnotations\x12,.supervisor.UpdateTerminalAnnotationsRequest\x1a-.supervisor.UpdateTerminalAnnotationsResponse\"\x00B\x10Z\x0esupervisor/apib\x06proto3"
+func (x *SetTerminalRecordingRequest) Reset() {
+	*x = SetTerminalRecordingRequest{}
+	mi := &file_terminal_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-var (
-	file_terminal_proto_rawDescOnce sync.Once
-	file_terminal_proto_rawDescData []byte
-)
+func (x *SetTerminalRecordingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-func file_terminal_proto_rawDescGZIP() []byte {
-	file_terminal_proto_rawDescOnce.Do(func() {
-		file_terminal_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_terminal_proto_rawDesc), len(file_terminal_proto_rawDesc)))
-	})
-	return file_terminal_proto_rawDescData
+func (*SetTerminalRecordingRequest) ProtoMessage() {}
+
+func (x *SetTerminalRecordingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var file_terminal_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_terminal_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
-var file_terminal_proto_goTypes = []any{
-	(TerminalTitleSource)(0),                  // 0: supervisor.TerminalTitleSource
-	(*TerminalSize)(nil),                      // 1: supervisor.TerminalSize
-	(*OpenTerminalRequest)(nil),               // 2: supervisor.OpenTerminalRequest
-	(*OpenTerminalResponse)(nil),              // 3: supervisor.OpenTerminalResponse
-	(*ShutdownTerminalRequest)(nil),           // 4: supervisor.ShutdownTerminalRequest
-	(*ShutdownTerminalResponse)(nil),          // 5: supervisor.ShutdownTerminalResponse
-	(*Terminal)(nil),                          // 6: supervisor.Terminal
-	(*GetTerminalRequest)(nil),                // 7: supervisor.GetTerminalRequest
-	(*ListTerminalsRequest)(nil),              // 8: supervisor.ListTerminalsRequest
-	(*ListTerminalsResponse)(nil),             // 9: supervisor.ListTerminalsResponse
-	(*ListenTerminalRequest)(nil),             // 10: supervisor.ListenTerminalRequest
-	(*ListenTerminalResponse)(nil),            // 11: supervisor.ListenTerminalResponse
-	(*WriteTerminalRequest)(nil),              // 12: supervisor.WriteTerminalRequest
-	(*WriteTerminalResponse)(nil),             // 13: supervisor.WriteTerminalResponse
-	(*SetTerminalSizeRequest)(nil),            // 14: supervisor.SetTerminalSizeRequest
-	(*SetTerminalSizeResponse)(nil),           // 15: supervisor.SetTerminalSizeResponse
-	(*SetTerminalTitleRequest)(nil),           // 16: supervisor.SetTerminalTitleRequest
-	(*SetTerminalTitleResponse)(nil),          // 17: supervisor.SetTerminalTitleResponse
-	(*UpdateTerminalAnnotationsRequest)(nil),  // 18: supervisor.UpdateTerminalAnnotationsRequest
-	(*UpdateTerminalAnnotationsResponse)(nil), // 19: supervisor.UpdateTerminalAnnotationsResponse
-	nil, // 20: supervisor.OpenTerminalRequest.EnvEntry
-	nil, // 21: supervisor.OpenTerminalRequest.AnnotationsEntry
-	nil, // 22: supervisor.Terminal.AnnotationsEntry
-	nil, // 23: supervisor.UpdateTerminalAnnotationsRequest.ChangedEntry
+// Deprecated: Use SetTerminalRecordingRequest.ProtoReflect.Descriptor instead.
+func (*SetTerminalRecordingRequest) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{22}
 }
-var file_terminal_proto_depIdxs = []int32{
-	20, // 0: supervisor.OpenTerminalRequest.env:type_name -> supervisor.OpenTerminalRequest.EnvEntry
-	21, // 1: supervisor.OpenTerminalRequest.annotations:type_name -> supervisor.OpenTerminalRequest.AnnotationsEntry
-	1,  // 2: supervisor.OpenTerminalRequest.size:type_name -> supervisor.TerminalSize
-	6,  // 3: supervisor.OpenTerminalResponse.terminal:type_name -> supervisor.Terminal
-	22, // 4: supervisor.Terminal.annotations:type_name -> supervisor.Terminal.AnnotationsEntry
-	0,  // 5: supervisor.Terminal.title_source:type_name -> supervisor.TerminalTitleSource
-	6,  // 6: supervisor.ListTerminalsResponse.terminals:type_name -> supervisor.Terminal
-	0,  // 7: supervisor.ListenTerminalResponse.title_source:type_name -> supervisor.TerminalTitleSource
-	1,  // 8: supervisor.SetTerminalSizeRequest.size:type_name -> supervisor.TerminalSize
-	23, // 9: supervisor.UpdateTerminalAnnotationsRequest.changed:type_name -> supervisor.UpdateTerminalAnnotationsRequest.ChangedEntry
-	2,  // 10: supervisor.TerminalService.Open:input_type -> supervisor.OpenTerminalRequest
-	4,  // 11: supervisor.TerminalService.Shutdown:input_type -> supervisor.ShutdownTerminalRequest
-	7,  // 12: supervisor.TerminalService.Get:input_type -> supervisor.GetTerminalRequest
-	8,  // 13: supervisor.TerminalService.List:input_type -> supervisor.ListTerminalsRequest
-	10, // 14: supervisor.TerminalService.Listen:input_type -> supervisor.ListenTerminalRequest
-	12, // 15: supervisor.TerminalService.Write:input_type -> supervisor.WriteTerminalRequest
-	14, // 16: supervisor.TerminalService.SetSize:input_type -> supervisor.SetTerminalSizeRequest
-	16, // 17: supervisor.TerminalService.SetTitle:input_type -> supervisor.SetTerminalTitleRequest
-	18, // 18: supervisor.TerminalService.UpdateAnnotations:input_type -> supervisor.UpdateTerminalAnnotationsRequest
-	3,  // 19: supervisor.TerminalService.Open:output_type -> supervisor.OpenTerminalResponse
-	5,  // 20: supervisor.TerminalService.Shutdown:output_type -> supervisor.ShutdownTerminalResponse
-	6,  // 21: supervisor.TerminalService.Get:output_type -> supervisor.Terminal
-	9,  // 22: supervisor.TerminalService.List:output_type -> supervisor.ListTerminalsResponse
-	11, // 23: supervisor.TerminalService.Listen:output_type -> supervisor.ListenTerminalResponse
-	13, // 24: supervisor.TerminalService.Write:output_type -> supervisor.WriteTerminalResponse
-	15, // 25: supervisor.TerminalService.SetSize:output_type -> supervisor.SetTerminalSizeResponse
-	17, // 26: supervisor.TerminalService.SetTitle:output_type -> supervisor.SetTerminalTitleResponse
-	19, // 27: supervisor.TerminalService.UpdateAnnotations:output_type -> supervisor.UpdateTerminalAnnotationsResponse
-	19, // [19:28] is the sub-list for method output_type
-	10, // [10:19] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+
+func (x *SetTerminalRecordingRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
 }
 
-func init() { file_terminal_proto_init() }
-func file_terminal_proto_init() {
-	if File_terminal_proto != nil {
-		return
+func (x *SetTerminalRecordingRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
 	}
-	file_terminal_proto_msgTypes[10].OneofWrappers = []any{
-		(*ListenTerminalResponse_Data)(nil),
-		(*ListenTerminalResponse_ExitCode)(nil),
-		(*ListenTerminalResponse_Title)(nil),
+	return false
+}
+
+type SetTerminalRecordingResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// path of the asciicast file, empty if recording has been stopped
+	Path          string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetTerminalRecordingResponse) Reset() {
+	*x = SetTerminalRecordingResponse{}
+	mi := &file_terminal_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetTerminalRecordingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTerminalRecordingResponse) ProtoMessage() {}
+
+func (x *SetTerminalRecordingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	file_terminal_proto_msgTypes[13].OneofWrappers = []any{
-		(*SetTerminalSizeRequest_Token)(nil),
-		(*SetTerminalSizeRequest_Force)(nil),
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTerminalRecordingResponse.ProtoReflect.Descriptor instead.
+func (*SetTerminalRecordingResponse) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SetTerminalRecordingResponse) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type ExportTerminalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alias         string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	Format        TerminalExportFormat   `protobuf:"varint,2,opt,name=format,proto3,enum=supervisor.TerminalExportFormat" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportTerminalRequest) Reset() {
+	*x = ExportTerminalRequest{}
+	mi := &file_terminal_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportTerminalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTerminalRequest) ProtoMessage() {}
+
+func (x *ExportTerminalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTerminalRequest.ProtoReflect.Descriptor instead.
+func (*ExportTerminalRequest) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ExportTerminalRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *ExportTerminalRequest) GetFormat() TerminalExportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return TerminalExportFormat_text
+}
+
+type ExportTerminalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Content       []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportTerminalResponse) Reset() {
+	*x = ExportTerminalResponse{}
+	mi := &file_terminal_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportTerminalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTerminalResponse) ProtoMessage() {}
+
+func (x *ExportTerminalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTerminalResponse.ProtoReflect.Descriptor instead.
+func (*ExportTerminalResponse) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ExportTerminalResponse) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type ShareTerminalRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Alias string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	Scope TerminalAccessScope    `protobuf:"varint,2,opt,name=scope,proto3,enum=supervisor.TerminalAccessScope" json:"scope,omitempty"`
+	// ttl_seconds is the lifetime of the token, defaults to one hour
+	TtlSeconds int64 `protobuf:"varint,3,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	// name identifies the share in the list of viewers, e.g. the name of a teammate
+	Name          string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareTerminalRequest) Reset() {
+	*x = ShareTerminalRequest{}
+	mi := &file_terminal_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareTerminalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareTerminalRequest) ProtoMessage() {}
+
+func (x *ShareTerminalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareTerminalRequest.ProtoReflect.Descriptor instead.
+func (*ShareTerminalRequest) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ShareTerminalRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *ShareTerminalRequest) GetScope() TerminalAccessScope {
+	if x != nil {
+		return x.Scope
+	}
+	return TerminalAccessScope_read_only
+}
+
+func (x *ShareTerminalRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *ShareTerminalRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ShareTerminalResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Token string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Scope TerminalAccessScope    `protobuf:"varint,2,opt,name=scope,proto3,enum=supervisor.TerminalAccessScope" json:"scope,omitempty"`
+	// expires_at is the unix timestamp in seconds the token expires at
+	ExpiresAt     int64 `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareTerminalResponse) Reset() {
+	*x = ShareTerminalResponse{}
+	mi := &file_terminal_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareTerminalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareTerminalResponse) ProtoMessage() {}
+
+func (x *ShareTerminalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareTerminalResponse.ProtoReflect.Descriptor instead.
+func (*ShareTerminalResponse) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ShareTerminalResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ShareTerminalResponse) GetScope() TerminalAccessScope {
+	if x != nil {
+		return x.Scope
+	}
+	return TerminalAccessScope_read_only
+}
+
+func (x *ShareTerminalResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type RevokeTerminalShareRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alias         string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeTerminalShareRequest) Reset() {
+	*x = RevokeTerminalShareRequest{}
+	mi := &file_terminal_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeTerminalShareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeTerminalShareRequest) ProtoMessage() {}
+
+func (x *RevokeTerminalShareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeTerminalShareRequest.ProtoReflect.Descriptor instead.
+func (*RevokeTerminalShareRequest) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RevokeTerminalShareRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *RevokeTerminalShareRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type RevokeTerminalShareResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeTerminalShareResponse) Reset() {
+	*x = RevokeTerminalShareResponse{}
+	mi := &file_terminal_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeTerminalShareResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeTerminalShareResponse) ProtoMessage() {}
+
+func (x *RevokeTerminalShareResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeTerminalShareResponse.ProtoReflect.Descriptor instead.
+func (*RevokeTerminalShareResponse) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{29}
+}
+
+type ReadTerminalBacklogRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Alias string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	// from is the offset of the first byte to read
+	From int64 `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	// to is the offset right after the last byte to read. If 0 or if the range
+	// exceeds 1 MiB, at most 1 MiB is returned.
+	To int64 `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	// token is a share token returned by ShareTerminal. It can be omitted if the client
+	// passes its token as "supervisor-token" metadata of the call.
+	Token         string `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadTerminalBacklogRequest) Reset() {
+	*x = ReadTerminalBacklogRequest{}
+	mi := &file_terminal_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadTerminalBacklogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadTerminalBacklogRequest) ProtoMessage() {}
+
+func (x *ReadTerminalBacklogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadTerminalBacklogRequest.ProtoReflect.Descriptor instead.
+func (*ReadTerminalBacklogRequest) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ReadTerminalBacklogRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *ReadTerminalBacklogRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *ReadTerminalBacklogRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *ReadTerminalBacklogRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type ReadTerminalBacklogResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Data  []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// from and to are the offsets of the returned data, the requested range is
+	// clipped to the available output
+	From int64 `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To   int64 `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	// first is the offset of the oldest output still available
+	First int64 `protobuf:"varint,4,opt,name=first,proto3" json:"first,omitempty"`
+	// total is the number of bytes the terminal has written so far
+	Total         int64 `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadTerminalBacklogResponse) Reset() {
+	*x = ReadTerminalBacklogResponse{}
+	mi := &file_terminal_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadTerminalBacklogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadTerminalBacklogResponse) ProtoMessage() {}
+
+func (x *ReadTerminalBacklogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadTerminalBacklogResponse.ProtoReflect.Descriptor instead.
+func (*ReadTerminalBacklogResponse) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ReadTerminalBacklogResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ReadTerminalBacklogResponse) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *ReadTerminalBacklogResponse) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *ReadTerminalBacklogResponse) GetFirst() int64 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+func (x *ReadTerminalBacklogResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type BroadcastTerminalRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// aliases of the terminals to write to
+	Aliases []string `protobuf:"bytes,1,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	// selector selects all terminals whose annotations contain all given key/value pairs,
+	// in addition to the terminals listed in aliases
+	Selector      map[string]string `protobuf:"bytes,2,rep,name=selector,proto3" json:"selector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Stdin         []byte            `protobuf:"bytes,3,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BroadcastTerminalRequest) Reset() {
+	*x = BroadcastTerminalRequest{}
+	mi := &file_terminal_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BroadcastTerminalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastTerminalRequest) ProtoMessage() {}
+
+func (x *BroadcastTerminalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastTerminalRequest.ProtoReflect.Descriptor instead.
+func (*BroadcastTerminalRequest) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *BroadcastTerminalRequest) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *BroadcastTerminalRequest) GetSelector() map[string]string {
+	if x != nil {
+		return x.Selector
+	}
+	return nil
+}
+
+func (x *BroadcastTerminalRequest) GetStdin() []byte {
+	if x != nil {
+		return x.Stdin
+	}
+	return nil
+}
+
+type BroadcastTerminalResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// results contains an entry for every selected terminal
+	Results       []*TerminalBroadcastResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BroadcastTerminalResponse) Reset() {
+	*x = BroadcastTerminalResponse{}
+	mi := &file_terminal_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BroadcastTerminalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastTerminalResponse) ProtoMessage() {}
+
+func (x *BroadcastTerminalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastTerminalResponse.ProtoReflect.Descriptor instead.
+func (*BroadcastTerminalResponse) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *BroadcastTerminalResponse) GetResults() []*TerminalBroadcastResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type TerminalBroadcastResult struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Alias        string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	BytesWritten uint32                 `protobuf:"varint,2,opt,name=bytes_written,json=bytesWritten,proto3" json:"bytes_written,omitempty"`
+	// code is a gRPC status code, it is 0 if the input was written successfully
+	Code          uint32 `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TerminalBroadcastResult) Reset() {
+	*x = TerminalBroadcastResult{}
+	mi := &file_terminal_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TerminalBroadcastResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerminalBroadcastResult) ProtoMessage() {}
+
+func (x *TerminalBroadcastResult) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminalBroadcastResult.ProtoReflect.Descriptor instead.
+func (*TerminalBroadcastResult) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *TerminalBroadcastResult) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *TerminalBroadcastResult) GetBytesWritten() uint32 {
+	if x != nil {
+		return x.BytesWritten
+	}
+	return 0
+}
+
+func (x *TerminalBroadcastResult) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *TerminalBroadcastResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SearchTerminalsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// alias of the terminal to search, all terminals are searched if empty
+	Alias string `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	// pattern is a regular expression in RE2 syntax. It is matched against single
+	// lines of output with escape sequences removed.
+	Pattern string `protobuf:"bytes,2,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	// context is the number of lines returned before and after each match
+	Context    uint32 `protobuf:"varint,3,opt,name=context,proto3" json:"context,omitempty"`
+	IgnoreCase bool   `protobuf:"varint,4,opt,name=ignore_case,json=ignoreCase,proto3" json:"ignore_case,omitempty"`
+	// max_matches limits the number of matches, defaults to 1000
+	MaxMatches    uint32 `protobuf:"varint,5,opt,name=max_matches,json=maxMatches,proto3" json:"max_matches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchTerminalsRequest) Reset() {
+	*x = SearchTerminalsRequest{}
+	mi := &file_terminal_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchTerminalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchTerminalsRequest) ProtoMessage() {}
+
+func (x *SearchTerminalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchTerminalsRequest.ProtoReflect.Descriptor instead.
+func (*SearchTerminalsRequest) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SearchTerminalsRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *SearchTerminalsRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *SearchTerminalsRequest) GetContext() uint32 {
+	if x != nil {
+		return x.Context
+	}
+	return 0
+}
+
+func (x *SearchTerminalsRequest) GetIgnoreCase() bool {
+	if x != nil {
+		return x.IgnoreCase
+	}
+	return false
+}
+
+func (x *SearchTerminalsRequest) GetMaxMatches() uint32 {
+	if x != nil {
+		return x.MaxMatches
+	}
+	return 0
+}
+
+type SearchTerminalsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Matches []*TerminalSearchMatch `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	// truncated is true if more matches than max_matches were found
+	Truncated     bool `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchTerminalsResponse) Reset() {
+	*x = SearchTerminalsResponse{}
+	mi := &file_terminal_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchTerminalsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchTerminalsResponse) ProtoMessage() {}
+
+func (x *SearchTerminalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchTerminalsResponse.ProtoReflect.Descriptor instead.
+func (*SearchTerminalsResponse) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SearchTerminalsResponse) GetMatches() []*TerminalSearchMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *SearchTerminalsResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+type TerminalSearchMatch struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Alias string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	Title string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	// line is the matching line without escape sequences
+	Line   string   `protobuf:"bytes,3,opt,name=line,proto3" json:"line,omitempty"`
+	Before []string `protobuf:"bytes,4,rep,name=before,proto3" json:"before,omitempty"`
+	After  []string `protobuf:"bytes,5,rep,name=after,proto3" json:"after,omitempty"`
+	// offset is the offset of the matching line in the terminal's output.
+	// It can be passed to Listen and ReadBacklog.
+	Offset int64 `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	// match_offset is the offset of the match in the terminal's output
+	MatchOffset   int64 `protobuf:"varint,7,opt,name=match_offset,json=matchOffset,proto3" json:"match_offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TerminalSearchMatch) Reset() {
+	*x = TerminalSearchMatch{}
+	mi := &file_terminal_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TerminalSearchMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerminalSearchMatch) ProtoMessage() {}
+
+func (x *TerminalSearchMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminalSearchMatch.ProtoReflect.Descriptor instead.
+func (*TerminalSearchMatch) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *TerminalSearchMatch) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *TerminalSearchMatch) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *TerminalSearchMatch) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+func (x *TerminalSearchMatch) GetBefore() []string {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *TerminalSearchMatch) GetAfter() []string {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *TerminalSearchMatch) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *TerminalSearchMatch) GetMatchOffset() int64 {
+	if x != nil {
+		return x.MatchOffset
+	}
+	return 0
+}
+
+type MultiplexTerminalRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Request:
+	//
+	//	*MultiplexTerminalRequest_Subscribe
+	//	*MultiplexTerminalRequest_Unsubscribe
+	//	*MultiplexTerminalRequest_Write
+	//	*MultiplexTerminalRequest_Ack
+	Request       isMultiplexTerminalRequest_Request `protobuf_oneof:"request"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiplexTerminalRequest) Reset() {
+	*x = MultiplexTerminalRequest{}
+	mi := &file_terminal_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiplexTerminalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiplexTerminalRequest) ProtoMessage() {}
+
+func (x *MultiplexTerminalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiplexTerminalRequest.ProtoReflect.Descriptor instead.
+func (*MultiplexTerminalRequest) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *MultiplexTerminalRequest) GetRequest() isMultiplexTerminalRequest_Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *MultiplexTerminalRequest) GetSubscribe() *TerminalSubscribe {
+	if x != nil {
+		if x, ok := x.Request.(*MultiplexTerminalRequest_Subscribe); ok {
+			return x.Subscribe
+		}
+	}
+	return nil
+}
+
+func (x *MultiplexTerminalRequest) GetUnsubscribe() *TerminalUnsubscribe {
+	if x != nil {
+		if x, ok := x.Request.(*MultiplexTerminalRequest_Unsubscribe); ok {
+			return x.Unsubscribe
+		}
+	}
+	return nil
+}
+
+func (x *MultiplexTerminalRequest) GetWrite() *TerminalStreamWrite {
+	if x != nil {
+		if x, ok := x.Request.(*MultiplexTerminalRequest_Write); ok {
+			return x.Write
+		}
+	}
+	return nil
+}
+
+func (x *MultiplexTerminalRequest) GetAck() *TerminalStreamAck {
+	if x != nil {
+		if x, ok := x.Request.(*MultiplexTerminalRequest_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
+}
+
+type isMultiplexTerminalRequest_Request interface {
+	isMultiplexTerminalRequest_Request()
+}
+
+type MultiplexTerminalRequest_Subscribe struct {
+	Subscribe *TerminalSubscribe `protobuf:"bytes,1,opt,name=subscribe,proto3,oneof"`
+}
+
+type MultiplexTerminalRequest_Unsubscribe struct {
+	Unsubscribe *TerminalUnsubscribe `protobuf:"bytes,2,opt,name=unsubscribe,proto3,oneof"`
+}
+
+type MultiplexTerminalRequest_Write struct {
+	Write *TerminalStreamWrite `protobuf:"bytes,3,opt,name=write,proto3,oneof"`
+}
+
+type MultiplexTerminalRequest_Ack struct {
+	Ack *TerminalStreamAck `protobuf:"bytes,4,opt,name=ack,proto3,oneof"`
+}
+
+func (*MultiplexTerminalRequest_Subscribe) isMultiplexTerminalRequest_Request() {}
+
+func (*MultiplexTerminalRequest_Unsubscribe) isMultiplexTerminalRequest_Request() {}
+
+func (*MultiplexTerminalRequest_Write) isMultiplexTerminalRequest_Request() {}
+
+func (*MultiplexTerminalRequest_Ack) isMultiplexTerminalRequest_Request() {}
+
+type TerminalSubscribe struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Alias string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	// token is a share token returned by ShareTerminal. It can be omitted if the client
+	// passes its token as "supervisor-token" metadata of the call.
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	// window is the number of output bytes the server may send before the client
+	// has to acknowledge them, defaults to 256 KiB
+	Window        uint32 `protobuf:"varint,3,opt,name=window,proto3" json:"window,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TerminalSubscribe) Reset() {
+	*x = TerminalSubscribe{}
+	mi := &file_terminal_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TerminalSubscribe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerminalSubscribe) ProtoMessage() {}
+
+func (x *TerminalSubscribe) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminalSubscribe.ProtoReflect.Descriptor instead.
+func (*TerminalSubscribe) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *TerminalSubscribe) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *TerminalSubscribe) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *TerminalSubscribe) GetWindow() uint32 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
+}
+
+type TerminalUnsubscribe struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alias         string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TerminalUnsubscribe) Reset() {
+	*x = TerminalUnsubscribe{}
+	mi := &file_terminal_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TerminalUnsubscribe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerminalUnsubscribe) ProtoMessage() {}
+
+func (x *TerminalUnsubscribe) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminalUnsubscribe.ProtoReflect.Descriptor instead.
+func (*TerminalUnsubscribe) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *TerminalUnsubscribe) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+type TerminalStreamWrite struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Alias string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	Stdin []byte                 `protobuf:"bytes,2,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	// token is only required when writing to a terminal the stream is not subscribed to
+	Token         string `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TerminalStreamWrite) Reset() {
+	*x = TerminalStreamWrite{}
+	mi := &file_terminal_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TerminalStreamWrite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerminalStreamWrite) ProtoMessage() {}
+
+func (x *TerminalStreamWrite) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminalStreamWrite.ProtoReflect.Descriptor instead.
+func (*TerminalStreamWrite) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *TerminalStreamWrite) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *TerminalStreamWrite) GetStdin() []byte {
+	if x != nil {
+		return x.Stdin
+	}
+	return nil
+}
+
+func (x *TerminalStreamWrite) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// TerminalStreamAck acknowledges output received for an alias and lets the
+// server send that many more bytes.
+type TerminalStreamAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alias         string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	Bytes         uint32                 `protobuf:"varint,2,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TerminalStreamAck) Reset() {
+	*x = TerminalStreamAck{}
+	mi := &file_terminal_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TerminalStreamAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerminalStreamAck) ProtoMessage() {}
+
+func (x *TerminalStreamAck) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminalStreamAck.ProtoReflect.Descriptor instead.
+func (*TerminalStreamAck) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *TerminalStreamAck) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *TerminalStreamAck) GetBytes() uint32 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+type MultiplexTerminalResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Alias string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	// Types that are valid to be assigned to Output:
+	//
+	//	*MultiplexTerminalResponse_Data
+	//	*MultiplexTerminalResponse_ExitCode
+	//	*MultiplexTerminalResponse_Title
+	//	*MultiplexTerminalResponse_Error
+	//	*MultiplexTerminalResponse_Clipboard
+	Output isMultiplexTerminalResponse_Output `protobuf_oneof:"output"`
+	// only present if output is title
+	TitleSource   TerminalTitleSource `protobuf:"varint,6,opt,name=title_source,json=titleSource,proto3,enum=supervisor.TerminalTitleSource" json:"title_source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiplexTerminalResponse) Reset() {
+	*x = MultiplexTerminalResponse{}
+	mi := &file_terminal_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiplexTerminalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiplexTerminalResponse) ProtoMessage() {}
+
+func (x *MultiplexTerminalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiplexTerminalResponse.ProtoReflect.Descriptor instead.
+func (*MultiplexTerminalResponse) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *MultiplexTerminalResponse) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *MultiplexTerminalResponse) GetOutput() isMultiplexTerminalResponse_Output {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+func (x *MultiplexTerminalResponse) GetData() []byte {
+	if x != nil {
+		if x, ok := x.Output.(*MultiplexTerminalResponse_Data); ok {
+			return x.Data
+		}
+	}
+	return nil
+}
+
+func (x *MultiplexTerminalResponse) GetExitCode() int32 {
+	if x != nil {
+		if x, ok := x.Output.(*MultiplexTerminalResponse_ExitCode); ok {
+			return x.ExitCode
+		}
+	}
+	return 0
+}
+
+func (x *MultiplexTerminalResponse) GetTitle() string {
+	if x != nil {
+		if x, ok := x.Output.(*MultiplexTerminalResponse_Title); ok {
+			return x.Title
+		}
+	}
+	return ""
+}
+
+func (x *MultiplexTerminalResponse) GetError() *TerminalStreamError {
+	if x != nil {
+		if x, ok := x.Output.(*MultiplexTerminalResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *MultiplexTerminalResponse) GetClipboard() *TerminalClipboard {
+	if x != nil {
+		if x, ok := x.Output.(*MultiplexTerminalResponse_Clipboard); ok {
+			return x.Clipboard
+		}
+	}
+	return nil
+}
+
+func (x *MultiplexTerminalResponse) GetTitleSource() TerminalTitleSource {
+	if x != nil {
+		return x.TitleSource
+	}
+	return TerminalTitleSource_process
+}
+
+type isMultiplexTerminalResponse_Output interface {
+	isMultiplexTerminalResponse_Output()
+}
+
+type MultiplexTerminalResponse_Data struct {
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"`
+}
+
+type MultiplexTerminalResponse_ExitCode struct {
+	ExitCode int32 `protobuf:"varint,3,opt,name=exit_code,json=exitCode,proto3,oneof"`
+}
+
+type MultiplexTerminalResponse_Title struct {
+	Title string `protobuf:"bytes,4,opt,name=title,proto3,oneof"`
+}
+
+type MultiplexTerminalResponse_Error struct {
+	Error *TerminalStreamError `protobuf:"bytes,5,opt,name=error,proto3,oneof"`
+}
+
+type MultiplexTerminalResponse_Clipboard struct {
+	Clipboard *TerminalClipboard `protobuf:"bytes,7,opt,name=clipboard,proto3,oneof"`
+}
+
+func (*MultiplexTerminalResponse_Data) isMultiplexTerminalResponse_Output() {}
+
+func (*MultiplexTerminalResponse_ExitCode) isMultiplexTerminalResponse_Output() {}
+
+func (*MultiplexTerminalResponse_Title) isMultiplexTerminalResponse_Output() {}
+
+func (*MultiplexTerminalResponse_Error) isMultiplexTerminalResponse_Output() {}
+
+func (*MultiplexTerminalResponse_Clipboard) isMultiplexTerminalResponse_Output() {}
+
+// TerminalStreamError reports a failed request or a dropped subscription for a
+// single alias, e.g. an unknown alias or a revoked token. The stream stays open
+// for the other aliases.
+type TerminalStreamError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// code is a gRPC status code
+	Code          uint32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TerminalStreamError) Reset() {
+	*x = TerminalStreamError{}
+	mi := &file_terminal_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TerminalStreamError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerminalStreamError) ProtoMessage() {}
+
+func (x *TerminalStreamError) ProtoReflect() protoreflect.Message {
+	mi := &file_terminal_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminalStreamError.ProtoReflect.Descriptor instead.
+func (*TerminalStreamError) Descriptor() ([]byte, []int) {
+	return file_terminal_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *TerminalStreamError) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *TerminalStreamError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_terminal_proto protoreflect.FileDescriptor
+
+const file_terminal_proto_rawDesc = "" +
+	"\n" +
+	"\x0eterminal.proto\x12\n" +
+	"supervisor\"l\n" +
+	"\fTerminalSize\x12\x12\n" +
+	"\x04rows\x18\x01 \x01(\rR\x04rows\x12\x12\n" +
+	"\x04cols\x18\x02 \x01(\rR\x04cols\x12\x18\n" +
+	"\awidthPx\x18\x03 \x01(\rR\awidthPx\x12\x1a\n" +
+	"\bheightPx\x18\x04 \x01(\rR\bheightPx\"\x83\x04\n" +
+	"\x13OpenTerminalRequest\x12\x18\n" +
+	"\aworkdir\x18\x01 \x01(\tR\aworkdir\x12:\n" +
+	"\x03env\x18\x02 \x03(\v2(.supervisor.OpenTerminalRequest.EnvEntryR\x03env\x12R\n" +
+	"\vannotations\x18\x03 \x03(\v20.supervisor.OpenTerminalRequest.AnnotationsEntryR\vannotations\x12\x14\n" +
+	"\x05shell\x18\x04 \x01(\tR\x05shell\x12\x1d\n" +
+	"\n" +
+	"shell_args\x18\x05 \x03(\tR\tshellArgs\x12,\n" +
+	"\x04size\x18\x06 \x01(\v2\x18.supervisor.TerminalSizeR\x04size\x12\x18\n" +
+	"\aprofile\x18\a \x01(\tR\aprofile\x12\x16\n" +
+	"\x06record\x18\b \x01(\bR\x06record\x12\x12\n" +
+	"\x04name\x18\t \x01(\tR\x04name\x12!\n" +
+	"\fidle_timeout\x18\n" +
+	" \x01(\rR\vidleTimeout\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a>\n" +
+	"\x10AnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"m\n" +
+	"\x14OpenTerminalResponse\x120\n" +
+	"\bterminal\x18\x01 \x01(\v2\x14.supervisor.TerminalR\bterminal\x12#\n" +
+	"\rstarter_token\x18\x02 \x01(\tR\fstarterToken\"T\n" +
+	"\x17ShutdownTerminalRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12#\n" +
+	"\rforce_success\x18\x02 \x01(\bR\fforceSuccess\"\x1a\n" +
+	"\x18ShutdownTerminalResponse\"\x8c\x04\n" +
+	"\bTerminal\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x18\n" +
+	"\acommand\x18\x02 \x03(\tR\acommand\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x10\n" +
+	"\x03pid\x18\x04 \x01(\x03R\x03pid\x12'\n" +
+	"\x0finitial_workdir\x18\x05 \x01(\tR\x0einitialWorkdir\x12'\n" +
+	"\x0fcurrent_workdir\x18\x06 \x01(\tR\x0ecurrentWorkdir\x12G\n" +
+	"\vannotations\x18\a \x03(\v2%.supervisor.Terminal.AnnotationsEntryR\vannotations\x12B\n" +
+	"\ftitle_source\x18\b \x01(\x0e2\x1f.supervisor.TerminalTitleSourceR\vtitleSource\x12\x1c\n" +
+	"\trecording\x18\t \x01(\tR\trecording\x124\n" +
+	"\aviewers\x18\n" +
+	" \x03(\v2\x1a.supervisor.TerminalViewerR\aviewers\x12\x12\n" +
+	"\x04name\x18\v \x01(\tR\x04name\x12!\n" +
+	"\fidle_timeout\x18\f \x01(\rR\vidleTimeout\x1a>\n" +
+	"\x10AnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x90\x01\n" +
+	"\x0eTerminalViewer\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x125\n" +
+	"\x05scope\x18\x02 \x01(\x0e2\x1f.supervisor.TerminalAccessScopeR\x05scope\x12\x14\n" +
+	"\x05since\x18\x03 \x01(\x03R\x05since\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\texpiresAt\"*\n" +
+	"\x12GetTerminalRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\"\x16\n" +
+	"\x14ListTerminalsRequest\"\x7f\n" +
+	"\x15ListTerminalsResponse\x122\n" +
+	"\tterminals\x18\x01 \x03(\v2\x14.supervisor.TerminalR\tterminals\x122\n" +
+	"\x06limits\x18\x02 \x01(\v2\x1a.supervisor.TerminalLimitsR\x06limits\"\x8a\x01\n" +
+	"\x0eTerminalLimits\x12#\n" +
+	"\rmax_terminals\x18\x01 \x01(\rR\fmaxTerminals\x12%\n" +
+	"\x0eannotation_key\x18\x02 \x01(\tR\rannotationKey\x12,\n" +
+	"\x12max_per_annotation\x18\x03 \x01(\rR\x10maxPerAnnotation\"[\n" +
+	"\x15ListenTerminalRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\"\xf2\x01\n" +
+	"\x16ListenTerminalResponse\x12\x14\n" +
+	"\x04data\x18\x01 \x01(\fH\x00R\x04data\x12\x1d\n" +
+	"\texit_code\x18\x02 \x01(\x05H\x00R\bexitCode\x12\x16\n" +
+	"\x05title\x18\x03 \x01(\tH\x00R\x05title\x12=\n" +
+	"\tclipboard\x18\x05 \x01(\v2\x1d.supervisor.TerminalClipboardH\x00R\tclipboard\x12B\n" +
+	"\ftitle_source\x18\x04 \x01(\x0e2\x1f.supervisor.TerminalTitleSourceR\vtitleSourceB\b\n" +
+	"\x06output\"K\n" +
+	"\x11TerminalClipboard\x12\x1c\n" +
+	"\tselection\x18\x01 \x01(\tR\tselection\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\fR\acontent\"X\n" +
+	"\x14WriteTerminalRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x14\n" +
+	"\x05stdin\x18\x02 \x01(\fR\x05stdin\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\"<\n" +
+	"\x15WriteTerminalResponse\x12#\n" +
+	"\rbytes_written\x18\x01 \x01(\rR\fbytesWritten\"\x98\x01\n" +
+	"\x16SetTerminalSizeRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x16\n" +
+	"\x05token\x18\x02 \x01(\tH\x00R\x05token\x12\x16\n" +
+	"\x05force\x18\x03 \x01(\bH\x00R\x05force\x12,\n" +
+	"\x04size\x18\x04 \x01(\v2\x18.supervisor.TerminalSizeR\x04sizeB\n" +
+	"\n" +
+	"\bpriority\"\x19\n" +
+	"\x17SetTerminalSizeResponse\"E\n" +
+	"\x17SetTerminalTitleRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\"\x1a\n" +
+	"\x18SetTerminalTitleResponse\"\xe3\x01\n" +
+	" UpdateTerminalAnnotationsRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12S\n" +
+	"\achanged\x18\x02 \x03(\v29.supervisor.UpdateTerminalAnnotationsRequest.ChangedEntryR\achanged\x12\x18\n" +
+	"\adeleted\x18\x03 \x03(\tR\adeleted\x1a:\n" +
+	"\fChangedEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"#\n" +
+	"!UpdateTerminalAnnotationsResponse\"M\n" +
+	"\x1bSetTerminalRecordingRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x18\n" +
+	"\aenabled\x18\x02 \x01(\bR\aenabled\"2\n" +
+	"\x1cSetTerminalRecordingResponse\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"g\n" +
+	"\x15ExportTerminalRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x128\n" +
+	"\x06format\x18\x02 \x01(\x0e2 .supervisor.TerminalExportFormatR\x06format\"2\n" +
+	"\x16ExportTerminalResponse\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\fR\acontent\"\x98\x01\n" +
+	"\x14ShareTerminalRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x125\n" +
+	"\x05scope\x18\x02 \x01(\x0e2\x1f.supervisor.TerminalAccessScopeR\x05scope\x12\x1f\n" +
+	"\vttl_seconds\x18\x03 \x01(\x03R\n" +
+	"ttlSeconds\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\"\x83\x01\n" +
+	"\x15ShareTerminalResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x125\n" +
+	"\x05scope\x18\x02 \x01(\x0e2\x1f.supervisor.TerminalAccessScopeR\x05scope\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\x03R\texpiresAt\"H\n" +
+	"\x1aRevokeTerminalShareRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"\x1d\n" +
+	"\x1bRevokeTerminalShareResponse\"l\n" +
+	"\x1aReadTerminalBacklogRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x14\n" +
+	"\x05token\x18\x04 \x01(\tR\x05token\"\x81\x01\n" +
+	"\x1bReadTerminalBacklogResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x14\n" +
+	"\x05first\x18\x04 \x01(\x03R\x05first\x12\x14\n" +
+	"\x05total\x18\x05 \x01(\x03R\x05total\"\xd7\x01\n" +
+	"\x18BroadcastTerminalRequest\x12\x18\n" +
+	"\aaliases\x18\x01 \x03(\tR\aaliases\x12N\n" +
+	"\bselector\x18\x02 \x03(\v22.supervisor.BroadcastTerminalRequest.SelectorEntryR\bselector\x12\x14\n" +
+	"\x05stdin\x18\x03 \x01(\fR\x05stdin\x1a;\n" +
+	"\rSelectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"Z\n" +
+	"\x19BroadcastTerminalResponse\x12=\n" +
+	"\aresults\x18\x01 \x03(\v2#.supervisor.TerminalBroadcastResultR\aresults\"~\n" +
+	"\x17TerminalBroadcastResult\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12#\n" +
+	"\rbytes_written\x18\x02 \x01(\rR\fbytesWritten\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\rR\x04code\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\xa4\x01\n" +
+	"\x16SearchTerminalsRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x18\n" +
+	"\apattern\x18\x02 \x01(\tR\apattern\x12\x18\n" +
+	"\acontext\x18\x03 \x01(\rR\acontext\x12\x1f\n" +
+	"\vignore_case\x18\x04 \x01(\bR\n" +
+	"ignoreCase\x12\x1f\n" +
+	"\vmax_matches\x18\x05 \x01(\rR\n" +
+	"maxMatches\"r\n" +
+	"\x17SearchTerminalsResponse\x129\n" +
+	"\amatches\x18\x01 \x03(\v2\x1f.supervisor.TerminalSearchMatchR\amatches\x12\x1c\n" +
+	"\ttruncated\x18\x02 \x01(\bR\ttruncated\"\xbe\x01\n" +
+	"\x13TerminalSearchMatch\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04line\x18\x03 \x01(\tR\x04line\x12\x16\n" +
+	"\x06before\x18\x04 \x03(\tR\x06before\x12\x14\n" +
+	"\x05after\x18\x05 \x03(\tR\x05after\x12\x16\n" +
+	"\x06offset\x18\x06 \x01(\x03R\x06offset\x12!\n" +
+	"\fmatch_offset\x18\a \x01(\x03R\vmatchOffset\"\x95\x02\n" +
+	"\x18MultiplexTerminalRequest\x12=\n" +
+	"\tsubscribe\x18\x01 \x01(\v2\x1d.supervisor.TerminalSubscribeH\x00R\tsubscribe\x12C\n" +
+	"\vunsubscribe\x18\x02 \x01(\v2\x1f.supervisor.TerminalUnsubscribeH\x00R\vunsubscribe\x127\n" +
+	"\x05write\x18\x03 \x01(\v2\x1f.supervisor.TerminalStreamWriteH\x00R\x05write\x121\n" +
+	"\x03ack\x18\x04 \x01(\v2\x1d.supervisor.TerminalStreamAckH\x00R\x03ackB\t\n" +
+	"\arequest\"W\n" +
+	"\x11TerminalSubscribe\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12\x16\n" +
+	"\x06window\x18\x03 \x01(\rR\x06window\"+\n" +
+	"\x13TerminalUnsubscribe\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\"W\n" +
+	"\x13TerminalStreamWrite\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x14\n" +
+	"\x05stdin\x18\x02 \x01(\fR\x05stdin\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\"?\n" +
+	"\x11TerminalStreamAck\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x14\n" +
+	"\x05bytes\x18\x02 \x01(\rR\x05bytes\"\xc4\x02\n" +
+	"\x19MultiplexTerminalResponse\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x14\n" +
+	"\x04data\x18\x02 \x01(\fH\x00R\x04data\x12\x1d\n" +
+	"\texit_code\x18\x03 \x01(\x05H\x00R\bexitCode\x12\x16\n" +
+	"\x05title\x18\x04 \x01(\tH\x00R\x05title\x127\n" +
+	"\x05error\x18\x05 \x01(\v2\x1f.supervisor.TerminalStreamErrorH\x00R\x05error\x12=\n" +
+	"\tclipboard\x18\a \x01(\v2\x1d.supervisor.TerminalClipboardH\x00R\tclipboard\x12B\n" +
+	"\ftitle_source\x18\x06 \x01(\x0e2\x1f.supervisor.TerminalTitleSourceR\vtitleSourceB\b\n" +
+	"\x06output\"C\n" +
+	"\x13TerminalStreamError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\rR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage*+\n" +
+	"\x13TerminalTitleSource\x12\v\n" +
+	"\aprocess\x10\x00\x12\a\n" +
+	"\x03api\x10\x01*4\n" +
+	"\x14TerminalExportFormat\x12\b\n" +
+	"\x04text\x10\x00\x12\b\n" +
+	"\x04html\x10\x01\x12\b\n" +
+	"\x04cast\x10\x02*4\n" +
+	"\x13TerminalAccessScope\x12\r\n" +
+	"\tread_only\x10\x00\x12\x0e\n" +
+	"\n" +
+	"read_write\x10\x012\xfa\v\n" +
+	"\x0fTerminalService\x12K\n" +
+	"\x04Open\x12\x1f.supervisor.OpenTerminalRequest\x1a .supervisor.OpenTerminalResponse\"\x00\x12W\n" +
+	"\bShutdown\x12#.supervisor.ShutdownTerminalRequest\x1a$.supervisor.ShutdownTerminalResponse\"\x00\x12=\n" +
+	"\x03Get\x12\x1e.supervisor.GetTerminalRequest\x1a\x14.supervisor.Terminal\"\x00\x12M\n" +
+	"\x04List\x12 .supervisor.ListTerminalsRequest\x1a!.supervisor.ListTerminalsResponse\"\x00\x12S\n" +
+	"\x06Listen\x12!.supervisor.ListenTerminalRequest\x1a\".supervisor.ListenTerminalResponse\"\x000\x01\x12N\n" +
+	"\x05Write\x12 .supervisor.WriteTerminalRequest\x1a!.supervisor.WriteTerminalResponse\"\x00\x12T\n" +
+	"\aSetSize\x12\".supervisor.SetTerminalSizeRequest\x1a#.supervisor.SetTerminalSizeResponse\"\x00\x12W\n" +
+	"\bSetTitle\x12#.supervisor.SetTerminalTitleRequest\x1a$.supervisor.SetTerminalTitleResponse\"\x00\x12r\n" +
+	"\x11UpdateAnnotations\x12,.supervisor.UpdateTerminalAnnotationsRequest\x1a-.supervisor.UpdateTerminalAnnotationsResponse\"\x00\x12c\n" +
+	"\fSetRecording\x12'.supervisor.SetTerminalRecordingRequest\x1a(.supervisor.SetTerminalRecordingResponse\"\x00\x12Q\n" +
+	"\x06Export\x12!.supervisor.ExportTerminalRequest\x1a\".supervisor.ExportTerminalResponse\"\x00\x12V\n" +
+	"\rShareTerminal\x12 .supervisor.ShareTerminalRequest\x1a!.supervisor.ShareTerminalResponse\"\x00\x12h\n" +
+	"\x13RevokeTerminalShare\x12&.supervisor.RevokeTerminalShareRequest\x1a'.supervisor.RevokeTerminalShareResponse\"\x00\x12`\n" +
+	"\vReadBacklog\x12&.supervisor.ReadTerminalBacklogRequest\x1a'.supervisor.ReadTerminalBacklogResponse\"\x00\x12Z\n" +
+	"\tBroadcast\x12$.supervisor.BroadcastTerminalRequest\x1a%.supervisor.BroadcastTerminalResponse\"\x00\x12S\n" +
+	"\x06Search\x12\".supervisor.SearchTerminalsRequest\x1a#.supervisor.SearchTerminalsResponse\"\x00\x12^\n" +
+	"\tMultiplex\x12$.supervisor.MultiplexTerminalRequest\x1a%.supervisor.MultiplexTerminalResponse\"\x00(\x010\x01B\x10Z\x0esupervisor/apib\x06proto3"
+
+var (
+	file_terminal_proto_rawDescOnce sync.Once
+	file_terminal_proto_rawDescData []byte
+)
+
+func file_terminal_proto_rawDescGZIP() []byte {
+	file_terminal_proto_rawDescOnce.Do(func() {
+		file_terminal_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_terminal_proto_rawDesc), len(file_terminal_proto_rawDesc)))
+	})
+	return file_terminal_proto_rawDescData
+}
+
+var file_terminal_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_terminal_proto_msgTypes = make([]protoimpl.MessageInfo, 50)
+var file_terminal_proto_goTypes = []any{
+	(TerminalTitleSource)(0),                  // 0: supervisor.TerminalTitleSource
+	(TerminalExportFormat)(0),                 // 1: supervisor.TerminalExportFormat
+	(TerminalAccessScope)(0),                  // 2: supervisor.TerminalAccessScope
+	(*TerminalSize)(nil),                      // 3: supervisor.TerminalSize
+	(*OpenTerminalRequest)(nil),               // 4: supervisor.OpenTerminalRequest
+	(*OpenTerminalResponse)(nil),              // 5: supervisor.OpenTerminalResponse
+	(*ShutdownTerminalRequest)(nil),           // 6: supervisor.ShutdownTerminalRequest
+	(*ShutdownTerminalResponse)(nil),          // 7: supervisor.ShutdownTerminalResponse
+	(*Terminal)(nil),                          // 8: supervisor.Terminal
+	(*TerminalViewer)(nil),                    // 9: supervisor.TerminalViewer
+	(*GetTerminalRequest)(nil),                // 10: supervisor.GetTerminalRequest
+	(*ListTerminalsRequest)(nil),              // 11: supervisor.ListTerminalsRequest
+	(*ListTerminalsResponse)(nil),             // 12: supervisor.ListTerminalsResponse
+	(*TerminalLimits)(nil),                    // 13: supervisor.TerminalLimits
+	(*ListenTerminalRequest)(nil),             // 14: supervisor.ListenTerminalRequest
+	(*ListenTerminalResponse)(nil),            // 15: supervisor.ListenTerminalResponse
+	(*TerminalClipboard)(nil),                 // 16: supervisor.TerminalClipboard
+	(*WriteTerminalRequest)(nil),              // 17: supervisor.WriteTerminalRequest
+	(*WriteTerminalResponse)(nil),             // 18: supervisor.WriteTerminalResponse
+	(*SetTerminalSizeRequest)(nil),            // 19: supervisor.SetTerminalSizeRequest
+	(*SetTerminalSizeResponse)(nil),           // 20: supervisor.SetTerminalSizeResponse
+	(*SetTerminalTitleRequest)(nil),           // 21: supervisor.SetTerminalTitleRequest
+	(*SetTerminalTitleResponse)(nil),          // 22: supervisor.SetTerminalTitleResponse
+	(*UpdateTerminalAnnotationsRequest)(nil),  // 23: supervisor.UpdateTerminalAnnotationsRequest
+	(*UpdateTerminalAnnotationsResponse)(nil), // 24: supervisor.UpdateTerminalAnnotationsResponse
+	(*SetTerminalRecordingRequest)(nil),       // 25: supervisor.SetTerminalRecordingRequest
+	(*SetTerminalRecordingResponse)(nil),      // 26: supervisor.SetTerminalRecordingResponse
+	(*ExportTerminalRequest)(nil),             // 27: supervisor.ExportTerminalRequest
+	(*ExportTerminalResponse)(nil),            // 28: supervisor.ExportTerminalResponse
+	(*ShareTerminalRequest)(nil),              // 29: supervisor.ShareTerminalRequest
+	(*ShareTerminalResponse)(nil),             // 30: supervisor.ShareTerminalResponse
+	(*RevokeTerminalShareRequest)(nil),        // 31: supervisor.RevokeTerminalShareRequest
+	(*RevokeTerminalShareResponse)(nil),       // 32: supervisor.RevokeTerminalShareResponse
+	(*ReadTerminalBacklogRequest)(nil),        // 33: supervisor.ReadTerminalBacklogRequest
+	(*ReadTerminalBacklogResponse)(nil),       // 34: supervisor.ReadTerminalBacklogResponse
+	(*BroadcastTerminalRequest)(nil),          // 35: supervisor.BroadcastTerminalRequest
+	(*BroadcastTerminalResponse)(nil),         // 36: supervisor.BroadcastTerminalResponse
+	(*TerminalBroadcastResult)(nil),           // 37: supervisor.TerminalBroadcastResult
+	(*SearchTerminalsRequest)(nil),            // 38: supervisor.SearchTerminalsRequest
+	(*SearchTerminalsResponse)(nil),           // 39: supervisor.SearchTerminalsResponse
+	(*TerminalSearchMatch)(nil),               // 40: supervisor.TerminalSearchMatch
+	(*MultiplexTerminalRequest)(nil),          // 41: supervisor.MultiplexTerminalRequest
+	(*TerminalSubscribe)(nil),                 // 42: supervisor.TerminalSubscribe
+	(*TerminalUnsubscribe)(nil),               // 43: supervisor.TerminalUnsubscribe
+	(*TerminalStreamWrite)(nil),               // 44: supervisor.TerminalStreamWrite
+	(*TerminalStreamAck)(nil),                 // 45: supervisor.TerminalStreamAck
+	(*MultiplexTerminalResponse)(nil),         // 46: supervisor.MultiplexTerminalResponse
+	(*TerminalStreamError)(nil),               // 47: supervisor.TerminalStreamError
+	nil,                                       // 48: supervisor.OpenTerminalRequest.EnvEntry
+	nil,                                       // 49: supervisor.OpenTerminalRequest.AnnotationsEntry
+	nil,                                       // 50: supervisor.Terminal.AnnotationsEntry
+	nil,                                       // 51: supervisor.UpdateTerminalAnnotationsRequest.ChangedEntry
+	nil,                                       // 52: supervisor.BroadcastTerminalRequest.SelectorEntry
+}
+var file_terminal_proto_depIdxs = []int32{
+	48, // 0: supervisor.OpenTerminalRequest.env:type_name -> supervisor.OpenTerminalRequest.EnvEntry
+	49, // 1: supervisor.OpenTerminalRequest.annotations:type_name -> supervisor.OpenTerminalRequest.AnnotationsEntry
+	3,  // 2: supervisor.OpenTerminalRequest.size:type_name -> supervisor.TerminalSize
+	8,  // 3: supervisor.OpenTerminalResponse.terminal:type_name -> supervisor.Terminal
+	50, // 4: supervisor.Terminal.annotations:type_name -> supervisor.Terminal.AnnotationsEntry
+	0,  // 5: supervisor.Terminal.title_source:type_name -> supervisor.TerminalTitleSource
+	9,  // 6: supervisor.Terminal.viewers:type_name -> supervisor.TerminalViewer
+	2,  // 7: supervisor.TerminalViewer.scope:type_name -> supervisor.TerminalAccessScope
+	8,  // 8: supervisor.ListTerminalsResponse.terminals:type_name -> supervisor.Terminal
+	13, // 9: supervisor.ListTerminalsResponse.limits:type_name -> supervisor.TerminalLimits
+	16, // 10: supervisor.ListenTerminalResponse.clipboard:type_name -> supervisor.TerminalClipboard
+	0,  // 11: supervisor.ListenTerminalResponse.title_source:type_name -> supervisor.TerminalTitleSource
+	3,  // 12: supervisor.SetTerminalSizeRequest.size:type_name -> supervisor.TerminalSize
+	51, // 13: supervisor.UpdateTerminalAnnotationsRequest.changed:type_name -> supervisor.UpdateTerminalAnnotationsRequest.ChangedEntry
+	1,  // 14: supervisor.ExportTerminalRequest.format:type_name -> supervisor.TerminalExportFormat
+	2,  // 15: supervisor.ShareTerminalRequest.scope:type_name -> supervisor.TerminalAccessScope
+	2,  // 16: supervisor.ShareTerminalResponse.scope:type_name -> supervisor.TerminalAccessScope
+	52, // 17: supervisor.BroadcastTerminalRequest.selector:type_name -> supervisor.BroadcastTerminalRequest.SelectorEntry
+	37, // 18: supervisor.BroadcastTerminalResponse.results:type_name -> supervisor.TerminalBroadcastResult
+	40, // 19: supervisor.SearchTerminalsResponse.matches:type_name -> supervisor.TerminalSearchMatch
+	42, // 20: supervisor.MultiplexTerminalRequest.subscribe:type_name -> supervisor.TerminalSubscribe
+	43, // 21: supervisor.MultiplexTerminalRequest.unsubscribe:type_name -> supervisor.TerminalUnsubscribe
+	44, // 22: supervisor.MultiplexTerminalRequest.write:type_name -> supervisor.TerminalStreamWrite
+	45, // 23: supervisor.MultiplexTerminalRequest.ack:type_name -> supervisor.TerminalStreamAck
+	47, // 24: supervisor.MultiplexTerminalResponse.error:type_name -> supervisor.TerminalStreamError
+	16, // 25: supervisor.MultiplexTerminalResponse.clipboard:type_name -> supervisor.TerminalClipboard
+	0,  // 26: supervisor.MultiplexTerminalResponse.title_source:type_name -> supervisor.TerminalTitleSource
+	4,  // 27: supervisor.TerminalService.Open:input_type -> supervisor.OpenTerminalRequest
+	6,  // 28: supervisor.TerminalService.Shutdown:input_type -> supervisor.ShutdownTerminalRequest
+	10, // 29: supervisor.TerminalService.Get:input_type -> supervisor.GetTerminalRequest
+	11, // 30: supervisor.TerminalService.List:input_type -> supervisor.ListTerminalsRequest
+	14, // 31: supervisor.TerminalService.Listen:input_type -> supervisor.ListenTerminalRequest
+	17, // 32: supervisor.TerminalService.Write:input_type -> supervisor.WriteTerminalRequest
+	19, // 33: supervisor.TerminalService.SetSize:input_type -> supervisor.SetTerminalSizeRequest
+	21, // 34: supervisor.TerminalService.SetTitle:input_type -> supervisor.SetTerminalTitleRequest
+	23, // 35: supervisor.TerminalService.UpdateAnnotations:input_type -> supervisor.UpdateTerminalAnnotationsRequest
+	25, // 36: supervisor.TerminalService.SetRecording:input_type -> supervisor.SetTerminalRecordingRequest
+	27, // 37: supervisor.TerminalService.Export:input_type -> supervisor.ExportTerminalRequest
+	29, // 38: supervisor.TerminalService.ShareTerminal:input_type -> supervisor.ShareTerminalRequest
+	31, // 39: supervisor.TerminalService.RevokeTerminalShare:input_type -> supervisor.RevokeTerminalShareRequest
+	33, // 40: supervisor.TerminalService.ReadBacklog:input_type -> supervisor.ReadTerminalBacklogRequest
+	35, // 41: supervisor.TerminalService.Broadcast:input_type -> supervisor.BroadcastTerminalRequest
+	38, // 42: supervisor.TerminalService.Search:input_type -> supervisor.SearchTerminalsRequest
+	41, // 43: supervisor.TerminalService.Multiplex:input_type -> supervisor.MultiplexTerminalRequest
+	5,  // 44: supervisor.TerminalService.Open:output_type -> supervisor.OpenTerminalResponse
+	7,  // 45: supervisor.TerminalService.Shutdown:output_type -> supervisor.ShutdownTerminalResponse
+	8,  // 46: supervisor.TerminalService.Get:output_type -> supervisor.Terminal
+	12, // 47: supervisor.TerminalService.List:output_type -> supervisor.ListTerminalsResponse
+	15, // 48: supervisor.TerminalService.Listen:output_type -> supervisor.ListenTerminalResponse
+	18, // 49: supervisor.TerminalService.Write:output_type -> supervisor.WriteTerminalResponse
+	20, // 50: supervisor.TerminalService.SetSize:output_type -> supervisor.SetTerminalSizeResponse
+	22, // 51: supervisor.TerminalService.SetTitle:output_type -> supervisor.SetTerminalTitleResponse
+	24, // 52: supervisor.TerminalService.UpdateAnnotations:output_type -> supervisor.UpdateTerminalAnnotationsResponse
+	26, // 53: supervisor.TerminalService.SetRecording:output_type -> supervisor.SetTerminalRecordingResponse
+	28, // 54: supervisor.TerminalService.Export:output_type -> supervisor.ExportTerminalResponse
+	30, // 55: supervisor.TerminalService.ShareTerminal:output_type -> supervisor.ShareTerminalResponse
+	32, // 56: supervisor.TerminalService.RevokeTerminalShare:output_type -> supervisor.RevokeTerminalShareResponse
+	34, // 57: supervisor.TerminalService.ReadBacklog:output_type -> supervisor.ReadTerminalBacklogResponse
+	36, // 58: supervisor.TerminalService.Broadcast:output_type -> supervisor.BroadcastTerminalResponse
+	39, // 59: supervisor.TerminalService.Search:output_type -> supervisor.SearchTerminalsResponse
+	46, // 60: supervisor.TerminalService.Multiplex:output_type -> supervisor.MultiplexTerminalResponse
+	44, // [44:61] is the sub-list for method output_type
+	27, // [27:44] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
+}
+
+func init() { file_terminal_proto_init() }
+func file_terminal_proto_init() {
+	if File_terminal_proto != nil {
+		return
+	}
+	file_terminal_proto_msgTypes[12].OneofWrappers = []any{
+		(*ListenTerminalResponse_Data)(nil),
+		(*ListenTerminalResponse_ExitCode)(nil),
+		(*ListenTerminalResponse_Title)(nil),
+		(*ListenTerminalResponse_Clipboard)(nil),
+	}
+	file_terminal_proto_msgTypes[16].OneofWrappers = []any{
+		(*SetTerminalSizeRequest_Token)(nil),
+		(*SetTerminalSizeRequest_Force)(nil),
+	}
+	file_terminal_proto_msgTypes[38].OneofWrappers = []any{
+		(*MultiplexTerminalRequest_Subscribe)(nil),
+		(*MultiplexTerminalRequest_Unsubscribe)(nil),
+		(*MultiplexTerminalRequest_Write)(nil),
+		(*MultiplexTerminalRequest_Ack)(nil),
+	}
+	file_terminal_proto_msgTypes[43].OneofWrappers = []any{
+		(*MultiplexTerminalResponse_Data)(nil),
+		(*MultiplexTerminalResponse_ExitCode)(nil),
+		(*MultiplexTerminalResponse_Title)(nil),
+		(*MultiplexTerminalResponse_Error)(nil),
+		(*MultiplexTerminalResponse_Clipboard)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_terminal_proto_rawDesc), len(file_terminal_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   23,
+			NumEnums:      3,
+			NumMessages:   50,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
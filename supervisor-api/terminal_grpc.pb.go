@@ -19,20 +19,33 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TerminalService_Open_FullMethodName              = "/supervisor.TerminalService/Open"
-	TerminalService_Shutdown_FullMethodName          = "/supervisor.TerminalService/Shutdown"
-	TerminalService_Get_FullMethodName               = "/supervisor.TerminalService/Get"
-	TerminalService_List_FullMethodName              = "/supervisor.TerminalService/List"
-	TerminalService_Listen_FullMethodName            = "/supervisor.TerminalService/Listen"
-	TerminalService_Write_FullMethodName             = "/supervisor.TerminalService/Write"
-	TerminalService_SetSize_FullMethodName           = "/supervisor.TerminalService/SetSize"
-	TerminalService_SetTitle_FullMethodName          = "/supervisor.TerminalService/SetTitle"
-	TerminalService_UpdateAnnotations_FullMethodName = "/supervisor.TerminalService/UpdateAnnotations"
+	TerminalService_Open_FullMethodName                = "/supervisor.TerminalService/Open"
+	TerminalService_Shutdown_FullMethodName            = "/supervisor.TerminalService/Shutdown"
+	TerminalService_Get_FullMethodName                 = "/supervisor.TerminalService/Get"
+	TerminalService_List_FullMethodName                = "/supervisor.TerminalService/List"
+	TerminalService_Listen_FullMethodName              = "/supervisor.TerminalService/Listen"
+	TerminalService_Write_FullMethodName               = "/supervisor.TerminalService/Write"
+	TerminalService_SetSize_FullMethodName             = "/supervisor.TerminalService/SetSize"
+	TerminalService_SetTitle_FullMethodName            = "/supervisor.TerminalService/SetTitle"
+	TerminalService_UpdateAnnotations_FullMethodName   = "/supervisor.TerminalService/UpdateAnnotations"
+	TerminalService_SetRecording_FullMethodName        = "/supervisor.TerminalService/SetRecording"
+	TerminalService_Export_FullMethodName              = "/supervisor.TerminalService/Export"
+	TerminalService_ShareTerminal_FullMethodName       = "/supervisor.TerminalService/ShareTerminal"
+	TerminalService_RevokeTerminalShare_FullMethodName = "/supervisor.TerminalService/RevokeTerminalShare"
+	TerminalService_ReadBacklog_FullMethodName         = "/supervisor.TerminalService/ReadBacklog"
+	TerminalService_Broadcast_FullMethodName           = "/supervisor.TerminalService/Broadcast"
+	TerminalService_Search_FullMethodName              = "/supervisor.TerminalService/Search"
+	TerminalService_Multiplex_FullMethodName           = "/supervisor.TerminalService/Multiplex"
 )
 
 // TerminalServiceClient is the client API for TerminalService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TerminalService requires every call to pass either the owner token, which supervisor writes to
+// a file only the workspace user can read, or a share token of the terminal as "supervisor-token"
+// metadata. Share tokens only grant access to Get, List, Listen, ReadBacklog, Export and Search,
+// and, with read_write scope, to Write, SetSize and Broadcast.
 type TerminalServiceClient interface {
 	// Open opens a new terminal running the login shell
 	Open(ctx context.Context, in *OpenTerminalRequest, opts ...grpc.CallOption) (*OpenTerminalResponse, error)
@@ -53,6 +66,27 @@ type TerminalServiceClient interface {
 	SetTitle(ctx context.Context, in *SetTerminalTitleRequest, opts ...grpc.CallOption) (*SetTerminalTitleResponse, error)
 	// UpdateAnnotations updates the terminal's annotations
 	UpdateAnnotations(ctx context.Context, in *UpdateTerminalAnnotationsRequest, opts ...grpc.CallOption) (*UpdateTerminalAnnotationsResponse, error)
+	// SetRecording starts or stops recording the terminal session into an asciicast v2 file
+	SetRecording(ctx context.Context, in *SetTerminalRecordingRequest, opts ...grpc.CallOption) (*SetTerminalRecordingResponse, error)
+	// Export returns the terminal's backlog in the requested format
+	Export(ctx context.Context, in *ExportTerminalRequest, opts ...grpc.CallOption) (*ExportTerminalResponse, error)
+	// ShareTerminal grants access to a terminal and returns a token that can be
+	// passed to Listen and Write.
+	ShareTerminal(ctx context.Context, in *ShareTerminalRequest, opts ...grpc.CallOption) (*ShareTerminalResponse, error)
+	// RevokeTerminalShare revokes an access token returned by ShareTerminal.
+	RevokeTerminalShare(ctx context.Context, in *RevokeTerminalShareRequest, opts ...grpc.CallOption) (*RevokeTerminalShareResponse, error)
+	// ReadBacklog returns a range of the terminal's output. Offsets are counted from
+	// the first byte the terminal has written. Output evicted from memory can be read
+	// if the backlog is spilled to disk.
+	ReadBacklog(ctx context.Context, in *ReadTerminalBacklogRequest, opts ...grpc.CallOption) (*ReadTerminalBacklogResponse, error)
+	// Broadcast writes the same input to several terminals, selected by alias or annotations.
+	Broadcast(ctx context.Context, in *BroadcastTerminalRequest, opts ...grpc.CallOption) (*BroadcastTerminalResponse, error)
+	// Search looks for lines matching a regular expression in the output of terminals.
+	Search(ctx context.Context, in *SearchTerminalsRequest, opts ...grpc.CallOption) (*SearchTerminalsResponse, error)
+	// Multiplex listens and writes to many terminals over a single stream.
+	// Clients subscribe to aliases and every response is tagged with the alias
+	// it belongs to. Output is flow controlled per alias, see TerminalStreamAck.
+	Multiplex(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MultiplexTerminalRequest, MultiplexTerminalResponse], error)
 }
 
 type terminalServiceClient struct {
@@ -162,9 +196,97 @@ func (c *terminalServiceClient) UpdateAnnotations(ctx context.Context, in *Updat
 	return out, nil
 }
 
+func (c *terminalServiceClient) SetRecording(ctx context.Context, in *SetTerminalRecordingRequest, opts ...grpc.CallOption) (*SetTerminalRecordingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetTerminalRecordingResponse)
+	err := c.cc.Invoke(ctx, TerminalService_SetRecording_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terminalServiceClient) Export(ctx context.Context, in *ExportTerminalRequest, opts ...grpc.CallOption) (*ExportTerminalResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportTerminalResponse)
+	err := c.cc.Invoke(ctx, TerminalService_Export_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terminalServiceClient) ShareTerminal(ctx context.Context, in *ShareTerminalRequest, opts ...grpc.CallOption) (*ShareTerminalResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShareTerminalResponse)
+	err := c.cc.Invoke(ctx, TerminalService_ShareTerminal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terminalServiceClient) RevokeTerminalShare(ctx context.Context, in *RevokeTerminalShareRequest, opts ...grpc.CallOption) (*RevokeTerminalShareResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeTerminalShareResponse)
+	err := c.cc.Invoke(ctx, TerminalService_RevokeTerminalShare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terminalServiceClient) ReadBacklog(ctx context.Context, in *ReadTerminalBacklogRequest, opts ...grpc.CallOption) (*ReadTerminalBacklogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReadTerminalBacklogResponse)
+	err := c.cc.Invoke(ctx, TerminalService_ReadBacklog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terminalServiceClient) Broadcast(ctx context.Context, in *BroadcastTerminalRequest, opts ...grpc.CallOption) (*BroadcastTerminalResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BroadcastTerminalResponse)
+	err := c.cc.Invoke(ctx, TerminalService_Broadcast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terminalServiceClient) Search(ctx context.Context, in *SearchTerminalsRequest, opts ...grpc.CallOption) (*SearchTerminalsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchTerminalsResponse)
+	err := c.cc.Invoke(ctx, TerminalService_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terminalServiceClient) Multiplex(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MultiplexTerminalRequest, MultiplexTerminalResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TerminalService_ServiceDesc.Streams[1], TerminalService_Multiplex_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[MultiplexTerminalRequest, MultiplexTerminalResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TerminalService_MultiplexClient = grpc.BidiStreamingClient[MultiplexTerminalRequest, MultiplexTerminalResponse]
+
 // TerminalServiceServer is the server API for TerminalService service.
 // All implementations must embed UnimplementedTerminalServiceServer
 // for forward compatibility.
+//
+// TerminalService requires every call to pass either the owner token, which supervisor writes to
+// a file only the workspace user can read, or a share token of the terminal as "supervisor-token"
+// metadata. Share tokens only grant access to Get, List, Listen, ReadBacklog, Export and Search,
+// and, with read_write scope, to Write, SetSize and Broadcast.
 type TerminalServiceServer interface {
 	// Open opens a new terminal running the login shell
 	Open(context.Context, *OpenTerminalRequest) (*OpenTerminalResponse, error)
@@ -185,6 +307,27 @@ type TerminalServiceServer interface {
 	SetTitle(context.Context, *SetTerminalTitleRequest) (*SetTerminalTitleResponse, error)
 	// UpdateAnnotations updates the terminal's annotations
 	UpdateAnnotations(context.Context, *UpdateTerminalAnnotationsRequest) (*UpdateTerminalAnnotationsResponse, error)
+	// SetRecording starts or stops recording the terminal session into an asciicast v2 file
+	SetRecording(context.Context, *SetTerminalRecordingRequest) (*SetTerminalRecordingResponse, error)
+	// Export returns the terminal's backlog in the requested format
+	Export(context.Context, *ExportTerminalRequest) (*ExportTerminalResponse, error)
+	// ShareTerminal grants access to a terminal and returns a token that can be
+	// passed to Listen and Write.
+	ShareTerminal(context.Context, *ShareTerminalRequest) (*ShareTerminalResponse, error)
+	// RevokeTerminalShare revokes an access token returned by ShareTerminal.
+	RevokeTerminalShare(context.Context, *RevokeTerminalShareRequest) (*RevokeTerminalShareResponse, error)
+	// ReadBacklog returns a range of the terminal's output. Offsets are counted from
+	// the first byte the terminal has written. Output evicted from memory can be read
+	// if the backlog is spilled to disk.
+	ReadBacklog(context.Context, *ReadTerminalBacklogRequest) (*ReadTerminalBacklogResponse, error)
+	// Broadcast writes the same input to several terminals, selected by alias or annotations.
+	Broadcast(context.Context, *BroadcastTerminalRequest) (*BroadcastTerminalResponse, error)
+	// Search looks for lines matching a regular expression in the output of terminals.
+	Search(context.Context, *SearchTerminalsRequest) (*SearchTerminalsResponse, error)
+	// Multiplex listens and writes to many terminals over a single stream.
+	// Clients subscribe to aliases and every response is tagged with the alias
+	// it belongs to. Output is flow controlled per alias, see TerminalStreamAck.
+	Multiplex(grpc.BidiStreamingServer[MultiplexTerminalRequest, MultiplexTerminalResponse]) error
 	mustEmbedUnimplementedTerminalServiceServer()
 }
 
@@ -222,6 +365,30 @@ func (UnimplementedTerminalServiceServer) SetTitle(context.Context, *SetTerminal
 func (UnimplementedTerminalServiceServer) UpdateAnnotations(context.Context, *UpdateTerminalAnnotationsRequest) (*UpdateTerminalAnnotationsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateAnnotations not implemented")
 }
+func (UnimplementedTerminalServiceServer) SetRecording(context.Context, *SetTerminalRecordingRequest) (*SetTerminalRecordingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRecording not implemented")
+}
+func (UnimplementedTerminalServiceServer) Export(context.Context, *ExportTerminalRequest) (*ExportTerminalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedTerminalServiceServer) ShareTerminal(context.Context, *ShareTerminalRequest) (*ShareTerminalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShareTerminal not implemented")
+}
+func (UnimplementedTerminalServiceServer) RevokeTerminalShare(context.Context, *RevokeTerminalShareRequest) (*RevokeTerminalShareResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeTerminalShare not implemented")
+}
+func (UnimplementedTerminalServiceServer) ReadBacklog(context.Context, *ReadTerminalBacklogRequest) (*ReadTerminalBacklogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadBacklog not implemented")
+}
+func (UnimplementedTerminalServiceServer) Broadcast(context.Context, *BroadcastTerminalRequest) (*BroadcastTerminalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Broadcast not implemented")
+}
+func (UnimplementedTerminalServiceServer) Search(context.Context, *SearchTerminalsRequest) (*SearchTerminalsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedTerminalServiceServer) Multiplex(grpc.BidiStreamingServer[MultiplexTerminalRequest, MultiplexTerminalResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Multiplex not implemented")
+}
 func (UnimplementedTerminalServiceServer) mustEmbedUnimplementedTerminalServiceServer() {}
 func (UnimplementedTerminalServiceServer) testEmbeddedByValue()                         {}
 
@@ -398,6 +565,139 @@ func _TerminalService_UpdateAnnotations_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TerminalService_SetRecording_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTerminalRecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminalServiceServer).SetRecording(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TerminalService_SetRecording_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminalServiceServer).SetRecording(ctx, req.(*SetTerminalRecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerminalService_Export_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportTerminalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminalServiceServer).Export(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TerminalService_Export_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminalServiceServer).Export(ctx, req.(*ExportTerminalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerminalService_ShareTerminal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareTerminalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminalServiceServer).ShareTerminal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TerminalService_ShareTerminal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminalServiceServer).ShareTerminal(ctx, req.(*ShareTerminalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerminalService_RevokeTerminalShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeTerminalShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminalServiceServer).RevokeTerminalShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TerminalService_RevokeTerminalShare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminalServiceServer).RevokeTerminalShare(ctx, req.(*RevokeTerminalShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerminalService_ReadBacklog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadTerminalBacklogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminalServiceServer).ReadBacklog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TerminalService_ReadBacklog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminalServiceServer).ReadBacklog(ctx, req.(*ReadTerminalBacklogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerminalService_Broadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastTerminalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminalServiceServer).Broadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TerminalService_Broadcast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminalServiceServer).Broadcast(ctx, req.(*BroadcastTerminalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerminalService_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTerminalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminalServiceServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TerminalService_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminalServiceServer).Search(ctx, req.(*SearchTerminalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerminalService_Multiplex_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TerminalServiceServer).Multiplex(&grpc.GenericServerStream[MultiplexTerminalRequest, MultiplexTerminalResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TerminalService_MultiplexServer = grpc.BidiStreamingServer[MultiplexTerminalRequest, MultiplexTerminalResponse]
+
 // TerminalService_ServiceDesc is the grpc.ServiceDesc for TerminalService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -437,6 +737,34 @@ var TerminalService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateAnnotations",
 			Handler:    _TerminalService_UpdateAnnotations_Handler,
 		},
+		{
+			MethodName: "SetRecording",
+			Handler:    _TerminalService_SetRecording_Handler,
+		},
+		{
+			MethodName: "Export",
+			Handler:    _TerminalService_Export_Handler,
+		},
+		{
+			MethodName: "ShareTerminal",
+			Handler:    _TerminalService_ShareTerminal_Handler,
+		},
+		{
+			MethodName: "RevokeTerminalShare",
+			Handler:    _TerminalService_RevokeTerminalShare_Handler,
+		},
+		{
+			MethodName: "ReadBacklog",
+			Handler:    _TerminalService_ReadBacklog_Handler,
+		},
+		{
+			MethodName: "Broadcast",
+			Handler:    _TerminalService_Broadcast_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _TerminalService_Search_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -444,6 +772,12 @@ var TerminalService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _TerminalService_Listen_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Multiplex",
+			Handler:       _TerminalService_Multiplex_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "terminal.proto",
 }
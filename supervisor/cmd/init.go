@@ -74,6 +74,17 @@ var initCmd = &cobra.Command{
 			return
 		}
 
+		// Forward SIGHUP to the supervisor, which reloads its configuration
+		sigReload := make(chan os.Signal, 1)
+		signal.Notify(sigReload, syscall.SIGHUP)
+		go func() {
+			for range sigReload {
+				if err := runCommand.Process.Signal(syscall.SIGHUP); err != nil {
+					log.WithError(err).Warn("cannot forward SIGHUP to supervisor")
+				}
+			}
+		}()
+
 		// Channel to signal when the supervisor process is done
 		supervisorDone := make(chan struct{})
 
@@ -20,12 +20,18 @@ func IsUnifiedCgroupSetup() (bool, error) {
 }
 
 func EnsureCpuControllerEnabled(basePath, cgroupPath string) error {
+	return EnsureControllersEnabled(basePath, cgroupPath, "cpu")
+}
+
+// EnsureControllersEnabled creates the cgroup if it doesn't exist and enables the
+// controllers for it in the cgroup.subtree_control file of all its ancestors.
+func EnsureControllersEnabled(basePath, cgroupPath string, controllers ...string) error {
 	c, err := v2.NewManager(basePath, cgroupPath, &v2.Resources{})
 	if err != nil {
 		return err
 	}
 
-	err = c.ToggleControllers([]string{"cpu"}, v2.Enable)
+	err = c.ToggleControllers(controllers, v2.Enable)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,286 @@
+package cgroups_v2
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"supervisor/pkg/cgroups"
+	"sync"
+	"syscall"
+)
+
+// Names of the sub-groups of the workspace cgroup
+const (
+	// GroupSupervisor holds supervisor itself and the processes which were in the workspace cgroup
+	GroupSupervisor = "supervisor"
+	GroupEditor     = "editor"
+	// GroupTasks holds one sub-group per task, e.g. "tasks/build"
+	GroupTasks     = "tasks"
+	GroupTerminals = "terminals"
+)
+
+// groupControllers are the controllers enabled for the sub-groups
+var groupControllers = []string{"cpu", "memory"}
+
+// unsafeGroupChars matches the characters which are replaced in task group names
+var unsafeGroupChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// GroupLimits are the resource controls of a sub-group. Zero values keep the kernel defaults.
+type GroupLimits struct {
+	// CPUWeight is the share of CPU time relative to the sibling groups, from 1 to 10000.
+	// The kernel default is 100.
+	CPUWeight uint64
+	// MemoryHigh is the memory usage in bytes above which the processes of the group are
+	// throttled and put under heavy reclaim pressure.
+	MemoryHigh uint64
+}
+
+// GroupUsage is the resource usage of a sub-group.
+type GroupUsage struct {
+	// Name of the group relative to the workspace cgroup, e.g. "editor" or "tasks/build"
+	Name string
+	// CPUUsage is the CPU time used by the group in microseconds
+	CPUUsage  uint64
+	CPUWeight uint64
+	// Memory is the memory used by the group in bytes
+	Memory uint64
+	// MemoryHigh is math.MaxUint64 if the group is not throttled
+	MemoryHigh uint64
+}
+
+// Groups manages the editor, tasks and terminals sub-groups of the workspace cgroup,
+// s.t. e.g. a runaway task cannot starve the editor.
+type Groups struct {
+	mountPoint string
+	path       string
+	limits     map[string]GroupLimits
+
+	// mu serializes the creation of task groups
+	mu sync.Mutex
+}
+
+// NewGroups creates the manager of the sub-groups of the cgroup path, relative to mountPoint.
+// limits are the resource controls per group name. Task groups share the limits of GroupTasks
+// as a whole.
+func NewGroups(mountPoint, path string, limits map[string]GroupLimits) *Groups {
+	return &Groups{
+		mountPoint: mountPoint,
+		path:       path,
+		limits:     limits,
+	}
+}
+
+// Setup moves all processes of the workspace cgroup into the supervisor group, since controllers
+// can only be enabled for the children of a cgroup without processes, and creates the editor,
+// tasks and terminals groups with their limits.
+func (g *Groups) Setup() error {
+	supervisor := filepath.Join(g.mountPoint, g.path, GroupSupervisor)
+	if err := os.MkdirAll(supervisor, 0o755); err != nil {
+		return err
+	}
+	pids, err := readPids(filepath.Join(g.mountPoint, g.path, "cgroup.procs"))
+	if err != nil {
+		return err
+	}
+	for _, pid := range pids {
+		// processes may exit while they are moved
+		if err := writeGroupFile(supervisor, "cgroup.procs", strconv.Itoa(pid)); err != nil && !errors.Is(err, syscall.ESRCH) {
+			return fmt.Errorf("cannot move process %d into the %s cgroup: %w", pid, GroupSupervisor, err)
+		}
+	}
+
+	for _, name := range []string{GroupEditor, GroupTasks, GroupTerminals} {
+		if err := g.create(name); err != nil {
+			return err
+		}
+		if err := g.applyLimits(name, g.limits[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Open opens the directory of group, which is GroupEditor, GroupTerminals or TaskGroup(name),
+// so that processes can be started in it with SysProcAttr.CgroupFD. Task groups are created as needed.
+func (g *Groups) Open(group string) (*os.File, error) {
+	if strings.HasPrefix(group, GroupTasks+"/") {
+		g.mu.Lock()
+		err := g.create(group)
+		g.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return os.Open(filepath.Join(g.mountPoint, g.path, group))
+}
+
+// Freeze freezes the processes of the groups and of their descendants if frozen is true,
+// and thaws them otherwise.
+func (g *Groups) Freeze(frozen bool, groups ...string) error {
+	value := "0"
+	if frozen {
+		value = "1"
+	}
+	for _, group := range groups {
+		if err := writeGroupFile(filepath.Join(g.mountPoint, g.path, group), "cgroup.freeze", value); err != nil {
+			return fmt.Errorf("cannot write cgroup.freeze of the %s cgroup: %w", group, err)
+		}
+	}
+	return nil
+}
+
+// Remove removes the group of a task, which fails while processes still run in it.
+// Removing a group which doesn't exist is not an error.
+func (g *Groups) Remove(group string) error {
+	if !strings.HasPrefix(group, GroupTasks+"/") {
+		return fmt.Errorf("cannot remove the %s cgroup: not a task group", group)
+	}
+	err := os.Remove(filepath.Join(g.mountPoint, g.path, group))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// TaskGroup returns the name of the group of a task. Names which had to be changed to be
+// safe get a suffix of their hash, s.t. e.g. "a/b" and "a_b" don't share a group.
+func TaskGroup(task string) string {
+	name := unsafeGroupChars.ReplaceAllString(task, "_")
+	if name == "" || strings.Trim(name, ".") == "" {
+		name = "_" + name
+	}
+	if name != task {
+		sum := sha256.Sum256([]byte(task))
+		name += "-" + hex.EncodeToString(sum[:4])
+	}
+	return GroupTasks + "/" + name
+}
+
+// Usage returns the resource usage of the editor, tasks and terminals groups and of every task group.
+func (g *Groups) Usage() ([]GroupUsage, error) {
+	names := []string{GroupEditor, GroupTasks}
+	entries, err := os.ReadDir(filepath.Join(g.mountPoint, g.path, GroupTasks))
+	if err != nil {
+		return nil, err
+	}
+	var tasks []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			tasks = append(tasks, GroupTasks+"/"+entry.Name())
+		}
+	}
+	sort.Strings(tasks)
+	names = append(append(names, tasks...), GroupTerminals)
+
+	res := make([]GroupUsage, 0, len(names))
+	for _, name := range names {
+		usage, err := g.usage(name)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read usage of the %s cgroup: %w", name, err)
+		}
+		res = append(res, usage)
+	}
+	return res, nil
+}
+
+func (g *Groups) usage(name string) (GroupUsage, error) {
+	path := filepath.Join(g.mountPoint, g.path, name)
+	stat, err := NewCpuController(path).Stat()
+	if err != nil {
+		return GroupUsage{}, err
+	}
+	weight, err := cgroups.ReadSingleValue(filepath.Join(path, "cpu.weight"))
+	if err != nil {
+		return GroupUsage{}, err
+	}
+	memory := NewMemoryController(path)
+	current, err := memory.Current()
+	if err != nil {
+		return GroupUsage{}, err
+	}
+	high, err := memory.High()
+	if err != nil {
+		return GroupUsage{}, err
+	}
+	return GroupUsage{
+		Name:       name,
+		CPUUsage:   stat.UsageTotal,
+		CPUWeight:  weight,
+		Memory:     current,
+		MemoryHigh: high,
+	}, nil
+}
+
+// create creates the group if it doesn't exist and enables the controllers for it.
+func (g *Groups) create(name string) error {
+	if err := cgroups.EnsureControllersEnabled(g.mountPoint, filepath.Join(g.path, name), groupControllers...); err != nil {
+		return fmt.Errorf("cannot create the %s cgroup: %w", name, err)
+	}
+	return nil
+}
+
+func (g *Groups) applyLimits(name string, limits GroupLimits) error {
+	path := filepath.Join(g.mountPoint, g.path, name)
+	if limits.CPUWeight > 0 {
+		if err := writeGroupFile(path, "cpu.weight", strconv.FormatUint(limits.CPUWeight, 10)); err != nil {
+			return fmt.Errorf("cannot set the cpu weight of the %s cgroup: %w", name, err)
+		}
+	}
+	if limits.MemoryHigh > 0 && limits.MemoryHigh != math.MaxUint64 {
+		if err := writeGroupFile(path, "memory.high", strconv.FormatUint(limits.MemoryHigh, 10)); err != nil {
+			return fmt.Errorf("cannot set the memory.high of the %s cgroup: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// CurrentGroup returns the cgroup v2 path of a process, read from its /proc/<pid>/cgroup file.
+func CurrentGroup(procCgroupFile string) (string, error) {
+	f, err := os.Open(procCgroupFile)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// the unified hierarchy has the ID 0 and no controllers, e.g. "0::/user.slice"
+		if path, ok := strings.CutPrefix(scanner.Text(), "0::"); ok {
+			return path, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s has no cgroup v2 entry", procCgroupFile)
+}
+
+func readPids(path string) ([]int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, field := range strings.Fields(string(content)) {
+		pid, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pid in %s: %s", path, field)
+		}
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
+
+// writeGroupFile writes a single value to a cgroup interface file.
+func writeGroupFile(path, file, value string) error {
+	return os.WriteFile(filepath.Join(path, file), []byte(value), 0o644)
+}
@@ -0,0 +1,175 @@
+package cgroups_v2
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGroups(t *testing.T) {
+	root := t.TempDir()
+	workspace := "/kubepods/pod42"
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	readFile := func(path string) string {
+		t.Helper()
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+	writeFile(filepath.Join(root, "cgroup.subtree_control"), "")
+	writeFile(filepath.Join(root, "kubepods", "cgroup.subtree_control"), "")
+	writeFile(filepath.Join(root, workspace, "cgroup.subtree_control"), "")
+	writeFile(filepath.Join(root, workspace, "cgroup.procs"), "12\n")
+
+	groups := NewGroups(root, workspace, map[string]GroupLimits{
+		GroupEditor: {CPUWeight: 200},
+		GroupTasks:  {CPUWeight: 50, MemoryHigh: 2 << 30},
+	})
+	if err := groups.Setup(); err != nil {
+		t.Fatal(err)
+	}
+
+	path := func(elem ...string) string {
+		return filepath.Join(append([]string{root, workspace}, elem...)...)
+	}
+	if diff := cmp.Diff("12", readFile(path(GroupSupervisor, "cgroup.procs"))); diff != "" {
+		t.Errorf("unexpected supervisor processes (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("+cpu +memory", readFile(path("cgroup.subtree_control"))); diff != "" {
+		t.Errorf("unexpected workspace controllers (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("200", readFile(path(GroupEditor, "cpu.weight"))); diff != "" {
+		t.Errorf("unexpected editor cpu.weight (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("2147483648", readFile(path(GroupTasks, "memory.high"))); diff != "" {
+		t.Errorf("unexpected tasks memory.high (-want +got):\n%s", diff)
+	}
+	if _, err := os.Stat(path(GroupTerminals, "cpu.weight")); !os.IsNotExist(err) {
+		t.Errorf("cpu.weight of terminals was written without limit: %v", err)
+	}
+
+	// the kernel creates the interface files of new groups
+	writeFile(path(GroupTasks, "cgroup.subtree_control"), "")
+	for group, dir := range map[string]string{
+		GroupEditor:              path(GroupEditor),
+		TaskGroup("npm install"): path(GroupTasks, "npm_install-3a2dc0ae"),
+	} {
+		f, err := groups.Open(group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = f.Close()
+		if diff := cmp.Diff(dir, f.Name()); diff != "" {
+			t.Errorf("unexpected directory of %s (-want +got):\n%s", group, diff)
+		}
+	}
+	if diff := cmp.Diff("+cpu +memory", readFile(path(GroupTasks, "cgroup.subtree_control"))); diff != "" {
+		t.Errorf("unexpected tasks controllers (-want +got):\n%s", diff)
+	}
+
+	if err := groups.Freeze(true, GroupTasks, GroupTerminals); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("1", readFile(path(GroupTerminals, "cgroup.freeze"))); diff != "" {
+		t.Errorf("unexpected terminals cgroup.freeze (-want +got):\n%s", diff)
+	}
+	if err := groups.Freeze(false, GroupTasks); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("0", readFile(path(GroupTasks, "cgroup.freeze"))); diff != "" {
+		t.Errorf("unexpected tasks cgroup.freeze (-want +got):\n%s", diff)
+	}
+
+	// the kernel reports the usage of the groups
+	for i, name := range []string{GroupEditor, GroupTasks, "tasks/npm_install-3a2dc0ae", GroupTerminals} {
+		writeFile(path(name, "cpu.stat"), "usage_usec "+strings.Repeat("1", i+1)+"\nuser_usec 1\nsystem_usec 0\n")
+		writeFile(path(name, "memory.current"), "1024\n")
+		if name != GroupTasks {
+			writeFile(path(name, "memory.high"), "max\n")
+		}
+		if name != GroupEditor {
+			writeFile(path(name, "cpu.weight"), "100\n")
+		}
+	}
+	usage, err := groups.Usage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectation := []GroupUsage{
+		{Name: GroupEditor, CPUUsage: 1, CPUWeight: 200, Memory: 1024, MemoryHigh: math.MaxUint64},
+		{Name: GroupTasks, CPUUsage: 11, CPUWeight: 100, Memory: 1024, MemoryHigh: 2 << 30},
+		{Name: "tasks/npm_install-3a2dc0ae", CPUUsage: 111, CPUWeight: 100, Memory: 1024, MemoryHigh: math.MaxUint64},
+		{Name: GroupTerminals, CPUUsage: 1111, CPUWeight: 100, Memory: 1024, MemoryHigh: math.MaxUint64},
+	}
+	if diff := cmp.Diff(expectation, usage); diff != "" {
+		t.Errorf("unexpected usage (-want +got):\n%s", diff)
+	}
+
+	// the group of a stopped task is removed once, other groups are kept
+	f, err := groups.Open(TaskGroup("lint"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+	for i := 0; i < 2; i++ {
+		if err := groups.Remove(TaskGroup("lint")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(path(GroupTasks, "lint")); !os.IsNotExist(err) {
+		t.Errorf("the lint cgroup still exists: %v", err)
+	}
+	if err := groups.Remove(GroupEditor); err == nil {
+		t.Error("expected an error when removing the editor cgroup")
+	}
+}
+
+func TestTaskGroup(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Task        string
+		Expectation string
+	}{
+		{Desc: "plain", Task: "build", Expectation: "tasks/build"},
+		{Desc: "unsafe characters", Task: "npm run dev/watch", Expectation: "tasks/npm_run_dev_watch-445e955c"},
+		{Desc: "replaced characters", Task: "a/b", Expectation: "tasks/a_b-c14cddc0"},
+		{Desc: "safe characters", Task: "a_b", Expectation: "tasks/a_b"},
+		{Desc: "dots", Task: "..", Expectation: "tasks/_..-5ec1f7e7"},
+		{Desc: "empty", Task: "", Expectation: "tasks/_-e3b0c442"},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			if diff := cmp.Diff(test.Expectation, TaskGroup(test.Task)); diff != "" {
+				t.Errorf("unexpected group (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCurrentGroup(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "cgroup")
+	if err := os.WriteFile(fn, []byte("0::/kubepods/pod42\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path, err := CurrentGroup(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("/kubepods/pod42", path); diff != "" {
+		t.Errorf("unexpected cgroup (-want +got):\n%s", diff)
+	}
+}
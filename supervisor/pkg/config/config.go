@@ -45,13 +45,21 @@ func GetConfig() (*Config, error) {
 			Error("failed to load runtime config")
 	}
 
-	log.Debug("configuration loaded successfully")
-	return &Config{
+	cfg := &Config{
 		StaticConfig:    *static,
 		WorkspaceConfig: *workspace,
 		Editor:          *editor,
 		Runtime:         *runtime,
-	}, nil
+	}
+	if err := cfg.expandTemplates(); err != nil {
+		log.WithError(err).
+			WithField("path", static.EditorConfigLocation).
+			Error("failed to load editor config")
+		return nil, err
+	}
+
+	log.Debug("configuration loaded successfully")
+	return cfg, nil
 }
 
 // EditorLogRateLimit returns the log rate limit for the IDE process in kib/sec.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // ReadinessProbeType determines the editor readiness probe type.
@@ -16,6 +17,15 @@ const (
 
 	// ReadinessHTTPProbe returns ready once a single HTTP request against the editor was successful.
 	ReadinessHTTPProbe ReadinessProbeType = "http"
+
+	// ReadinessTCPProbe returns ready once a TCP connection to the editor could be opened.
+	ReadinessTCPProbe ReadinessProbeType = "tcp"
+
+	// ReadinessExecProbe returns ready once a command exited with code 0.
+	ReadinessExecProbe ReadinessProbeType = "exec"
+
+	// ReadinessGRPCHealthProbe returns ready once the gRPC health service of the editor reports serving.
+	ReadinessGRPCHealthProbe ReadinessProbeType = "grpc-health"
 )
 
 // EditorConfig is the Editor specific configuration.
@@ -27,25 +37,102 @@ type EditorConfig struct {
 	Version string `json:"version"`
 
 	// Entrypoint is the command that gets executed by supervisor to start
-	// the Editor process. If this command exits, supervisor will start it again.
-	// If this command exits right after it was started with a non-zero exit
-	// code the workspace is stopped.
+	// the Editor process. If this command exits, supervisor will start it again
+	// according to Restart.
 	Entrypoint string `json:"entrypoint"`
 
-	// EntrypointArgs
+	// EntrypointArgs are the arguments passed to Entrypoint.
+	// Entrypoint, EntrypointArgs and the readiness probe settings are templates,
+	// see TemplateData for the available values.
 	EntrypointArgs []string `json:"entrypointArgs"`
 
-	// LogRateLimit can be used to limit the log output of the editor process.
-	// Any output that exceeds this limit is silently dropped.
+	// Port is the port the editor serves on. Defaults to 3000.
+	Port int `json:"port"`
+
+	// Source configures the archive the editor is provisioned from before it is started.
+	// If set, a relative Entrypoint is relative to the unpacked archive.
+	Source struct {
+		// URL is the http(s) URL or the local path of a .tar.gz or .tar archive of the editor.
+		URL string `json:"url"`
+
+		// SHA256 is the hex encoded checksum the archive must have. It is required if URL is set.
+		SHA256 string `json:"sha256"`
+
+		// StripComponents is the number of leading path elements removed from the archive entries,
+		// like tar --strip-components.
+		StripComponents int `json:"stripComponents"`
+
+		// CacheDir is the directory archives are unpacked in, one directory per editor version.
+		// It can be shared by workspaces, versions no workspace uses anymore are removed.
+		// Defaults to "/tmp/opencoder/editors".
+		CacheDir string `json:"cacheDir"`
+	} `json:"source"`
+
+	// LogRateLimit can be used to limit the log output of the editor process forwarded to supervisor's output.
+	// Any output that exceeds this limit is silently dropped from it, the captured logs keep it.
 	// Expressed in kb/sec. Can be overridden by the workspace config (smallest value wins).
 	LogRateLimit int `json:"logRateLimit"`
 
+	// Extensions configures how the VS Code extensions of the runtime config are installed.
+	Extensions struct {
+		// InstallCommand is the command installing an extension, e.g. ["code-server", "--install-extension"].
+		// The extension ID or the path of a .vsix file is appended to it.
+		InstallCommand []string `json:"installCommand"`
+
+		// ListCommand is the command printing the IDs of the installed extensions, one per line,
+		// e.g. ["code-server", "--list-extensions"]. Installed extensions are skipped.
+		ListCommand []string `json:"listCommand"`
+
+		// CacheDir is the directory .vsix files downloaded from a URL are cached in.
+		// Defaults to "/tmp/opencoder/extensions".
+		CacheDir string `json:"cacheDir"`
+	} `json:"extensions"`
+
+	// Logs configures how the output of the editor process is captured.
+	Logs struct {
+		// File is the path of the log file the editor output is written to.
+		// Defaults to "/tmp/opencoder/logs/editor.log".
+		File string `json:"file"`
+
+		// MaxSize is the size in MiB after which the log file is rotated. Defaults to 10.
+		MaxSize int `json:"maxSize"`
+
+		// MaxBackups is the number of rotated log files to keep. Defaults to 3.
+		MaxBackups int `json:"maxBackups"`
+
+		// BufferLines is the number of lines kept in memory for the logs API. Defaults to 5000.
+		BufferLines int `json:"bufferLines"`
+
+		// NoForward stops supervisor from also writing the editor output to its own stdout and stderr,
+		// which container log collection relies on by default.
+		NoForward bool `json:"noForward"`
+	} `json:"logs"`
+
 	// ReadinessProbe configures the probe used to serve the editor status
 	ReadinessProbe struct {
 		// Type determines the type of readiness probe we'll use.
 		// Defaults to process.
 		Type ReadinessProbeType `json:"type"`
 
+		// InitialDelay is the time to wait after the editor started before probing it.
+		InitialDelay Duration `json:"initialDelay"`
+
+		// Period is the time between two probes until the editor is ready. Defaults to 250ms.
+		Period Duration `json:"period"`
+
+		// Liveness keeps probing the editor once it is ready and restarts it when it hangs.
+		Liveness bool `json:"liveness"`
+
+		// LivenessPeriod is the time between two probes once the editor is ready. Defaults to 10s.
+		LivenessPeriod Duration `json:"livenessPeriod"`
+
+		// Timeout is the time after which a single probe fails. Defaults to 1s.
+		Timeout Duration `json:"timeout"`
+
+		// FailureThreshold is the number of consecutive failed probes after which a ready
+		// editor is considered hung and restarted. Defaults to 3.
+		FailureThreshold int `json:"failureThreshold"`
+
 		// HTTPProbe configures the HTTP readiness probe.
 		HTTPProbe struct {
 			// Schema is either "http" or "https". Defaults to "http".
@@ -54,13 +141,106 @@ type EditorConfig struct {
 			// Host is the host to make requests to. Default to "localhost".
 			Host string `json:"host"`
 
-			// Port is the port to make requests to. Default it the editor port in the supervisor config.
+			// Port is the port to make requests to. Defaults to the editor port.
 			Port int `json:"port"`
 
 			// Path is the path to make requests to. Defaults to "/".
 			Path string `json:"path"`
+
+			// Status lists the accepted status codes, either single codes or ranges
+			// like "200-299". Defaults to 200.
+			Status []StatusRange `json:"status"`
+
+			// Body is a regular expression the response body must match.
+			Body *Regexp `json:"body"`
 		} `json:"http"`
+
+		// TCPProbe configures the TCP readiness probe.
+		TCPProbe struct {
+			// Host is the host to connect to. Default to "localhost".
+			Host string `json:"host"`
+
+			// Port is the port to connect to. Defaults to the editor port.
+			Port int `json:"port"`
+		} `json:"tcp"`
+
+		// ExecProbe configures the exec readiness probe.
+		ExecProbe struct {
+			// Command is the command to run with its arguments. The editor is ready once it exits with code 0.
+			Command []string `json:"command"`
+		} `json:"exec"`
+
+		// GRPCHealthProbe configures the gRPC health readiness probe.
+		GRPCHealthProbe struct {
+			// Host is the host to connect to. Default to "localhost".
+			Host string `json:"host"`
+
+			// Port is the port to connect to. Defaults to the editor port.
+			Port int `json:"port"`
+
+			// Service is the name of the service to check, the whole server by default.
+			Service string `json:"service"`
+		} `json:"grpcHealth"`
 	} `json:"readinessProbe"`
+
+	// Restart configures how the editor is restarted once it stops.
+	Restart struct {
+		// InitialBackoff is the number of seconds to wait before restarting the editor.
+		// The delay doubles with every restart within Window, up to MaxBackoff. Defaults to 1.
+		InitialBackoff int `json:"initialBackoff"`
+
+		// MaxBackoff is the maximum number of seconds to wait before a restart. Defaults to 60.
+		MaxBackoff int `json:"maxBackoff"`
+
+		// MaxRestarts is the number of restarts within Window after which the editor is
+		// considered crash looping and is not restarted anymore. Defaults to 5.
+		MaxRestarts int `json:"maxRestarts"`
+
+		// Window is the number of minutes restarts are counted in. Defaults to 5.
+		Window int `json:"window"`
+	} `json:"restart"`
+}
+
+// defaultEditorPort is the port the editor serves on if the editor config has none.
+const defaultEditorPort = 3000
+
+// GetPort returns the port the editor serves on.
+func (c EditorConfig) GetPort() int {
+	if c.Port == 0 {
+		return defaultEditorPort
+	}
+	return c.Port
+}
+
+// defaultEditorCacheDir is the directory editor archives are unpacked in if the editor config has none.
+const defaultEditorCacheDir = "/tmp/opencoder/editors"
+
+// BundleDir returns the directory the editor archive of Source is unpacked in,
+// or an empty string if the editor has no source. The directory is specific to
+// the version and checksum of the archive.
+func (c EditorConfig) BundleDir() string {
+	if c.Source.URL == "" {
+		return ""
+	}
+	cacheDir := c.Source.CacheDir
+	if cacheDir == "" {
+		cacheDir = defaultEditorCacheDir
+	}
+	version := c.Version
+	if version == "" {
+		version = "unversioned"
+	}
+	checksum := strings.ToLower(c.Source.SHA256)
+	return filepath.Join(cacheDir, safePathElement(c.Name), safePathElement(version)+"-"+checksum[:min(12, len(checksum))])
+}
+
+// safePathElement replaces characters of s which are not allowed in a single path element.
+func safePathElement(s string) string {
+	s = strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(s)
+	if s == "" || s == "." || s == ".." {
+		return "_" + s
+	}
+	return s
 }
 
 // loadEditorConfig reads and parses an Editor configuration from the given file path.
@@ -78,6 +258,14 @@ func loadEditorConfig(configPath string) (*EditorConfig, error) {
 		return nil, fmt.Errorf("failed to parse editor config %q: %w", configPath, err)
 	}
 
+	if cfg.ReadinessProbe.Type == ReadinessExecProbe && len(cfg.ReadinessProbe.ExecProbe.Command) == 0 {
+		return nil, fmt.Errorf("invalid editor config %q: exec readiness probe without command", configPath)
+	}
+
+	if cfg.Source.URL != "" && cfg.Source.SHA256 == "" {
+		return nil, fmt.Errorf("invalid editor config %q: editor source without sha256 checksum", configPath)
+	}
+
 	// If no name is provided, default to the parent directory's name.
 	if cfg.Name == "" {
 		cfg.Name = filepath.Base(filepath.Dir(configPath))
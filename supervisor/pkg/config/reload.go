@@ -0,0 +1,250 @@
+package config
+
+import (
+	"common/log"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Diff lists the changes between two configurations.
+type Diff struct {
+	// Env lists the names of runtime environment variables which were added, changed or removed
+	Env []string
+	// TasksAdded, TasksChanged and TasksRemoved list tasks by name, or by position if they have none
+	TasksAdded   []string
+	TasksChanged []string
+	TasksRemoved []string
+	// Terminals is true if the terminal profiles changed
+	Terminals bool
+	// Motd is true if the welcome banner configuration changed
+	Motd bool
+	// Extensions lists the VS Code extensions which were added
+	Extensions []string
+	// EditorEntrypoint is true if the editor needs to be restarted because its command or archive changed
+	EditorEntrypoint bool
+	// Editor is true if any other editor setting changed, which applies once the editor restarts
+	Editor bool
+}
+
+// Empty returns true if nothing changed.
+func (d Diff) Empty() bool {
+	return reflect.DeepEqual(d, Diff{})
+}
+
+// String summarizes the changes, e.g. for logging.
+func (d Diff) String() string {
+	var res []string
+	list := func(label string, items []string) {
+		if len(items) > 0 {
+			res = append(res, fmt.Sprintf("%s: %s", label, strings.Join(items, ", ")))
+		}
+	}
+	list("env", d.Env)
+	list("tasks added", d.TasksAdded)
+	list("tasks changed", d.TasksChanged)
+	list("tasks removed", d.TasksRemoved)
+	if d.Terminals {
+		res = append(res, "terminal profiles")
+	}
+	if d.Motd {
+		res = append(res, "motd")
+	}
+	list("extensions added", d.Extensions)
+	if d.EditorEntrypoint {
+		res = append(res, "editor entrypoint")
+	}
+	if d.Editor {
+		res = append(res, "editor settings")
+	}
+	if len(res) == 0 {
+		return "no changes"
+	}
+	return strings.Join(res, "; ")
+}
+
+// Compare returns the changes of the editor and runtime configuration between two configurations.
+func Compare(from, to *Config) Diff {
+	var d Diff
+
+	for name, value := range to.Runtime.Environment {
+		if prev, exists := from.Runtime.Environment[name]; !exists || prev != value {
+			d.Env = append(d.Env, name)
+		}
+	}
+	for name := range from.Runtime.Environment {
+		if _, exists := to.Runtime.Environment[name]; !exists {
+			d.Env = append(d.Env, name)
+		}
+	}
+	sort.Strings(d.Env)
+
+	oldTasks := make(map[string]TaskConfig, len(from.Runtime.Tasks))
+	for i, task := range from.Runtime.Tasks {
+		oldTasks[TaskKey(i, task)] = task
+	}
+	newTasks := make(map[string]struct{}, len(to.Runtime.Tasks))
+	for i, task := range to.Runtime.Tasks {
+		key := TaskKey(i, task)
+		newTasks[key] = struct{}{}
+		prev, exists := oldTasks[key]
+		switch {
+		case !exists:
+			d.TasksAdded = append(d.TasksAdded, key)
+		case !reflect.DeepEqual(prev, task):
+			d.TasksChanged = append(d.TasksChanged, key)
+		}
+	}
+	for i, task := range from.Runtime.Tasks {
+		if _, exists := newTasks[TaskKey(i, task)]; !exists {
+			d.TasksRemoved = append(d.TasksRemoved, TaskKey(i, task))
+		}
+	}
+
+	d.Terminals = !reflect.DeepEqual(from.Runtime.Terminals, to.Runtime.Terminals)
+	d.Motd = from.Runtime.Motd != to.Runtime.Motd
+
+	installed := make(map[string]struct{}, len(from.Runtime.Vscode.Extensions))
+	for _, ext := range from.Runtime.Vscode.Extensions {
+		installed[ext] = struct{}{}
+	}
+	for _, ext := range to.Runtime.Vscode.Extensions {
+		if _, exists := installed[ext]; !exists {
+			d.Extensions = append(d.Extensions, ext)
+		}
+	}
+
+	d.EditorEntrypoint = from.Editor.Entrypoint != to.Editor.Entrypoint ||
+		!reflect.DeepEqual(from.Editor.EntrypointArgs, to.Editor.EntrypointArgs) ||
+		from.Editor.BundleDir() != to.Editor.BundleDir()
+	fromEditor, toEditor := from.Editor, to.Editor
+	fromEditor.Entrypoint, fromEditor.EntrypointArgs = "", nil
+	toEditor.Entrypoint, toEditor.EntrypointArgs = "", nil
+	d.Editor = !reflect.DeepEqual(fromEditor, toEditor)
+
+	return d
+}
+
+// TaskKey identifies a task by its name, or by its position if it has none.
+func TaskKey(i int, task TaskConfig) string {
+	if task.Name != nil && *task.Name != "" {
+		return *task.Name
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
+
+// Store holds the current configuration, which is replaced as a whole when the editor
+// or runtime configuration is reloaded. Configurations returned by Get are never modified.
+type Store struct {
+	// reloadMu serializes reloads
+	reloadMu sync.Mutex
+	mu       sync.RWMutex
+	cfg      *Config
+	onReload []func(cfg *Config, diff Diff)
+}
+
+// NewStore creates a store holding cfg.
+func NewStore(cfg *Config) *Store {
+	return &Store{cfg: cfg}
+}
+
+// Get returns the current configuration.
+func (s *Store) Get() *Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// OnReload registers fn to be called with the new configuration and the changes
+// whenever the configuration has been reloaded with changes.
+func (s *Store) OnReload(fn func(cfg *Config, diff Diff)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onReload = append(s.onReload, fn)
+}
+
+// Reload reads the editor and runtime configuration again. The static and workspace
+// configuration are not reloaded. If either file cannot be loaded, the current
+// configuration is kept.
+func (s *Store) Reload() (Diff, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	old := s.Get()
+	editor, err := loadEditorConfig(old.EditorConfigLocation)
+	if err != nil {
+		return Diff{}, err
+	}
+	runtime, err := loadRuntimeConfig(old.WorkspaceLocation)
+	if err != nil {
+		return Diff{}, err
+	}
+	cfg := *old
+	cfg.Editor = *editor
+	cfg.Runtime = *runtime
+	if err := cfg.expandTemplates(); err != nil {
+		return Diff{}, err
+	}
+
+	s.mu.Lock()
+	s.cfg = &cfg
+	onReload := s.onReload
+	s.mu.Unlock()
+
+	diff := Compare(old, &cfg)
+	if diff.Empty() {
+		return diff, nil
+	}
+	for _, fn := range onReload {
+		fn(&cfg, diff)
+	}
+	return diff, nil
+}
+
+// Watch reloads the configuration whenever the editor or runtime configuration file
+// changed, checking them every interval until ctx is cancelled.
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	cfg := s.Get()
+	files := []string{cfg.EditorConfigLocation, filepath.Join(cfg.WorkspaceLocation, runtimeConfigFile)}
+	last := modTimes(files)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := modTimes(files)
+		if reflect.DeepEqual(current, last) {
+			continue
+		}
+		last = current
+
+		diff, err := s.Reload()
+		if err != nil {
+			log.WithError(err).Warn("cannot reload configuration, keeping the current one")
+			continue
+		}
+		log.WithField("changes", diff.String()).Info("configuration file changed, reloaded configuration")
+	}
+}
+
+// modTimes returns the modification time and size of every file, zero if it doesn't exist.
+func modTimes(files []string) []string {
+	res := make([]string, len(files))
+	for i, fn := range files {
+		if stat, err := os.Stat(fn); err == nil {
+			res[i] = fmt.Sprintf("%d:%d", stat.ModTime().UnixNano(), stat.Size())
+		}
+	}
+	return res
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompare(t *testing.T) {
+	name := func(s string) *string { return &s }
+	base := func() *Config {
+		cfg := &Config{}
+		cfg.Editor.Entrypoint = "/ide/bin/server"
+		cfg.Editor.EntrypointArgs = []string{"--port", "3000"}
+		cfg.Runtime.Environment = map[string]string{"FOO": "foo", "BAR": "bar"}
+		cfg.Runtime.Tasks = []TaskConfig{{Name: name("build"), Command: name("make")}, {Command: name("npm start")}}
+		cfg.Runtime.Vscode.Extensions = []string{"golang.go"}
+		return cfg
+	}
+
+	tests := []struct {
+		Desc        string
+		Change      func(cfg *Config)
+		Expectation Diff
+	}{
+		{Desc: "no changes", Change: func(cfg *Config) {}},
+		{
+			Desc: "env",
+			Change: func(cfg *Config) {
+				cfg.Runtime.Environment = map[string]string{"FOO": "changed", "BAZ": "baz"}
+			},
+			Expectation: Diff{Env: []string{"BAR", "BAZ", "FOO"}},
+		},
+		{
+			Desc: "tasks",
+			Change: func(cfg *Config) {
+				cfg.Runtime.Tasks = []TaskConfig{{Name: name("test"), Command: name("make test")}, {Command: name("npm run dev")}}
+			},
+			Expectation: Diff{TasksAdded: []string{"test"}, TasksChanged: []string{"#2"}, TasksRemoved: []string{"build"}},
+		},
+		{
+			Desc: "editor entrypoint",
+			Change: func(cfg *Config) {
+				cfg.Editor.EntrypointArgs = []string{"--port", "3001"}
+			},
+			Expectation: Diff{EditorEntrypoint: true},
+		},
+		{
+			Desc: "editor settings",
+			Change: func(cfg *Config) {
+				cfg.Editor.LogRateLimit = 10
+			},
+			Expectation: Diff{Editor: true},
+		},
+		{
+			Desc: "extensions",
+			Change: func(cfg *Config) {
+				cfg.Runtime.Vscode.Extensions = []string{"golang.go", "redhat.vscode-yaml"}
+			},
+			Expectation: Diff{Extensions: []string{"redhat.vscode-yaml"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			changed := base()
+			test.Change(changed)
+			if diff := cmp.Diff(test.Expectation, Compare(base(), changed)); diff != "" {
+				t.Errorf("unexpected changes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStoreWatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	workspace := t.TempDir()
+	editorConfig := filepath.Join(t.TempDir(), "editor.json")
+	writeFile := func(fn, content string) {
+		t.Helper()
+		if err := os.WriteFile(fn, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(editorConfig, `{"name":"ide","entrypoint":"/ide/bin/server"}`)
+
+	cfg := &Config{}
+	cfg.EditorConfigLocation = editorConfig
+	cfg.WorkspaceLocation = workspace
+	cfg.Editor.Name = "ide"
+	cfg.Editor.Entrypoint = "/ide/bin/server"
+	cfg.Runtime = *newRuntimeConfig()
+	store := NewStore(cfg)
+
+	reloaded := make(chan Diff, 1)
+	store.OnReload(func(cfg *Config, diff Diff) {
+		reloaded <- diff
+	})
+	go store.Watch(ctx, 10*time.Millisecond)
+
+	// the watcher must have recorded the initial state before the files change
+	time.Sleep(50 * time.Millisecond)
+	writeFile(filepath.Join(workspace, runtimeConfigFile), "env:\n  FOO: foo\n")
+	writeFile(editorConfig, `{"name":"ide","entrypoint":"/ide/bin/server","entrypointArgs":["--verbose"]}`)
+
+	var act Diff
+	for act.Env == nil || !act.EditorEntrypoint {
+		select {
+		case diff := <-reloaded:
+			act.Env = append(act.Env, diff.Env...)
+			act.EditorEntrypoint = act.EditorEntrypoint || diff.EditorEntrypoint
+		case <-ctx.Done():
+			t.Fatalf("configuration was not reloaded, changes so far: %s", act)
+		}
+	}
+	if diff := cmp.Diff(Diff{Env: []string{"FOO"}, EditorEntrypoint: true}, act); diff != "" {
+		t.Errorf("unexpected changes (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"--verbose"}, store.Get().Editor.EntrypointArgs); diff != "" {
+		t.Errorf("unexpected entrypoint args (-want +got):\n%s", diff)
+	}
+	if cfg.Editor.EntrypointArgs != nil {
+		t.Error("reload modified the previous configuration")
+	}
+}
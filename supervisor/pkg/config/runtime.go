@@ -1,6 +1,7 @@
 package config
 
 import (
+	"common/log"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,18 +9,33 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// runtimeConfigFile is the name of the runtime configuration file in the workspace.
+const runtimeConfigFile = ".opencoder.yml"
+
 // RuntimeConfig defines the root structure of the runtime configuration file.
-// It contains environment variables, Git configuration, tasks, and VS Code settings.
+// It contains environment variables, Git configuration, tasks, terminal profiles, the welcome banner and VS Code settings.
 type RuntimeConfig struct {
-	Environment      map[string]string      `yaml:"env"`       // Arbitrary environment variables
-	GitConfiguration map[string]interface{} `yaml:"gitConfig"` // Git-related configuration values
-	Tasks            []TaskConfig           `yaml:"tasks"`     // List of tasks to run in the workspace
-	Vscode           VscodeConfig           `yaml:"vscode"`    // VS Code-specific settings
+	Environment      map[string]string       `yaml:"env"`       // Arbitrary environment variables
+	GitConfiguration map[string]interface{}  `yaml:"gitConfig"` // Git-related configuration values
+	Tasks            []TaskConfig            `yaml:"tasks"`     // List of tasks to run in the workspace
+	Terminals        []TerminalProfileConfig `yaml:"terminals"` // Named terminal profiles
+	Motd             MotdConfig              `yaml:"motd"`      // Welcome banner of new terminals
+	Vscode           VscodeConfig            `yaml:"vscode"`    // VS Code-specific settings
 }
 
 // VscodeConfig defines VS Code-related configuration such as required extensions.
 type VscodeConfig struct {
-	Extensions []string `yaml:"extensions"` // Extensions to be installed in VS Code
+	// Extensions to be installed in VS Code, either marketplace IDs like "publisher.name"
+	// optionally followed by "@version", paths of .vsix files relative to the workspace,
+	// or URLs of .vsix files
+	Extensions []string `yaml:"extensions"`
+}
+
+// MotdConfig configures the welcome banner printed in new interactive terminals,
+// which summarizes the workspace status.
+type MotdConfig struct {
+	Enabled bool   `yaml:"enabled"` // Print the banner in new interactive terminals
+	Message string `yaml:"message"` // Text shown above the workspace status
 }
 
 // TaskConfig represents the configuration of a single task that can be run
@@ -33,12 +49,25 @@ type TaskConfig struct {
 	Env      *map[string]interface{} `yaml:"env"`      // Environment variables specific to the task
 }
 
+// TerminalProfileConfig describes a named terminal configuration that can be
+// referenced when opening a terminal, so every client gets the same shell setup.
+type TerminalProfileConfig struct {
+	Name        string            `yaml:"name"`        // Unique name of the profile
+	Shell       string            `yaml:"shell"`       // Shell to start, defaults to the user's shell
+	Args        []string          `yaml:"args"`        // Arguments passed to the shell
+	Cwd         string            `yaml:"cwd"`         // Working directory, relative to the workspace if not absolute
+	Env         map[string]string `yaml:"env"`         // Environment variables specific to the profile
+	Annotations map[string]string `yaml:"annotations"` // Annotations attached to the terminal
+	Title       string            `yaml:"title"`       // Default title of the terminal
+}
+
 // NewRuntimeConfig creates a new RuntimeConfig with all properties initialized
 func newRuntimeConfig() *RuntimeConfig {
 	return &RuntimeConfig{
 		Environment:      make(map[string]string),
 		GitConfiguration: make(map[string]interface{}),
 		Tasks:            []TaskConfig{},
+		Terminals:        []TerminalProfileConfig{},
 		Vscode: VscodeConfig{
 			Extensions: []string{},
 		},
@@ -50,7 +79,7 @@ func loadRuntimeConfig(workspaceLocation string) (*RuntimeConfig, error) {
 	cfg := newRuntimeConfig()
 
 	// Construct full path to .opencoder.yaml
-	configPath := filepath.Join(workspaceLocation, ".opencoder.yml")
+	configPath := filepath.Join(workspaceLocation, runtimeConfigFile)
 
 	// Attempt to open the configuration file
 	file, err := os.Open(configPath)
@@ -76,5 +105,32 @@ func loadRuntimeConfig(workspaceLocation string) (*RuntimeConfig, error) {
 		}
 	}
 
+	// Drop ambiguous terminal profiles, the first of duplicate profiles is kept
+	seen := make(map[string]struct{}, len(cfg.Terminals))
+	profiles := cfg.Terminals[:0]
+	for i, profile := range cfg.Terminals {
+		if profile.Name == "" {
+			log.WithField("index", i).Warn("ignoring terminal profile without name")
+			continue
+		}
+		if _, exists := seen[profile.Name]; exists {
+			log.WithField("profile", profile.Name).Warn("ignoring duplicate terminal profile")
+			continue
+		}
+		seen[profile.Name] = struct{}{}
+		profiles = append(profiles, profile)
+	}
+	cfg.Terminals = profiles
+
 	return cfg, nil
 }
+
+// TerminalProfile returns the terminal profile with the given name.
+func (c RuntimeConfig) TerminalProfile(name string) (TerminalProfileConfig, bool) {
+	for _, profile := range c.Terminals {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	return TerminalProfileConfig{}, false
+}
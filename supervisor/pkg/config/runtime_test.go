@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadRuntimeConfig(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Content     string
+		Expectation []string
+		Error       bool
+	}{
+		{Desc: "no file"},
+		{
+			Desc:        "profiles",
+			Content:     "terminals:\n  - name: db-shell\n  - name: logs\n",
+			Expectation: []string{"db-shell", "logs"},
+		},
+		{
+			Desc:        "profile without name",
+			Content:     "terminals:\n  - shell: bash\n  - name: logs\n",
+			Expectation: []string{"logs"},
+		},
+		{
+			Desc:        "duplicate profile",
+			Content:     "terminals:\n  - name: db-shell\n    shell: bash\n  - name: db-shell\n    shell: zsh\n",
+			Expectation: []string{"db-shell:bash"},
+		},
+		{
+			Desc:    "invalid yaml",
+			Content: "terminals: [\n",
+			Error:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			dir := t.TempDir()
+			if test.Content != "" {
+				if err := os.WriteFile(filepath.Join(dir, runtimeConfigFile), []byte(test.Content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			cfg, err := loadRuntimeConfig(dir)
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if test.Error {
+				return
+			}
+			var profiles []string
+			for _, profile := range cfg.Terminals {
+				if profile.Shell != "" {
+					profiles = append(profiles, profile.Name+":"+profile.Shell)
+				} else {
+					profiles = append(profiles, profile.Name)
+				}
+			}
+			if diff := cmp.Diff(test.Expectation, profiles); diff != "" {
+				t.Errorf("unexpected profiles (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// TemplateData are the values available in the templated settings of the editor config,
+// e.g. "--port={{.EditorPort}}" or "{{env \"HOME\"}}/.ide".
+type TemplateData struct {
+	WorkspaceID          int64
+	WorkspaceLocation    string
+	WorkspaceUrl         string
+	WorkspaceClusterHost string
+	EditorName           string
+	EditorPort           int
+	// EditorDir is the directory the editor archive is unpacked in, if the editor has a source
+	EditorDir       string
+	APIEndpointPort int
+}
+
+// templateFuncs are the functions available in the templated settings of the editor config.
+var templateFuncs = template.FuncMap{
+	"env": os.Getenv,
+}
+
+// expandTemplates renders the templated settings of the editor config with the values
+// of the configuration.
+func (c *Config) expandTemplates() error {
+	data := TemplateData{
+		WorkspaceID:          c.WorkspaceID,
+		WorkspaceLocation:    c.WorkspaceLocation,
+		WorkspaceUrl:         c.WorkspaceUrl,
+		WorkspaceClusterHost: c.WorkspaceClusterHost,
+		EditorName:           c.Editor.Name,
+		EditorPort:           c.Editor.GetPort(),
+		EditorDir:            c.Editor.BundleDir(),
+		APIEndpointPort:      c.APIEndpointPort,
+	}
+
+	probe := &c.Editor.ReadinessProbe
+	fields := map[string]*string{
+		"entrypoint":                        &c.Editor.Entrypoint,
+		"readinessProbe.http.schema":        &probe.HTTPProbe.Schema,
+		"readinessProbe.http.host":          &probe.HTTPProbe.Host,
+		"readinessProbe.http.path":          &probe.HTTPProbe.Path,
+		"readinessProbe.tcp.host":           &probe.TCPProbe.Host,
+		"readinessProbe.grpcHealth.host":    &probe.GRPCHealthProbe.Host,
+		"readinessProbe.grpcHealth.service": &probe.GRPCHealthProbe.Service,
+	}
+	for i := range c.Editor.EntrypointArgs {
+		fields[fmt.Sprintf("entrypointArgs[%d]", i)] = &c.Editor.EntrypointArgs[i]
+	}
+	for i := range probe.ExecProbe.Command {
+		fields[fmt.Sprintf("readinessProbe.exec.command[%d]", i)] = &probe.ExecProbe.Command[i]
+	}
+
+	for name, field := range fields {
+		res, err := expandTemplate(*field, data)
+		if err != nil {
+			return fmt.Errorf("invalid template in editor config %s: %w", name, err)
+		}
+		*field = res
+	}
+
+	if data.EditorDir != "" && c.Editor.Entrypoint != "" && !filepath.IsAbs(c.Editor.Entrypoint) {
+		c.Editor.Entrypoint = filepath.Join(data.EditorDir, c.Editor.Entrypoint)
+	}
+	return nil
+}
+
+// expandTemplate renders text as template with data. Text without template actions is returned as-is.
+func expandTemplate(text string, data TemplateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tpl, err := template.New("").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var res strings.Builder
+	if err := tpl.Execute(&res, data); err != nil {
+		return "", err
+	}
+	return res.String(), nil
+}
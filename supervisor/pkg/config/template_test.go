@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExpandTemplates(t *testing.T) {
+	t.Setenv("IDE_HOME", "/opt/ide")
+
+	tests := []struct {
+		Desc        string
+		Port        int
+		Args        []string
+		Expectation []string
+		Error       bool
+	}{
+		{
+			Desc:        "verbatim",
+			Args:        []string{"--host", "0.0.0.0"},
+			Expectation: []string{"--host", "0.0.0.0"},
+		},
+		{
+			Desc:        "workspace",
+			Args:        []string{"--port={{.EditorPort}}", "{{.WorkspaceLocation}}", "--id={{.WorkspaceID}}"},
+			Expectation: []string{"--port=3000", "/workspace", "--id=42"},
+		},
+		{
+			Desc:        "editor port",
+			Port:        8080,
+			Args:        []string{"--port={{.EditorPort}}"},
+			Expectation: []string{"--port=8080"},
+		},
+		{
+			Desc:        "env",
+			Args:        []string{`--extensions-dir={{env "IDE_HOME"}}/extensions`},
+			Expectation: []string{"--extensions-dir=/opt/ide/extensions"},
+		},
+		{Desc: "unknown value", Args: []string{"{{.Unknown}}"}, Error: true},
+		{Desc: "syntax error", Args: []string{"{{.EditorPort"}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.WorkspaceID = 42
+			cfg.WorkspaceLocation = "/workspace"
+			cfg.Editor.Port = test.Port
+			cfg.Editor.EntrypointArgs = test.Args
+			cfg.Editor.ReadinessProbe.HTTPProbe.Path = "/healthz?port={{.EditorPort}}"
+
+			err := cfg.expandTemplates()
+			if test.Error {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expectation, cfg.Editor.EntrypointArgs); diff != "" {
+				t.Errorf("unexpected args (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff("/healthz?port="+fmt.Sprint(cfg.Editor.GetPort()), cfg.Editor.ReadinessProbe.HTTPProbe.Path); diff != "" {
+				t.Errorf("unexpected probe path (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a time.Duration read from JSON either as a duration string
+// like "250ms" or as a number of seconds.
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case float64:
+		*d = Duration(v * float64(time.Second))
+	case string:
+		dur, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*d = Duration(dur)
+	default:
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	return nil
+}
+
+// StatusRange is an inclusive range of HTTP status codes, read from JSON either as a
+// single code like 200 or "200", or as a range like "200-299".
+type StatusRange struct {
+	Min int
+	Max int
+}
+
+// Contains returns true if code is within the range.
+func (r StatusRange) Contains(code int) bool {
+	return code >= r.Min && code <= r.Max
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *StatusRange) UnmarshalJSON(data []byte) error {
+	var code int
+	if err := json.Unmarshal(data, &code); err == nil {
+		*r = StatusRange{Min: code, Max: code}
+		return nil
+	}
+
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("invalid status range %s", data)
+	}
+	lo, hi, isRange := strings.Cut(v, "-")
+	if !isRange {
+		hi = lo
+	}
+	from, err := strconv.Atoi(strings.TrimSpace(lo))
+	if err != nil {
+		return fmt.Errorf("invalid status range %q", v)
+	}
+	to, err := strconv.Atoi(strings.TrimSpace(hi))
+	if err != nil || to < from {
+		return fmt.Errorf("invalid status range %q", v)
+	}
+	*r = StatusRange{Min: from, Max: to}
+	return nil
+}
+
+// Regexp is a regular expression read from JSON as a string.
+type Regexp struct {
+	*regexp.Regexp
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *Regexp) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	re, err := regexp.Compile(v)
+	if err != nil {
+		return err
+	}
+	r.Regexp = re
+	return nil
+}
@@ -32,14 +32,65 @@ type WorkspaceConfig struct {
 	// OwnerId is the user id who owns the workspace
 	OwnerId int64 `env:"OPENCODER_OWNER_ID"`
 
-	// LogRateLimit limits the log output of the editor process.
-	// Any output that exceeds this limit is silently dropped.
+	// LogRateLimit limits the log output of the editor process forwarded to supervisor's output.
+	// Any output that exceeds this limit is silently dropped from it, the captured logs keep it.
 	// Expressed in kb/sec. Can be overridden by the IDE config (smallest value wins).
 	LogRateLimit int `env:"OPENCODER_RATE_LIMIT_LOG"`
 
 	// WorkspaceClusterHost is a host under which this workspace is served, e.g. fr42.oc.dev.local
 	WorkspaceClusterHost string `env:"OPENCODER_WORKSPACE_CLUSTER_HOST"`
 
+	// OwnerAuth makes supervisor require the owner token in the metadata of every API call.
+	// Terminals shared through ShareTerminal stay accessible with their share token. Without it,
+	// calls without a token have the access of the workspace owner.
+	OwnerAuth bool `env:"OPENCODER_OWNER_AUTH"`
+
+	// RestoreTerminals makes supervisor persist its terminals and re-spawn them after a restart.
+	RestoreTerminals bool `env:"OPENCODER_RESTORE_TERMINALS"`
+
+	// TerminalBacklogSize is the number of KiB of output kept in memory for each terminal.
+	// Defaults to 256 KiB.
+	TerminalBacklogSize int64 `env:"OPENCODER_TERMINAL_BACKLOG_SIZE"`
+
+	// TerminalBacklogSpill makes supervisor keep the complete output of terminals on disk,
+	// s.t. output evicted from memory can still be read.
+	TerminalBacklogSpill bool `env:"OPENCODER_TERMINAL_BACKLOG_SPILL"`
+
+	// TerminalBacklogDiskLimit caps the disk usage of all spilled terminal output.
+	// Expressed in MiB, defaults to 256 MiB.
+	TerminalBacklogDiskLimit int64 `env:"OPENCODER_TERMINAL_BACKLOG_DISK_LIMIT"`
+
+	// TerminalMax is the maximum number of open terminals. 0 means no limit.
+	TerminalMax int `env:"OPENCODER_TERMINAL_MAX"`
+
+	// TerminalLimitAnnotation is the annotation terminals are grouped by for TerminalMaxPerAnnotation,
+	// e.g. the annotation identifying the client that opened them.
+	TerminalLimitAnnotation string `env:"OPENCODER_TERMINAL_LIMIT_ANNOTATION"`
+
+	// TerminalMaxPerAnnotation is the maximum number of open terminals sharing a value of
+	// TerminalLimitAnnotation. 0 means no limit.
+	TerminalMaxPerAnnotation int `env:"OPENCODER_TERMINAL_MAX_PER_ANNOTATION"`
+
+	// Cgroups makes supervisor run the editor, tasks and terminals in dedicated sub-groups of the
+	// workspace cgroup, s.t. a runaway task cannot starve the editor. Requires cgroup v2.
+	Cgroups bool `env:"OPENCODER_CGROUPS"`
+
+	// EditorCPUWeight, TasksCPUWeight and TerminalsCPUWeight are the cpu.weight of the sub-groups,
+	// i.e. their share of CPU time relative to each other. Default to 200, 50 and 100.
+	EditorCPUWeight    uint64 `env:"OPENCODER_CGROUP_EDITOR_CPU_WEIGHT"`
+	TasksCPUWeight     uint64 `env:"OPENCODER_CGROUP_TASKS_CPU_WEIGHT"`
+	TerminalsCPUWeight uint64 `env:"OPENCODER_CGROUP_TERMINALS_CPU_WEIGHT"`
+
+	// EditorMemoryHigh, TasksMemoryHigh and TerminalsMemoryHigh are the memory.high of the sub-groups,
+	// above which their processes are throttled. Expressed in MiB, 0 means no limit.
+	EditorMemoryHigh    uint64 `env:"OPENCODER_CGROUP_EDITOR_MEMORY_HIGH"`
+	TasksMemoryHigh     uint64 `env:"OPENCODER_CGROUP_TASKS_MEMORY_HIGH"`
+	TerminalsMemoryHigh uint64 `env:"OPENCODER_CGROUP_TERMINALS_MEMORY_HIGH"`
+
+	// HibernateIdleTimeout is the number of seconds without API calls after which the processes of
+	// the tasks and terminals are frozen, until the next API call. Requires Cgroups, 0 disables it.
+	HibernateIdleTimeout int `env:"OPENCODER_HIBERNATE_IDLE_TIMEOUT"`
+
 	// TerminationGracePeriodSeconds is the max number of seconds the workspace can take to shut down all its processes after SIGTERM was sent.
 	TerminationGracePeriodSeconds *int `env:"OPENCODER_TERMINATION_GRACE_PERIOD_SECONDS"`
 }
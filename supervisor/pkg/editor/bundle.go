@@ -0,0 +1,296 @@
+package editor
+
+import (
+	"archive/tar"
+	"bufio"
+	"common/log"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"supervisor/pkg/config"
+	"syscall"
+)
+
+const (
+	// bundleLockFile serializes provisioning of an editor across workspaces sharing the cache
+	bundleLockFile = ".lock"
+	// bundleInUseSuffix is the suffix of the file next to a version directory which workspaces
+	// using the version hold a shared lock on
+	bundleInUseSuffix = ".inuse"
+)
+
+// provisionEditor makes sure the editor archive configured as source is unpacked in
+// its versioned directory, and marks the version as used until release is called.
+// Versions of the editor no workspace uses anymore are removed from the cache.
+// It does nothing if the editor has no source.
+func provisionEditor(ctx context.Context, cfg *config.Config) (release func(), err error) {
+	dir := cfg.Editor.BundleDir()
+	if dir == "" {
+		return func() {}, nil
+	}
+	base := filepath.Dir(dir)
+	if err := os.MkdirAll(base, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create editor cache: %w", err)
+	}
+
+	unlock, err := lockFile(filepath.Join(base, bundleLockFile), syscall.LOCK_EX)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		log.WithField("source", cfg.Editor.Source.URL).WithField("dir", dir).Info("provisioning editor")
+		if err := fetchBundle(ctx, cfg, dir); err != nil {
+			return nil, err
+		}
+		log.WithField("dir", dir).Info("editor provisioned")
+	} else if err != nil {
+		return nil, err
+	}
+
+	release, err = lockFile(dir+bundleInUseSuffix, syscall.LOCK_SH)
+	if err != nil {
+		return nil, err
+	}
+	removeUnusedBundles(base, dir)
+	return release, nil
+}
+
+// removeUnusedBundles removes the version directories of base other than keep which no
+// workspace holds the in-use lock of. Callers are expected to hold the lock of base.
+func removeUnusedBundles(base, keep string) {
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		log.WithError(err).WithField("dir", base).Warn("cannot clean up editor cache")
+		return
+	}
+	for _, entry := range entries {
+		dir := filepath.Join(base, entry.Name())
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || dir == keep {
+			continue
+		}
+		unlock, err := lockFile(dir+bundleInUseSuffix, syscall.LOCK_EX|syscall.LOCK_NB)
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			continue
+		}
+		if err != nil {
+			log.WithError(err).WithField("dir", dir).Warn("cannot clean up unused editor")
+			continue
+		}
+		err = os.RemoveAll(dir)
+		if err == nil {
+			err = os.Remove(dir + bundleInUseSuffix)
+		}
+		unlock()
+		if err != nil {
+			log.WithError(err).WithField("dir", dir).Warn("cannot clean up unused editor")
+			continue
+		}
+		log.WithField("dir", dir).Info("removed unused editor")
+	}
+}
+
+// fetchBundle downloads or copies the editor archive, verifies its checksum and unpacks
+// it into dir. dir only appears once the archive has been unpacked completely.
+func fetchBundle(ctx context.Context, cfg *config.Config, dir string) error {
+	src := cfg.Editor.Source
+	if src.SHA256 == "" {
+		return errors.New("editor source has no sha256 checksum")
+	}
+	base := filepath.Dir(dir)
+
+	archive, err := os.CreateTemp(base, ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	hash := sha256.New()
+	if err := copySource(ctx, src.URL, io.MultiWriter(archive, hash)); err != nil {
+		return err
+	}
+	if checksum := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(checksum, src.SHA256) {
+		return fmt.Errorf("editor archive has checksum %s, expected %s", checksum, src.SHA256)
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp(base, ".unpack-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	if err := unpackTar(archive, tmp, src.StripComponents); err != nil {
+		return fmt.Errorf("cannot unpack editor archive: %w", err)
+	}
+	if err := os.Chmod(tmp, 0o755); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dir)
+}
+
+// copySource writes the content of a http(s) URL or a local file to w.
+func copySource(ctx context.Context, source string, w io.Writer) error {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		f, err := os.Open(source)
+		if err != nil {
+			return fmt.Errorf("cannot read editor archive: %w", err)
+		}
+		defer f.Close()
+		_, err = io.Copy(w, f)
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot download editor archive: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cannot download editor archive: unexpected status code %d", resp.StatusCode)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("cannot download editor archive: %w", err)
+	}
+	return nil
+}
+
+// unpackTar unpacks a tar archive, which may be gzip compressed, into dir.
+// The first strip elements of every path are removed.
+func unpackTar(r io.Reader, dir string, strip int) error {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := strings.Trim(filepath.ToSlash(filepath.Clean(hdr.Name)), "/")
+		if name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("archive entry %q is outside of the archive", hdr.Name)
+		}
+		parts := strings.Split(name, "/")
+		if len(parts) <= strip {
+			continue
+		}
+		parts = parts[strip:]
+		target := filepath.Join(dir, filepath.Join(parts...))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := makeDirs(dir, parts); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := prepareEntry(dir, parts); err != nil {
+				return err
+			}
+			// O_EXCL never follows a symlink created by an earlier entry
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, hdr.FileInfo().Mode().Perm())
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			link := filepath.ToSlash(hdr.Linkname)
+			resolved := path.Join(path.Dir(strings.Join(parts, "/")), link)
+			if path.IsAbs(link) || resolved == ".." || strings.HasPrefix(resolved, "../") {
+				return fmt.Errorf("archive entry %q links outside of the archive to %q", hdr.Name, hdr.Linkname)
+			}
+			if err := prepareEntry(dir, parts); err != nil {
+				return err
+			}
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+		default:
+			log.WithField("name", hdr.Name).WithField("type", string(hdr.Typeflag)).Debug("skipping unsupported editor archive entry")
+		}
+	}
+}
+
+// makeDirs creates the directories of parts below dir. It fails if one of them
+// exists but is not a directory, so that entries are never unpacked through a symlink.
+func makeDirs(dir string, parts []string) error {
+	for _, part := range parts {
+		dir = filepath.Join(dir, part)
+		if err := os.Mkdir(dir, 0o755); err != nil && !os.IsExist(err) {
+			return err
+		}
+		stat, err := os.Lstat(dir)
+		if err != nil {
+			return err
+		}
+		if !stat.IsDir() {
+			return fmt.Errorf("archive entry %q is not a directory", dir)
+		}
+	}
+	return nil
+}
+
+// prepareEntry creates the parent directories of the file or link of parts below dir
+// and removes an entry of the same name unpacked before.
+func prepareEntry(dir string, parts []string) error {
+	if err := makeDirs(dir, parts[:len(parts)-1]); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(dir, filepath.Join(parts...))); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// lockFile takes a flock of how, e.g. syscall.LOCK_EX, on fn, which is created if it doesn't
+// exist. It blocks until the lock is available unless how includes syscall.LOCK_NB.
+// Call unlock to release it.
+func lockFile(fn string, how int) (unlock func(), err error) {
+	f, err := os.OpenFile(fn, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot lock editor cache: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), how); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("cannot lock editor cache: %w", err)
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		_ = f.Close()
+	}, nil
+}
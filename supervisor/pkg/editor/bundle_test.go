@@ -0,0 +1,267 @@
+package editor
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"supervisor/pkg/config"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestProvisionEditor(t *testing.T) {
+	archive := editorArchive(t, map[string]string{
+		"ide-1.2.3/":           "",
+		"ide-1.2.3/bin/editor": "#!/bin/sh\necho editor\n",
+		"ide-1.2.3/README":     "readme",
+	})
+	hash := sha256.Sum256(archive)
+	checksum := hex.EncodeToString(hash[:])
+
+	var downloads atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		_, _ = w.Write(archive)
+	}))
+	defer srv.Close()
+
+	local := filepath.Join(t.TempDir(), "ide.tar.gz")
+	if err := os.WriteFile(local, archive, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	newConfig := func(url, sha string) *config.Config {
+		cfg := &config.Config{}
+		cfg.Editor.Name = "ide"
+		cfg.Editor.Version = "1.2.3"
+		cfg.Editor.Source.URL = url
+		cfg.Editor.Source.SHA256 = sha
+		cfg.Editor.Source.StripComponents = 1
+		cfg.Editor.Source.CacheDir = t.TempDir()
+		return cfg
+	}
+	checkBundle := func(t *testing.T, cfg *config.Config) {
+		t.Helper()
+		dir := cfg.Editor.BundleDir()
+		if stat, err := os.Stat(filepath.Join(dir, "bin", "editor")); err != nil {
+			t.Fatalf("editor was not unpacked: %v", err)
+		} else if stat.Mode().Perm()&0o100 == 0 {
+			t.Errorf("editor is not executable: %v", stat.Mode())
+		}
+	}
+
+	t.Run("download once", func(t *testing.T) {
+		downloads.Store(0)
+		cfg := newConfig(srv.URL+"/ide.tar.gz", checksum)
+		for i := 0; i < 2; i++ {
+			release, err := provisionEditor(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer release()
+		}
+		checkBundle(t, cfg)
+		if n := downloads.Load(); n != 1 {
+			t.Errorf("archive was downloaded %d times, expected once", n)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		downloads.Store(0)
+		cfg := newConfig(srv.URL+"/ide.tar.gz", checksum)
+		var wg sync.WaitGroup
+		errs := make(chan error, 5)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				release, err := provisionEditor(context.Background(), cfg)
+				if err == nil {
+					defer release()
+				}
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		checkBundle(t, cfg)
+		if n := downloads.Load(); n != 1 {
+			t.Errorf("archive was downloaded %d times, expected once", n)
+		}
+	})
+
+	t.Run("local path", func(t *testing.T) {
+		cfg := newConfig(local, checksum)
+		release, err := provisionEditor(context.Background(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer release()
+		checkBundle(t, cfg)
+	})
+
+	t.Run("remove unused versions", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		newVersion := func(version string) *config.Config {
+			cfg := newConfig(local, checksum)
+			cfg.Editor.Version = version
+			cfg.Editor.Source.CacheDir = cacheDir
+			return cfg
+		}
+		v1, v2, v3 := newVersion("1"), newVersion("2"), newVersion("3")
+
+		// a version in use by another workspace is kept
+		release1, err := provisionEditor(context.Background(), v1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		release2, err := provisionEditor(context.Background(), v2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkBundle(t, v1)
+		checkBundle(t, v2)
+
+		release1()
+		release2()
+		release3, err := provisionEditor(context.Background(), v3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer release3()
+		checkBundle(t, v3)
+		for _, cfg := range []*config.Config{v1, v2} {
+			if _, err := os.Stat(cfg.Editor.BundleDir()); !os.IsNotExist(err) {
+				t.Errorf("unused editor version %s was not removed: %v", cfg.Editor.Version, err)
+			}
+		}
+	})
+
+	t.Run("missing checksum", func(t *testing.T) {
+		cfg := newConfig(local, "")
+		if _, err := provisionEditor(context.Background(), cfg); err == nil {
+			t.Fatal("expected an error")
+		}
+		if _, err := os.Stat(cfg.Editor.BundleDir()); !os.IsNotExist(err) {
+			t.Errorf("editor directory exists after a failed provisioning: %v", err)
+		}
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		cfg := newConfig(local, "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+		if _, err := provisionEditor(context.Background(), cfg); err == nil {
+			t.Fatal("expected an error")
+		}
+		if _, err := os.Stat(cfg.Editor.BundleDir()); !os.IsNotExist(err) {
+			t.Errorf("editor directory exists after a failed provisioning: %v", err)
+		}
+	})
+
+	t.Run("path traversal", func(t *testing.T) {
+		evil := editorArchive(t, map[string]string{"ide/../../evil": "evil"})
+		fn := filepath.Join(t.TempDir(), "evil.tar")
+		if err := os.WriteFile(fn, evil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		hash := sha256.Sum256(evil)
+		cfg := newConfig(fn, hex.EncodeToString(hash[:]))
+		if _, err := provisionEditor(context.Background(), cfg); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("no source", func(t *testing.T) {
+		if _, err := provisionEditor(context.Background(), &config.Config{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestUnpackTarLinks(t *testing.T) {
+	symlink := func(name, target string) *tar.Header {
+		return &tar.Header{Name: name, Linkname: target, Typeflag: tar.TypeSymlink}
+	}
+	file := func(name string) *tar.Header {
+		return &tar.Header{Name: name, Mode: 0o644, Typeflag: tar.TypeReg}
+	}
+	dir := func(name string) *tar.Header {
+		return &tar.Header{Name: name, Mode: 0o755, Typeflag: tar.TypeDir}
+	}
+
+	tests := []struct {
+		Desc        string
+		Entries     []*tar.Header
+		Expectation bool
+	}{
+		{Desc: "relative link", Entries: []*tar.Header{file("ide/bin/editor"), symlink("ide/editor", "bin/editor")}, Expectation: true},
+		{Desc: "link to parent", Entries: []*tar.Header{file("ide/README"), symlink("ide/bin/README", "../README")}, Expectation: true},
+		{Desc: "absolute link", Entries: []*tar.Header{symlink("ide/etc", "/etc")}},
+		{Desc: "escaping link", Entries: []*tar.Header{symlink("ide/up", "../..")}},
+		{Desc: "file through link", Entries: []*tar.Header{dir("ide/bin/"), symlink("ide/lib", "bin"), file("ide/lib/editor")}},
+		{Desc: "directory through link", Entries: []*tar.Header{dir("ide/bin/"), symlink("ide/lib", "bin"), dir("ide/lib/sub/")}},
+		{Desc: "file replacing link", Entries: []*tar.Header{file("ide/bin/editor"), symlink("ide/editor", "bin/editor"), file("ide/editor")}, Expectation: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			tw := tar.NewWriter(&buf)
+			for _, hdr := range test.Entries {
+				if err := tw.WriteHeader(hdr); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			target := filepath.Join(t.TempDir(), "ide")
+			if err := os.Mkdir(target, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			err := unpackTar(&buf, target, 1)
+			if ok := err == nil; ok != test.Expectation {
+				t.Errorf("expected unpack success to be %v, got error: %v", test.Expectation, err)
+			}
+		})
+	}
+}
+
+// editorArchive returns a .tar.gz archive of files, names ending with a slash are directories.
+func editorArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if name[len(name)-1] == '/' {
+			hdr.Typeflag, hdr.Size = tar.TypeDir, 0
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
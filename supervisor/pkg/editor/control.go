@@ -0,0 +1,122 @@
+package editor
+
+import (
+	"context"
+	"os/exec"
+	"supervisor/pkg/config"
+	"sync"
+	"time"
+)
+
+// controlAction is a lifecycle change requested through Control.
+type controlAction string
+
+const (
+	actionRestart controlAction = "restart"
+	actionStop    controlAction = "stop"
+)
+
+type controlRequest struct {
+	action controlAction
+	// done is closed once the request has been applied
+	done chan struct{}
+}
+
+// applied signals the requester that the request has been applied.
+func (r controlRequest) applied() {
+	if r.done != nil {
+		close(r.done)
+	}
+}
+
+// Control requests lifecycle changes of the editor run by StartAndWatchEditor.
+type Control struct {
+	requests chan controlRequest
+
+	mu      sync.Mutex
+	cfg     *config.Config
+	prepare func(cmd *exec.Cmd) (release func())
+}
+
+// NewEditorControl creates a control for the editor.
+func NewEditorControl() *Control {
+	return &Control{
+		requests: make(chan controlRequest),
+	}
+}
+
+// Restart stops the editor and starts it again right away. A failed or stopped
+// editor is started again. It returns once the new editor process is being started.
+func (c *Control) Restart(ctx context.Context) error {
+	return c.request(ctx, actionRestart)
+}
+
+// Stop stops the editor until Restart is called. It returns once the editor process has exited.
+func (c *Control) Stop(ctx context.Context) error {
+	return c.request(ctx, actionStop)
+}
+
+// Configure sets the configuration the editor is started with from its next start on.
+func (c *Control) Configure(cfg *config.Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cfg = cfg
+}
+
+// PrepareStart sets fn to be called with the command of every editor process before it is started,
+// e.g. to start it in a cgroup. The returned release function is called once it started.
+func (c *Control) PrepareStart(fn func(cmd *exec.Cmd) (release func())) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prepare = fn
+}
+
+// prepareStart calls the function set with PrepareStart and returns its release function.
+func (c *Control) prepareStart(cmd *exec.Cmd) (release func()) {
+	c.mu.Lock()
+	fn := c.prepare
+	c.mu.Unlock()
+	if fn == nil {
+		return func() {}
+	}
+	return fn(cmd)
+}
+
+// config returns the configuration set with Configure, or cfg if there is none.
+func (c *Control) config(cfg *config.Config) *config.Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cfg == nil {
+		return cfg
+	}
+	return c.cfg
+}
+
+func (c *Control) request(ctx context.Context, action controlAction) error {
+	req := controlRequest{action: action, done: make(chan struct{})}
+	select {
+	case c.requests <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-req.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wait blocks until the timer fires, a control request arrives or ctx is cancelled.
+// It returns a zero request if the timer fired and false if ctx was cancelled.
+// A nil timer never fires.
+func (c *Control) wait(ctx context.Context, timer <-chan time.Time) (controlRequest, bool) {
+	select {
+	case <-timer:
+		return controlRequest{}, true
+	case req := <-c.requests:
+		return req, true
+	case <-ctx.Done():
+		return controlRequest{}, false
+	}
+}
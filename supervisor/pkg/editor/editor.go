@@ -3,6 +3,9 @@ package editor
 import (
 	"common/log"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
@@ -14,49 +17,200 @@ import (
 	"time"
 )
 
-const timeBudgetIDEShutdown = 15 * time.Second
+const (
+	timeBudgetIDEShutdown = 15 * time.Second
+	// outputWaitDelay is the time to wait for the editor output to be closed once it exited
+	outputWaitDelay = 2 * time.Second
+)
+
+// editorExit is the outcome of an editor process.
+type editorExit struct {
+	err error
+	// started is false if the process could not be started at all
+	started bool
+	// reason overrides the exit reason if supervisor stopped the editor itself
+	reason string
+}
 
 // StartAndWatchEditor launches the configured editor process and continuously monitors it.
-func StartAndWatchEditor(ctx context.Context, cfg *config.Config, wg *sync.WaitGroup, ideReady *ReadyState) {
+// The editor is restarted with an exponential backoff once it stops, until it stops
+// too often within the configured window and is marked as failed. ideControl restarts
+// and stops the editor on request. The output of the editor is captured in ideLogs.
+func StartAndWatchEditor(ctx context.Context, cfg *config.Config, wg *sync.WaitGroup, ideReady *ReadyState, ideStatus *StatusState, ideControl *Control, ideLogs *Logs) {
 	defer wg.Done()
 	defer log.Debug("Editor supervisor stopped")
 
-	//
-	var cmd *exec.Cmd
-	var ideStopped chan struct{}
+	watchEditor(ctx, cfg, ideReady, ideStatus, ideControl, ideLogs, newRestartPolicy(cfg))
+}
+
+// newRestartPolicy returns the restart policy of the editor config with defaults applied.
+func newRestartPolicy(cfg *config.Config) restartPolicy {
+	restart := cfg.Editor.Restart
+	return restartPolicy{
+		InitialBackoff: time.Duration(defaultIfZero(restart.InitialBackoff, 1)) * time.Second,
+		MaxBackoff:     time.Duration(defaultIfZero(restart.MaxBackoff, 60)) * time.Second,
+		MaxRestarts:    defaultIfZero(restart.MaxRestarts, 5),
+		Window:         time.Duration(defaultIfZero(restart.Window, 5)) * time.Minute,
+	}
+}
 
+// watchEditor runs the editor until ctx is cancelled, restarting it according to policy.
+func watchEditor(ctx context.Context, cfg *config.Config, ideReady *ReadyState, ideStatus *StatusState, ideControl *Control, ideLogs *Logs, policy restartPolicy) {
+	history := crashHistory{policy: policy}
 	firstStart := true
+	var pending controlRequest
+	// releaseBundle marks the editor version started last as unused
+	releaseBundle := func() {}
+	defer func() { releaseBundle() }()
 	for {
-		// Launch a new editor process
-		ideStopped = make(chan struct{}, 1)
-		cmd = prepareEditorLaunch(cfg)
-		launchEditor(cfg, cmd, ideStopped, ideReady)
-
-		// Only track readiness on the first start
-		if firstStart {
-			firstStart = false
-			go monitorReadiness(ctx, ideReady)
+		// Launch a new editor process with the latest configuration
+		cfg = ideControl.config(cfg)
+		var (
+			req controlRequest
+			ok  = true
+		)
+		release, err := provisionEditor(ctx, cfg)
+		if err != nil {
+			log.WithError(err).Error("cannot provision editor")
+			pending.applied()
+			req, ok = awaitRestart(ctx, editorExit{err: fmt.Errorf("cannot provision editor: %w", err)}, &history, ideStatus, ideControl)
+		} else {
+			// the version started before can be cleaned up once no workspace uses it
+			releaseBundle()
+			releaseBundle = release
+			ideStopped := make(chan editorExit, 1)
+			cmd, flushOutput := prepareEditorLaunch(cfg, ideLogs)
+			launchEditor(ctx, cfg, cmd, flushOutput, ideStopped, ideReady, ideStatus, ideLogs, ideControl.prepareStart)
+			pending.applied()
+
+			// Only track readiness on the first start
+			if firstStart {
+				firstStart = false
+				go monitorReadiness(ctx, ideReady)
+			}
+
+			// Wait until either the editor stops, a change is requested or the supervisor is cancelled
+			select {
+			case exit := <-ideStopped:
+				// Editor stopped unexpectedly -> cleanup and restart
+				if exit.started {
+					_ = syscall.Kill(-1*cmd.Process.Pid, syscall.SIGKILL)
+				}
+				req, ok = awaitRestart(ctx, exit, &history, ideStatus, ideControl)
+
+			case req = <-ideControl.requests:
+				log.WithField("action", req.action).Info("stopping editor on request")
+				terminateEditor(cmd, ideStopped)
+				ideStatus.update(func(status *Status) {
+					status.Pid = 0
+					status.Reason = fmt.Sprintf("%s requested", req.action)
+				})
+
+			case <-ctx.Done():
+				// Supervisor shutdown requested
+				log.Info("context cancelled, stopping editor")
+				gracefulStop(cmd, ideStopped)
+				ok = false
+			}
 		}
 
-		// Wait until either the editor stops or the supervisor is cancelled
-		select {
-		case <-ideStopped:
-			// Editor stopped unexpectedly -> cleanup and restart
-			_ = syscall.Kill(-1*cmd.Process.Pid, syscall.SIGKILL)
-			time.Sleep(1 * time.Second)
-
-		case <-ctx.Done():
-			// Supervisor shutdown requested
-			log.Info("context cancelled, stopping editor")
-			gracefulStop(cmd, ideStopped)
+		// A stopped editor waits to be restarted
+		for ok && req.action == actionStop {
+			ideStatus.update(func(status *Status) {
+				status.Phase = PhaseStopped
+				status.NextRestart = time.Time{}
+			})
+			req.applied()
+			req, ok = ideControl.wait(ctx, nil)
+		}
+		if !ok {
+			ideStatus.update(func(status *Status) {
+				status.Phase = PhaseStopped
+				status.Pid = 0
+				status.NextRestart = time.Time{}
+			})
 			return
 		}
+
+		if req.action == actionRestart {
+			// a restart on request gives a crash looping editor a fresh start
+			history = crashHistory{policy: policy}
+		}
+		ideStatus.update(func(status *Status) {
+			status.Restarts++
+			status.NextRestart = time.Time{}
+		})
+		pending = req
+	}
+}
+
+// awaitRestart records that the editor stopped by itself and waits until it should be
+// restarted. It returns the control request received while waiting, if any, and false
+// if ctx has been cancelled. An editor which is crash looping is only restarted on request.
+func awaitRestart(ctx context.Context, exit editorExit, history *crashHistory, ideStatus *StatusState, ideControl *Control) (controlRequest, bool) {
+	reason := exitReason(exit.err)
+	if !exit.started {
+		reason = "failed to start: " + reason
+	}
+	if exit.reason != "" {
+		reason = exit.reason
+	}
+
+	delay, ok := history.record(time.Now())
+	if !ok {
+		log.WithField("reason", reason).
+			WithField("restarts", history.policy.MaxRestarts).
+			WithField("window", history.policy.Window).
+			Error("editor is crash looping, giving up restarting it")
+		ideStatus.update(func(status *Status) {
+			status.Phase = PhaseFailed
+			status.Pid = 0
+			status.Reason = reason
+		})
+		return ideControl.wait(ctx, nil)
+	}
+
+	log.WithField("reason", reason).WithField("delay", delay).Warn("editor stopped, restarting")
+	ideStatus.update(func(status *Status) {
+		status.Phase = PhaseBackoff
+		status.Pid = 0
+		status.Reason = reason
+		status.NextRestart = time.Now().Add(delay)
+	})
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	return ideControl.wait(ctx, timer.C)
+}
+
+// terminateEditor stops the editor process group with SIGTERM. If the editor
+// does not exit within `timeBudgetIDEShutdown`, it is force-killed.
+func terminateEditor(cmd *exec.Cmd, ideStopped chan editorExit) {
+	_ = syscall.Kill(-1*cmd.Process.Pid, syscall.SIGTERM)
+	select {
+	case <-ideStopped:
+	case <-time.After(timeBudgetIDEShutdown):
+		log.Error("editor did not stop in time, sending SIGKILL")
+		_ = syscall.Kill(-1*cmd.Process.Pid, syscall.SIGKILL)
+		<-ideStopped
+	}
+}
+
+// killHungEditor stops the process group of an editor which no longer responds to
+// probes with SIGTERM, and SIGKILL if it is still running after `timeBudgetIDEShutdown`.
+// ctx is cancelled once the editor process exited.
+func killHungEditor(ctx context.Context, cmd *exec.Cmd) {
+	_ = syscall.Kill(-1*cmd.Process.Pid, syscall.SIGTERM)
+	select {
+	case <-ctx.Done():
+	case <-time.After(timeBudgetIDEShutdown):
+		log.Error("editor did not stop in time, sending SIGKILL")
+		_ = syscall.Kill(-1*cmd.Process.Pid, syscall.SIGKILL)
 	}
 }
 
 // gracefulStop tries to stop the editor process cleanly. If the editor
 // does not exit within `timeBudgetIDEShutdown`, it is force-killed.
-func gracefulStop(cmd *exec.Cmd, ideStopped chan struct{}) {
+func gracefulStop(cmd *exec.Cmd, ideStopped chan editorExit) {
 	log.WithField("timeout", timeBudgetIDEShutdown).Info("waiting for editor shutdown")
 
 	select {
@@ -85,7 +239,8 @@ func monitorReadiness(ctx context.Context, ideReady *ReadyState) {
 }
 
 // launchEditor starts the editor as a subprocess, runs readiness probes, and monitors for process exit.
-func launchEditor(cfg *config.Config, cmd *exec.Cmd, ideStopped chan struct{}, ideReady *ReadyState) {
+// prepare is called with the command before it is started, flushOutput once it exited.
+func launchEditor(ctx context.Context, cfg *config.Config, cmd *exec.Cmd, flushOutput func(), ideStopped chan editorExit, ideReady *ReadyState, ideStatus *StatusState, ideLogs *Logs, prepare func(cmd *exec.Cmd) (release func())) {
 	go func() {
 		// Lock thread to ensure Pdeathsig works correctly with SysProcAttr
 		runtime.LockOSThread()
@@ -93,34 +248,72 @@ func launchEditor(cfg *config.Config, cmd *exec.Cmd, ideStopped chan struct{}, i
 
 		log.Info("starting editor process")
 
-		if err := cmd.Start(); err != nil {
-			log.WithError(err).Fatal("Editor failed to start")
+		ideStatus.update(func(status *Status) {
+			status.Phase = PhaseStarting
+			status.Pid = 0
+		})
+		release := prepare(cmd)
+		err := cmd.Start()
+		release()
+		if err != nil {
+			log.WithError(err).Error("Editor failed to start")
+			ideLogs.Mark("editor failed to start: %v", err)
+			ideStopped <- editorExit{err: err}
 			return
 		}
+		ideLogs.Mark("editor started with pid %d", cmd.Process.Pid)
+		ideStatus.update(func(status *Status) {
+			status.Pid = cmd.Process.Pid
+			status.StartedAt = time.Now()
+		})
 
-		// Run readiness probe in background
+		// Run readiness and liveness probes in background, until the process exits
+		probeCtx, cancelProbe := context.WithCancel(ctx)
+		probeDone := make(chan struct{})
+		var hung error
 		go func() {
-			runEditorReadinessProbe(cfg)
+			defer close(probeDone)
+			if !runEditorReadinessProbe(probeCtx, cfg) {
+				return
+			}
 			ideReady.Set(true)
+			ideStatus.update(func(status *Status) { status.Phase = PhaseRunning })
+
+			hung = runEditorLivenessProbe(probeCtx, cfg)
+			if hung != nil {
+				log.WithError(hung).Error("editor is not responding, restarting it")
+				killHungEditor(probeCtx, cmd)
+			}
 		}()
 
 		// Block until the process exits
-		if err := cmd.Wait(); err != nil && err.Error() != "signal: terminated" {
-			log.WithError(err).Warn("Editor stopped unexpectedly")
-			if !ideReady.Get() {
-				log.WithError(err).Fatal("Editor failed before becoming ready")
-				return
-			}
+		err = cmd.Wait()
+		if errors.Is(err, exec.ErrWaitDelay) {
+			err = nil
+		}
+		flushOutput()
+		// the probe must not mark the editor ready once it has stopped
+		cancelProbe()
+		<-probeDone
+		if err != nil && err.Error() != "signal: terminated" {
+			log.WithError(err).WithField("ready", ideReady.Get()).Warn("Editor stopped unexpectedly")
 		}
 
 		// Reset readiness and signal stop
 		ideReady.Set(false)
-		close(ideStopped)
+		exit := editorExit{err: err, started: true}
+		if hung != nil {
+			exit.reason = fmt.Sprintf("liveness probe failed: %v", hung)
+		}
+		ideLogs.Mark("editor %s", defaultIfEmpty(exit.reason, exitReason(err)))
+		ideStopped <- exit
 	}()
 }
 
-// prepareEditorLaunch configures the exec.Cmd used to start the editor.
-func prepareEditorLaunch(cfg *config.Config) *exec.Cmd {
+// prepareEditorLaunch configures the exec.Cmd used to start the editor. Its output is
+// captured in ideLogs and forwarded to supervisor's stdout and stderr unless disabled.
+// The returned function captures the remaining output once the process exited.
+func prepareEditorLaunch(cfg *config.Config, ideLogs *Logs) (*exec.Cmd, func()) {
 	log.WithField("args", cfg.Editor.EntrypointArgs).
 		WithField("entrypoint", cfg.Editor.Entrypoint).
 		Info("preparing editor launch")
@@ -131,17 +324,26 @@ func prepareEditorLaunch(cfg *config.Config) *exec.Cmd {
 	// Prepare operating system-specific attributes
 	prepareSysProc(cmd)
 
-	// Default: pass through raw stdout/stderr
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	// Optionally wrap with a rate-limited writer
-	if lrr := cfg.EditorLogRateLimit(); lrr > 0 {
-		limit := int64(lrr)
-		cmd.Stdout = dropwriter.Writer(cmd.Stdout, dropwriter.NewBucket(limit*1024*3, limit*1024))
-		cmd.Stderr = dropwriter.Writer(cmd.Stderr, dropwriter.NewBucket(limit*1024*3, limit*1024))
-		log.WithField("limit_kb_per_sec", limit).Info("rate limiting editor log output")
+	// Capture the output, and pass it through to supervisor's output unless disabled
+	stdout, stderr := ideLogs.Writer(LogStdout), ideLogs.Writer(LogStderr)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	// processes started by the editor may keep its output open after it exited
+	cmd.WaitDelay = outputWaitDelay
+	if !cfg.Editor.Logs.NoForward {
+		var forwardOut, forwardErr io.Writer = os.Stdout, os.Stderr
+		// Optionally rate limit the forwarded output, the captured logs are complete
+		if lrr := cfg.EditorLogRateLimit(); lrr > 0 {
+			limit := int64(lrr)
+			forwardOut = dropwriter.Writer(forwardOut, dropwriter.NewBucket(limit*1024*3, limit*1024))
+			forwardErr = dropwriter.Writer(forwardErr, dropwriter.NewBucket(limit*1024*3, limit*1024))
+			log.WithField("limit_kb_per_sec", limit).Info("rate limiting editor log output")
+		}
+		cmd.Stdout = io.MultiWriter(stdout, forwardOut)
+		cmd.Stderr = io.MultiWriter(stderr, forwardErr)
 	}
 
-	return cmd
+	return cmd, func() {
+		_ = stdout.Close()
+		_ = stderr.Close()
+	}
 }
@@ -1,5 +1,10 @@
 package editor
 
+import (
+	"os/exec"
+	"syscall"
+)
+
 // prepareSysProc configures an *exec.Cmd to run in its own process group.
 func prepareSysProc(cmd *exec.Cmd) {
 	if cmd.SysProcAttr == nil {
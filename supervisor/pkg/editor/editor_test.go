@@ -0,0 +1,222 @@
+package editor
+
+import (
+	"context"
+	"os/exec"
+	"supervisor/pkg/config"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestCrashHistory(t *testing.T) {
+	policy := restartPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Second,
+		MaxRestarts:    4,
+		Window:         time.Minute,
+	}
+	type result struct {
+		Delay time.Duration
+		OK    bool
+	}
+	start := time.Now()
+
+	tests := []struct {
+		Desc         string
+		Stops        []time.Duration
+		Expectations []result
+	}{
+		{
+			Desc:  "exponential backoff up to the crash loop",
+			Stops: []time.Duration{0, time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second},
+			Expectations: []result{
+				{Delay: time.Second, OK: true},
+				{Delay: 2 * time.Second, OK: true},
+				{Delay: 4 * time.Second, OK: true},
+				{Delay: 5 * time.Second, OK: true},
+				{OK: false},
+			},
+		},
+		{
+			Desc:  "stops outside of the window are forgotten",
+			Stops: []time.Duration{0, 30 * time.Second, 2 * time.Minute, 2*time.Minute + time.Second},
+			Expectations: []result{
+				{Delay: time.Second, OK: true},
+				{Delay: 2 * time.Second, OK: true},
+				{Delay: time.Second, OK: true},
+				{Delay: 2 * time.Second, OK: true},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			history := crashHistory{policy: policy}
+			var act []result
+			for _, stop := range test.Stops {
+				delay, ok := history.record(start.Add(stop))
+				act = append(act, result{Delay: delay, OK: ok})
+			}
+			if diff := cmp.Diff(test.Expectations, act); diff != "" {
+				t.Errorf("unexpected restarts (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExitReason(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Script      string
+		Expectation string
+	}{
+		{Desc: "success", Script: "exit 0", Expectation: "exited with code 0"},
+		{Desc: "exit code", Script: "exit 3", Expectation: "exited with code 3"},
+		{Desc: "signal", Script: "kill -9 $$", Expectation: "killed by signal killed"},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			err := exec.Command("/bin/sh", "-c", test.Script).Run()
+			if diff := cmp.Diff(test.Expectation, exitReason(err)); diff != "" {
+				t.Errorf("unexpected reason (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWatchEditorCrashLoop(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cfg := &config.Config{}
+	cfg.Editor.Entrypoint = "/bin/sh"
+	cfg.Editor.EntrypointArgs = []string{"-c", "exit 3"}
+
+	ideReady := NewEditorReadyState()
+	ideStatus := NewEditorStatusState()
+	updates, stopWatching := ideStatus.Watch()
+	defer stopWatching()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchEditor(ctx, cfg, ideReady, ideStatus, NewEditorControl(), newLogs(100, nil), restartPolicy{
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     40 * time.Millisecond,
+			MaxRestarts:    3,
+			Window:         time.Minute,
+		})
+	}()
+
+	var status Status
+	for status.Phase != PhaseFailed {
+		select {
+		case status = <-updates:
+		case <-ctx.Done():
+			t.Fatalf("editor was not marked as failed, last status: %+v", status)
+		}
+	}
+	if diff := cmp.Diff(3, status.Restarts); diff != "" {
+		t.Errorf("unexpected restarts (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("exited with code 3", status.Reason); diff != "" {
+		t.Errorf("unexpected reason (-want +got):\n%s", diff)
+	}
+
+	cancel()
+	<-done
+	if diff := cmp.Diff(PhaseStopped, ideStatus.Get().Phase); diff != "" {
+		t.Errorf("unexpected phase after shutdown (-want +got):\n%s", diff)
+	}
+}
+
+func TestEditorControl(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cfg := &config.Config{}
+	cfg.Editor.Entrypoint = "/bin/sh"
+	cfg.Editor.EntrypointArgs = []string{"-c", "exec sleep 60"}
+
+	ideReady := NewEditorReadyState()
+	ideStatus := NewEditorStatusState()
+	ideControl := NewEditorControl()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchEditor(ctx, cfg, ideReady, ideStatus, ideControl, newLogs(100, nil), restartPolicy{
+			InitialBackoff: time.Second,
+			MaxBackoff:     time.Second,
+			MaxRestarts:    3,
+			Window:         time.Minute,
+		})
+	}()
+
+	waitForPhase := func(phase Phase) Status {
+		t.Helper()
+		updates, stopWatching := ideStatus.Watch()
+		defer stopWatching()
+		for {
+			select {
+			case status := <-updates:
+				if status.Phase == phase {
+					return status
+				}
+			case <-ctx.Done():
+				t.Fatalf("editor did not reach phase %s, status: %+v", phase, ideStatus.Get())
+			}
+		}
+	}
+	first := waitForPhase(PhaseRunning)
+
+	if err := ideControl.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	stopped := ideStatus.Get()
+	if diff := cmp.Diff(Status{Phase: PhaseStopped, Reason: "stop requested"}, stopped, cmpopts.IgnoreFields(Status{}, "Since", "StartedAt")); diff != "" {
+		t.Errorf("unexpected status after stop (-want +got):\n%s", diff)
+	}
+	if ideReady.Get() {
+		t.Error("stopped editor is still ready")
+	}
+
+	if err := ideControl.Restart(ctx); err != nil {
+		t.Fatal(err)
+	}
+	restarted := waitForPhase(PhaseRunning)
+	if restarted.Pid == first.Pid || restarted.Pid == 0 {
+		t.Errorf("expected a new editor process, got pid %d after %d", restarted.Pid, first.Pid)
+	}
+	if diff := cmp.Diff(1, restarted.Restarts); diff != "" {
+		t.Errorf("unexpected restarts (-want +got):\n%s", diff)
+	}
+
+	// supervisor shutdown waits for the editor to stop by itself
+	if err := ideControl.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	<-done
+}
+
+func TestPrepareEditorLaunchRateLimit(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Editor.Entrypoint = "/bin/sh"
+	cfg.Editor.EntrypointArgs = []string{"-c", "i=0; while [ $i -lt 1000 ]; do echo line-$i; i=$((i+1)); done"}
+	cfg.Editor.LogRateLimit = 1
+
+	// the rate limit only applies to the forwarded output, not to the captured logs
+	logs := newLogs(2000, nil)
+	cmd, finish := prepareEditorLaunch(cfg, logs)
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	finish()
+
+	lines, _ := logs.Tail(0, time.Time{})
+	if diff := cmp.Diff(1000, len(lines)); diff != "" {
+		t.Errorf("unexpected number of captured lines (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,299 @@
+package editor
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"common/log"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"supervisor/pkg/config"
+	"supervisor/pkg/variable"
+	"sync"
+	"time"
+)
+
+const (
+	defaultExtensionCacheDir = "/tmp/opencoder/extensions"
+	// timeBudgetExtensionInstall bounds the time installing a single extension may take
+	timeBudgetExtensionInstall = 5 * time.Minute
+)
+
+// ExtensionState is the installation state of an extension.
+type ExtensionState string
+
+const (
+	// ExtensionQueued means the extension waits to be installed.
+	ExtensionQueued ExtensionState = "queued"
+	// ExtensionInstalling means the extension is being downloaded or installed.
+	ExtensionInstalling ExtensionState = "installing"
+	// ExtensionInstalled means the extension has been installed.
+	ExtensionInstalled ExtensionState = "installed"
+	// ExtensionSkipped means the extension was installed already.
+	ExtensionSkipped ExtensionState = "skipped"
+	// ExtensionFailed means the extension could not be installed.
+	ExtensionFailed ExtensionState = "failed"
+)
+
+// ExtensionStatus describes the installation of an extension of the runtime config.
+type ExtensionStatus struct {
+	// Ref is the extension as listed in the runtime config
+	Ref string
+	// ID is the extension ID, known once a .vsix file has been read
+	ID    string
+	State ExtensionState
+	// Error explains why the installation failed
+	Error string
+}
+
+// InstallExtensions installs the VS Code extensions of the runtime config which are not
+// installed yet, once the editor is ready for the first time. The progress is reported
+// in the editor status.
+func InstallExtensions(ctx context.Context, cfg *config.Config, ideReady *ReadyState, ideStatus *StatusState) {
+	if len(cfg.Runtime.Vscode.Extensions) == 0 {
+		return
+	}
+	select {
+	case <-ideReady.Wait():
+	case <-ctx.Done():
+		return
+	}
+	installExtensions(ctx, cfg, ideStatus)
+}
+
+// installMu serializes installations, e.g. when extensions are added while installing the previous ones.
+var installMu sync.Mutex
+
+func installExtensions(ctx context.Context, cfg *config.Config, ideStatus *StatusState) {
+	installMu.Lock()
+	defer installMu.Unlock()
+
+	refs := cfg.Runtime.Vscode.Extensions
+	setStatus := func(i int, change func(ext *ExtensionStatus)) {
+		ideStatus.update(func(status *Status) {
+			// the slice is shared with copies of the status handed out before
+			exts := append([]ExtensionStatus(nil), status.Extensions...)
+			change(&exts[i])
+			status.Extensions = exts
+		})
+	}
+	ideStatus.update(func(status *Status) {
+		status.Extensions = make([]ExtensionStatus, len(refs))
+		for i, ref := range refs {
+			status.Extensions[i] = ExtensionStatus{Ref: ref, State: ExtensionQueued}
+		}
+	})
+
+	ec := cfg.Editor.Extensions
+	if len(ec.InstallCommand) == 0 {
+		log.Warn("the editor has no extension install command, cannot install extensions")
+		for i := range refs {
+			setStatus(i, func(ext *ExtensionStatus) {
+				ext.State = ExtensionFailed
+				ext.Error = "the editor does not support installing extensions"
+			})
+		}
+		return
+	}
+
+	installed, err := listInstalledExtensions(ctx, cfg)
+	if err != nil {
+		log.WithError(err).Warn("cannot list installed extensions, installing all of them")
+	}
+
+	for i, ref := range refs {
+		if ctx.Err() != nil {
+			return
+		}
+		setStatus(i, func(ext *ExtensionStatus) { ext.State = ExtensionInstalling })
+
+		id, err := installExtension(ctx, cfg, ref, installed)
+		switch {
+		case errors.Is(err, errExtensionInstalled):
+			log.WithField("extension", ref).Debug("extension is installed already")
+			setStatus(i, func(ext *ExtensionStatus) {
+				ext.ID = id
+				ext.State = ExtensionSkipped
+			})
+		case err != nil:
+			log.WithError(err).WithField("extension", ref).Warn("cannot install extension")
+			setStatus(i, func(ext *ExtensionStatus) {
+				ext.ID = id
+				ext.State = ExtensionFailed
+				ext.Error = err.Error()
+			})
+		default:
+			log.WithField("extension", ref).WithField("id", id).Info("installed extension")
+			installed[strings.ToLower(id)] = struct{}{}
+			setStatus(i, func(ext *ExtensionStatus) {
+				ext.ID = id
+				ext.State = ExtensionInstalled
+			})
+		}
+	}
+}
+
+var errExtensionInstalled = errors.New("extension is installed already")
+
+// installExtension installs the extension ref unless its ID is in installed and returns its ID.
+// URLs are downloaded to the cache first, local .vsix files are installed from the workspace.
+func installExtension(ctx context.Context, cfg *config.Config, ref string, installed map[string]struct{}) (string, error) {
+	source, id := ref, ref
+	if at := strings.LastIndex(ref, "@"); at > 0 {
+		id = ref[:at]
+	}
+
+	isURL := strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://")
+	if isURL || strings.HasSuffix(strings.ToLower(ref), ".vsix") {
+		if isURL {
+			fn, err := downloadExtension(ctx, defaultIfEmpty(cfg.Editor.Extensions.CacheDir, defaultExtensionCacheDir), ref)
+			if err != nil {
+				return "", err
+			}
+			source = fn
+		} else if !filepath.IsAbs(source) {
+			source = filepath.Join(cfg.WorkspaceLocation, source)
+		}
+
+		var err error
+		id, err = vsixExtensionID(source)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if _, exists := installed[strings.ToLower(id)]; exists {
+		return id, errExtensionInstalled
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeBudgetExtensionInstall)
+	defer cancel()
+	command := cfg.Editor.Extensions.InstallCommand
+	args := append(append([]string(nil), command[1:]...), source)
+	cmd := exec.CommandContext(ctx, command[0], args...)
+	variable.AddDefault(cmd, cfg)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if line := lastLine(out); line != "" {
+			return id, fmt.Errorf("%s: %s", exitReason(err), line)
+		}
+		return id, errors.New(exitReason(err))
+	}
+	return id, nil
+}
+
+// listInstalledExtensions returns the lower-cased IDs of the installed extensions.
+// It returns an empty set if the editor has no list command.
+func listInstalledExtensions(ctx context.Context, cfg *config.Config) (map[string]struct{}, error) {
+	res := make(map[string]struct{})
+	command := cfg.Editor.Extensions.ListCommand
+	if len(command) == 0 {
+		return res, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	variable.AddDefault(cmd, cfg)
+	out, err := cmd.Output()
+	if err != nil {
+		return res, fmt.Errorf("%s: %w", strings.Join(command, " "), err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		// code --list-extensions --show-versions prints publisher.name@version
+		id, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), "@")
+		if id != "" {
+			res[strings.ToLower(id)] = struct{}{}
+		}
+	}
+	return res, nil
+}
+
+// downloadExtension downloads the .vsix file at url into the cache directory, unless it is
+// cached already, and returns its path.
+func downloadExtension(ctx context.Context, cacheDir, url string) (string, error) {
+	hash := sha256.Sum256([]byte(url))
+	fn := filepath.Join(cacheDir, hex.EncodeToString(hash[:8])+".vsix")
+	if _, err := os.Stat(fn); err == nil {
+		return fn, nil
+	}
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return "", fmt.Errorf("cannot create extension cache: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("cannot download extension: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cannot download extension: unexpected status code %d", resp.StatusCode)
+	}
+
+	// download into a temporary file s.t. an interrupted download is not cached
+	f, err := os.CreateTemp(cacheDir, ".download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("cannot download extension: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(f.Name(), fn); err != nil {
+		return "", err
+	}
+	return fn, nil
+}
+
+// vsixExtensionID reads the extension ID publisher.name from the manifest of a .vsix file.
+func vsixExtensionID(fn string) (string, error) {
+	r, err := zip.OpenReader(fn)
+	if err != nil {
+		return "", fmt.Errorf("cannot read extension package: %w", err)
+	}
+	defer r.Close()
+
+	f, err := r.Open("extension/package.json")
+	if err != nil {
+		return "", fmt.Errorf("cannot read extension manifest: %w", err)
+	}
+	defer f.Close()
+
+	var manifest struct {
+		Publisher string `json:"publisher"`
+		Name      string `json:"name"`
+	}
+	if err := json.NewDecoder(f).Decode(&manifest); err != nil {
+		return "", fmt.Errorf("cannot parse extension manifest: %w", err)
+	}
+	if manifest.Publisher == "" || manifest.Name == "" {
+		return "", fmt.Errorf("extension manifest without publisher or name")
+	}
+	return manifest.Publisher + "." + manifest.Name, nil
+}
+
+// lastLine returns the last non-empty line of out.
+func lastLine(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
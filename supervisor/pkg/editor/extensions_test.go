@@ -0,0 +1,114 @@
+package editor
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"supervisor/pkg/config"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInstallExtensions(t *testing.T) {
+	workspace := t.TempDir()
+	writeVsix(t, filepath.Join(workspace, "local.vsix"), "acme", "local")
+
+	var downloads atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/remote.vsix" {
+			http.NotFound(w, r)
+			return
+		}
+		downloads.Add(1)
+		fn := filepath.Join(t.TempDir(), "remote.vsix")
+		writeVsix(t, fn, "acme", "remote")
+		http.ServeFile(w, r, fn)
+	}))
+	defer srv.Close()
+
+	// the fake editor records installations in a file and fails for "acme.broken"
+	dir := t.TempDir()
+	calls, list := filepath.Join(dir, "calls"), filepath.Join(dir, "list")
+	if err := os.WriteFile(list, []byte("Acme.Present@1.0.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	cfg.WorkspaceLocation = workspace
+	cfg.Editor.Extensions.InstallCommand = []string{"/bin/sh", "-c", `case "$0" in acme.broken) echo "extension not found" >&2; exit 1;; esac; echo "$0" >> ` + calls}
+	cfg.Editor.Extensions.ListCommand = []string{"cat", list}
+	cfg.Editor.Extensions.CacheDir = t.TempDir()
+	cfg.Runtime.Vscode.Extensions = []string{
+		"acme.present",
+		"acme.new@2.0.0",
+		"local.vsix",
+		srv.URL + "/remote.vsix",
+		srv.URL + "/missing.vsix",
+		"acme.broken",
+		"acme.new",
+	}
+
+	ideStatus := NewEditorStatusState()
+	installExtensions(context.Background(), cfg, ideStatus)
+	expectation := []ExtensionStatus{
+		{Ref: "acme.present", ID: "acme.present", State: ExtensionSkipped},
+		{Ref: "acme.new@2.0.0", ID: "acme.new", State: ExtensionInstalled},
+		{Ref: "local.vsix", ID: "acme.local", State: ExtensionInstalled},
+		{Ref: srv.URL + "/remote.vsix", ID: "acme.remote", State: ExtensionInstalled},
+		{Ref: srv.URL + "/missing.vsix", State: ExtensionFailed, Error: "cannot download extension: unexpected status code 404"},
+		{Ref: "acme.broken", ID: "acme.broken", State: ExtensionFailed, Error: "exited with code 1: extension not found"},
+		{Ref: "acme.new", ID: "acme.new", State: ExtensionSkipped},
+	}
+	if diff := cmp.Diff(expectation, ideStatus.Get().Extensions); diff != "" {
+		t.Errorf("unexpected extensions (-want +got):\n%s", diff)
+	}
+
+	content, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	act := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(act) == 3 {
+		// downloaded extensions are installed from the cache
+		act[2] = filepath.Dir(act[2])
+	}
+	if diff := cmp.Diff([]string{"acme.new@2.0.0", filepath.Join(workspace, "local.vsix"), cfg.Editor.Extensions.CacheDir}, act); diff != "" {
+		t.Errorf("unexpected installations (-want +got):\n%s", diff)
+	}
+
+	// a second run only retries the failed extensions, and doesn't download cached extensions again
+	if err := os.WriteFile(list, []byte("acme.present\nacme.new\nacme.local\nacme.remote\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Remove(calls)
+	installExtensions(context.Background(), cfg, NewEditorStatusState())
+	if _, err := os.Stat(calls); !os.IsNotExist(err) {
+		t.Errorf("expected no installation, got %v", err)
+	}
+	if downloads.Load() != 1 {
+		t.Errorf("expected a single download, got %d", downloads.Load())
+	}
+}
+
+func writeVsix(t *testing.T, fn, publisher, name string) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("extension/package.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.Write([]byte(`{"publisher":"` + publisher + `","name":"` + name + `","version":"1.0.0"}`))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fn, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,281 @@
+package editor
+
+import (
+	"bytes"
+	"common/log"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"supervisor/pkg/config"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLogFile        = "/tmp/opencoder/logs/editor.log"
+	defaultLogMaxSize     = 10
+	defaultLogMaxBackups  = 3
+	defaultLogBufferLines = 5000
+
+	// maxLogLineLength is the length after which a line without newline is split
+	maxLogLineLength = 64 << 10
+)
+
+// LogStream is the output stream a log line was written to.
+type LogStream string
+
+const (
+	LogStdout LogStream = "stdout"
+	LogStderr LogStream = "stderr"
+)
+
+// LogLine is a line of output of the editor process.
+type LogLine struct {
+	// Seq numbers all lines in the order they were written, starting at 1
+	Seq    uint64
+	Time   time.Time
+	Stream LogStream
+	Text   string
+}
+
+// Logs captures the output of the editor process into a log file and keeps the
+// most recent lines in memory.
+type Logs struct {
+	mu    sync.Mutex
+	lines []LogLine
+	// next is the index in lines the next line is written to
+	next int
+	seq  uint64
+	// changed is closed and replaced whenever a line is added
+	changed chan struct{}
+	file    io.WriteCloser
+}
+
+// NewEditorLogs creates the log capture configured for the editor. If the log file
+// cannot be opened, output is only kept in memory.
+func NewEditorLogs(cfg *config.Config) *Logs {
+	lc := cfg.Editor.Logs
+	file, err := openRotatingFile(defaultIfEmpty(lc.File, defaultLogFile), int64(defaultIfZero(lc.MaxSize, defaultLogMaxSize))<<20, defaultIfZero(lc.MaxBackups, defaultLogMaxBackups))
+	if err != nil {
+		log.WithError(err).Warn("cannot open editor log file, keeping editor logs in memory only")
+		return newLogs(max(defaultIfZero(lc.BufferLines, defaultLogBufferLines), 1), nil)
+	}
+	return newLogs(max(defaultIfZero(lc.BufferLines, defaultLogBufferLines), 1), file)
+}
+
+func newLogs(size int, file io.WriteCloser) *Logs {
+	return &Logs{
+		lines:   make([]LogLine, 0, size),
+		changed: make(chan struct{}),
+		file:    file,
+	}
+}
+
+// Writer returns a writer capturing the output written to stream. Lines are
+// captured once they are complete, call Close to capture the last partial line.
+func (l *Logs) Writer(stream LogStream) io.WriteCloser {
+	return &logWriter{logs: l, stream: stream}
+}
+
+// Mark adds a line written by supervisor, e.g. when the editor is started.
+func (l *Logs) Mark(format string, args ...interface{}) {
+	l.add(LogStderr, []byte("--- "+fmt.Sprintf(format, args...)+" ---"))
+}
+
+// Close closes the log file.
+func (l *Logs) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+func (l *Logs) add(stream LogStream, text []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.seq++
+	line := LogLine{Seq: l.seq, Time: time.Now(), Stream: stream, Text: string(text)}
+	if len(l.lines) < cap(l.lines) {
+		l.lines = append(l.lines, line)
+	} else {
+		l.lines[l.next] = line
+	}
+	l.next = (l.next + 1) % cap(l.lines)
+
+	if l.file != nil {
+		buf := make([]byte, 0, len(text)+1)
+		if _, err := l.file.Write(append(append(buf, text...), '\n')); err != nil {
+			log.WithError(err).Debug("cannot write editor log file")
+		}
+	}
+
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// Read returns the buffered lines after seq, oldest first, and a channel which is
+// closed once more lines are added.
+func (l *Logs) Read(seq uint64) ([]LogLine, <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var res []LogLine
+	for i := range l.lines {
+		line := l.lines[(l.next+i)%len(l.lines)]
+		if line.Seq > seq {
+			res = append(res, line)
+		}
+	}
+	return res, l.changed
+}
+
+// Tail returns the last n buffered lines written at or after since, all of them if n is 0,
+// and the sequence number of the latest line to follow the logs from.
+func (l *Logs) Tail(n int, since time.Time) ([]LogLine, uint64) {
+	lines, _ := l.Read(0)
+	var seq uint64
+	if len(lines) > 0 {
+		seq = lines[len(lines)-1].Seq
+	}
+	start := len(lines)
+	for start > 0 && !lines[start-1].Time.Before(since) {
+		start--
+	}
+	lines = lines[start:]
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, seq
+}
+
+// Follow calls fn for every line added after seq until ctx is cancelled or fn returns an error.
+// Lines which have been evicted from the buffer before fn could be called for them are skipped.
+func (l *Logs) Follow(ctx context.Context, seq uint64, fn func(LogLine) error) error {
+	for {
+		lines, changed := l.Read(seq)
+		for _, line := range lines {
+			if err := fn(line); err != nil {
+				return err
+			}
+			seq = line.Seq
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// logWriter splits the output written to a stream into lines.
+type logWriter struct {
+	logs    *Logs
+	stream  LogStream
+	mu      sync.Mutex
+	partial []byte
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.logs.add(w.stream, bytes.TrimSuffix(w.partial[:i], []byte{'\r'}))
+		w.partial = w.partial[i+1:]
+	}
+	for len(w.partial) >= maxLogLineLength {
+		w.logs.add(w.stream, w.partial[:maxLogLineLength])
+		w.partial = w.partial[maxLogLineLength:]
+	}
+	// don't keep a large underlying array around for the remainder
+	w.partial = append([]byte(nil), w.partial...)
+	return len(p), nil
+}
+
+// Close captures the last line if it did not end with a newline.
+func (w *logWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.partial) > 0 {
+		w.logs.add(w.stream, w.partial)
+		w.partial = nil
+	}
+	return nil
+}
+
+// rotatingFile is a file which is rotated once it grows beyond maxSize.
+// Rotated files are suffixed with .1 to .<maxBackups>, .1 being the most recent one.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	f          *os.File
+	size       int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create log directory: %w", err)
+	}
+	res := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := res.open(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	r.f, r.size = f, stat.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	if r.maxBackups > 0 {
+		for i := r.maxBackups - 1; i > 0; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		if err := os.Rename(r.path, r.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) Close() error {
+	return r.f.Close()
+}
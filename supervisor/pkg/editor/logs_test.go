@@ -0,0 +1,139 @@
+package editor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLogs(t *testing.T) {
+	type line struct {
+		Stream LogStream
+		Text   string
+	}
+	tests := []struct {
+		Desc        string
+		Size        int
+		Writes      []line
+		Tail        int
+		Expectation []line
+	}{
+		{
+			Desc:        "split lines",
+			Size:        10,
+			Writes:      []line{{LogStdout, "first\r\nsec"}, {LogStdout, "ond\nthird"}},
+			Expectation: []line{{LogStdout, "first"}, {LogStdout, "second"}, {LogStdout, "third"}},
+		},
+		{
+			Desc:        "streams",
+			Size:        10,
+			Writes:      []line{{LogStdout, "out"}, {LogStderr, "err\n"}, {LogStdout, "put\n"}},
+			Expectation: []line{{LogStderr, "err"}, {LogStdout, "output"}},
+		},
+		{
+			Desc:        "evict oldest lines",
+			Size:        2,
+			Writes:      []line{{LogStdout, "1\n2\n3\n4\n"}},
+			Expectation: []line{{LogStdout, "3"}, {LogStdout, "4"}},
+		},
+		{
+			Desc:        "tail",
+			Size:        10,
+			Writes:      []line{{LogStdout, "1\n2\n3\n4\n"}},
+			Tail:        3,
+			Expectation: []line{{LogStdout, "2"}, {LogStdout, "3"}, {LogStdout, "4"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			logs := newLogs(test.Size, nil)
+			writers := map[LogStream]io.WriteCloser{
+				LogStdout: logs.Writer(LogStdout),
+				LogStderr: logs.Writer(LogStderr),
+			}
+			for _, w := range test.Writes {
+				_, _ = writers[w.Stream].Write([]byte(w.Text))
+			}
+			_ = writers[LogStdout].Close()
+			_ = writers[LogStderr].Close()
+
+			lines, _ := logs.Tail(test.Tail, time.Time{})
+			var act []line
+			for _, l := range lines {
+				act = append(act, line{l.Stream, l.Text})
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected lines (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLogsFollow(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	logs := newLogs(10, nil)
+	w := logs.Writer(LogStdout)
+	_, _ = w.Write([]byte("before\n"))
+	_, seq := logs.Tail(0, time.Time{})
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			_, _ = fmt.Fprintf(w, "line %d\n", i)
+		}
+	}()
+
+	errDone := errors.New("done")
+	var act []string
+	err := logs.Follow(ctx, seq, func(line LogLine) error {
+		act = append(act, line.Text)
+		if len(act) == 3 {
+			return errDone
+		}
+		return nil
+	})
+	if err != errDone {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"line 0", "line 1", "line 2"}, act); diff != "" {
+		t.Errorf("unexpected lines (-want +got):\n%s", diff)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "logs", "editor.log")
+	f, err := openRotatingFile(fn, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = f.Close()
+
+	act := make(map[string]string)
+	for _, name := range []string{"editor.log", "editor.log.1", "editor.log.2", "editor.log.3"} {
+		content, err := os.ReadFile(filepath.Join(filepath.Dir(fn), name))
+		if err == nil {
+			act[name] = string(content)
+		}
+	}
+	expectation := map[string]string{
+		"editor.log":   "fourth\n",
+		"editor.log.1": "third\n",
+		"editor.log.2": "second\n",
+	}
+	if diff := cmp.Diff(expectation, act); diff != "" {
+		t.Errorf("unexpected files (-want +got):\n%s", diff)
+	}
+}
@@ -2,59 +2,242 @@ package editor
 
 import (
 	"common/log"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"os/exec"
+	"strconv"
 	"strings"
 	"supervisor/pkg/config"
+	"supervisor/pkg/variable"
 	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
-// runEditorReadinessProbe ensures the configured editor is available before marking it ready.
-func runEditorReadinessProbe(cfg *config.Config) {
-	defer log.WithField("ide", cfg.Editor.Name).Info("editor is ready")
+const (
+	defaultProbePeriod         = 250 * time.Millisecond
+	defaultLivenessPeriod      = 10 * time.Second
+	defaultProbeTimeout        = 1 * time.Second
+	defaultFailureThreshold    = 3
+	maxProbeResponseBodyLength = 1 << 20
+)
 
-	switch cfg.Editor.ReadinessProbe.Type {
-	case config.ReadinessProcessProbe:
-		// No readiness check needed
-		return
+// probe checks the editor once. It returns an error if the editor is not healthy.
+type probe func(ctx context.Context) error
 
+// newProbe returns the probe configured for the editor, or nil for process probes.
+func newProbe(cfg *config.Config) probe {
+	switch cfg.Editor.ReadinessProbe.Type {
 	case config.ReadinessHTTPProbe:
-		url := buildProbeURL(cfg)
-		for range time.Tick(250 * time.Millisecond) {
-			body, err := editorStatusRequest(url)
-			if err != nil {
-				log.WithError(err).Debug("editor readiness probe failed")
-				continue
-			}
-			log.WithField("body", string(body)).Debug("editor readiness response received")
+		return httpProbe(cfg)
+	case config.ReadinessTCPProbe:
+		return tcpProbe(cfg)
+	case config.ReadinessExecProbe:
+		return execProbe(cfg)
+	case config.ReadinessGRPCHealthProbe:
+		return grpcHealthProbe(cfg)
+	default:
+		return nil
+	}
+}
+
+// runEditorReadinessProbe ensures the configured editor is available before marking it ready.
+// It returns false if ctx is cancelled before the editor became ready.
+func runEditorReadinessProbe(ctx context.Context, cfg *config.Config) bool {
+	p := newProbe(cfg)
+	if p == nil {
+		// No readiness check needed for process probes
+		log.WithField("ide", cfg.Editor.Name).Info("editor is ready")
+		return true
+	}
+
+	rp := cfg.Editor.ReadinessProbe
+	if !sleep(ctx, time.Duration(rp.InitialDelay)) {
+		return false
+	}
+	period := defaultIfZeroDuration(time.Duration(rp.Period), defaultProbePeriod)
+	for {
+		err := runProbe(ctx, cfg, p)
+		if err == nil {
 			break
 		}
+		log.WithError(err).Debug("editor readiness probe failed")
+		if !sleep(ctx, period) {
+			return false
+		}
+	}
+
+	log.WithField("ide", cfg.Editor.Name).Info("editor is ready")
+	return true
+}
+
+// runEditorLivenessProbe keeps probing a ready editor. It returns an error once
+// FailureThreshold probes in a row failed, and nil if ctx is cancelled or liveness
+// probing is not enabled.
+func runEditorLivenessProbe(ctx context.Context, cfg *config.Config) error {
+	rp := cfg.Editor.ReadinessProbe
+	p := newProbe(cfg)
+	if p == nil || !rp.Liveness {
+		return nil
+	}
+
+	threshold := defaultIfZero(rp.FailureThreshold, defaultFailureThreshold)
+	period := defaultIfZeroDuration(time.Duration(rp.LivenessPeriod), defaultLivenessPeriod)
+	failures := 0
+	for sleep(ctx, period) {
+		err := runProbe(ctx, cfg, p)
+		if err == nil {
+			failures = 0
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		failures++
+		log.WithError(err).WithField("failures", failures).Warn("editor liveness probe failed")
+		if failures >= threshold {
+			return fmt.Errorf("%d liveness probes failed in a row: %w", failures, err)
+		}
+	}
+	return nil
+}
+
+// runProbe runs p once within the configured probe timeout.
+func runProbe(ctx context.Context, cfg *config.Config, p probe) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultIfZeroDuration(time.Duration(cfg.Editor.ReadinessProbe.Timeout), defaultProbeTimeout))
+	defer cancel()
+	return p(ctx)
+}
+
+// sleep waits for d and returns false if ctx was cancelled in the meantime.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// httpProbe requests the editor and checks the response status and body.
+func httpProbe(cfg *config.Config) probe {
+	url := buildProbeURL(cfg)
+	hp := cfg.Editor.ReadinessProbe.HTTPProbe
+	return func(ctx context.Context) error {
+		body, err := editorStatusRequest(ctx, url, hp.Status)
+		if err != nil {
+			return err
+		}
+		log.WithField("body", string(body)).Debug("editor probe response received")
+		if hp.Body != nil && !hp.Body.Match(body) {
+			return fmt.Errorf("response body does not match %q", hp.Body.String())
+		}
+		return nil
+	}
+}
+
+// tcpProbe opens a TCP connection to the editor.
+func tcpProbe(cfg *config.Config) probe {
+	tp := cfg.Editor.ReadinessProbe.TCPProbe
+	addr := probeAddress(tp.Host, defaultIfZero(tp.Port, cfg.Editor.GetPort()))
+	return func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// execProbe runs a command in the editor environment, which must exit with code 0.
+func execProbe(cfg *config.Config) probe {
+	command := cfg.Editor.ReadinessProbe.ExecProbe.Command
+	return func(ctx context.Context) error {
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		variable.AddDefault(cmd, cfg)
+		out, err := cmd.CombinedOutput()
+		if err == nil {
+			return nil
+		}
+		if out := strings.TrimSpace(string(out)); out != "" {
+			return fmt.Errorf("%s: %s", exitReason(err), out)
+		}
+		return errors.New(exitReason(err))
 	}
 }
 
+// grpcHealthProbe checks the editor with the standard gRPC health checking protocol.
+func grpcHealthProbe(cfg *config.Config) probe {
+	gp := cfg.Editor.ReadinessProbe.GRPCHealthProbe
+	addr := probeAddress(gp.Host, defaultIfZero(gp.Port, cfg.Editor.GetPort()))
+	return func(ctx context.Context) error {
+		conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: gp.Service})
+		if err != nil {
+			return err
+		}
+		if resp.Status != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("health status %s", resp.Status)
+		}
+		return nil
+	}
+}
+
+// probeAddress returns the host:port address to probe, localhost if host is empty.
+func probeAddress(host string, port int) string {
+	return net.JoinHostPort(defaultIfEmpty(host, "localhost"), strconv.Itoa(port))
+}
+
 // buildProbeURL constructs the full readiness probe URL based on the editor's configuration.
 func buildProbeURL(cfg *config.Config) string {
 	schema := defaultIfEmpty(cfg.Editor.ReadinessProbe.HTTPProbe.Schema, "http")
 	host := defaultIfEmpty(cfg.Editor.ReadinessProbe.HTTPProbe.Host, "localhost")
-	port := defaultIfZero(cfg.Editor.ReadinessProbe.HTTPProbe.Port, 3000)
+	port := defaultIfZero(cfg.Editor.ReadinessProbe.HTTPProbe.Port, cfg.Editor.GetPort())
 	path := strings.TrimPrefix(cfg.Editor.ReadinessProbe.HTTPProbe.Path, "/")
 
 	return fmt.Sprintf("%s://%s:%d/%s", schema, host, port, path)
 }
 
 // editorStatusRequest sends an HTTP GET request to the given readiness probe URL.
-func editorStatusRequest(url string) ([]byte, error) {
-	client := http.Client{Timeout: 1 * time.Second}
-	resp, err := client.Get(url)
+// The response status must be within one of the accepted ranges, 200 if there are none.
+func editorStatusRequest(ctx context.Context, url string, accepted []config.StatusRange) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("non-200 status code: %v", resp.StatusCode)
+	if len(accepted) == 0 {
+		accepted = []config.StatusRange{{Min: 200, Max: 200}}
+	}
+	ok := false
+	for _, r := range accepted {
+		ok = ok || r.Contains(resp.StatusCode)
+	}
+	if !ok {
+		return nil, fmt.Errorf("unexpected status code: %v", resp.StatusCode)
 	}
 
-	return io.ReadAll(resp.Body)
+	return io.ReadAll(io.LimitReader(resp.Body, maxProbeResponseBodyLength))
 }
@@ -0,0 +1,169 @@
+package editor
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"supervisor/pkg/config"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestProbes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		case "/starting":
+			_, _ = w.Write([]byte(`{"status":"starting"}`))
+		case "/redirect":
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	srvURL, _ := url.Parse(srv.URL)
+	srvPort, _ := strconv.Atoi(srvURL.Port())
+
+	closed, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	_ = closed.Close()
+
+	grpcLis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcSrv := grpc.NewServer()
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("editor", healthpb.HealthCheckResponse_SERVING)
+	healthSrv.SetServingStatus("stopping", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(grpcSrv, healthSrv)
+	go func() { _ = grpcSrv.Serve(grpcLis) }()
+	defer grpcSrv.Stop()
+	grpcPort := grpcLis.Addr().(*net.TCPAddr).Port
+
+	tests := []struct {
+		Desc        string
+		Probe       string
+		Port        int
+		Expectation bool
+	}{
+		{Desc: "http ok", Probe: `{"type":"http","http":{"path":"/ok"}}`, Port: srvPort, Expectation: true},
+		{Desc: "http default status", Probe: `{"type":"http","http":{"path":"/redirect"}}`, Port: srvPort},
+		{Desc: "http redirect accepted", Probe: `{"type":"http","http":{"path":"/redirect","status":["200-399"]}}`, Port: srvPort, Expectation: true},
+		{Desc: "http unavailable", Probe: `{"type":"http","http":{"path":"/unavailable"}}`, Port: srvPort},
+		{Desc: "http status range", Probe: `{"type":"http","http":{"path":"/unavailable","status":["200-299",503]}}`, Port: srvPort, Expectation: true},
+		{Desc: "http status not in range", Probe: `{"type":"http","http":{"path":"/redirect","status":["200-299"]}}`, Port: srvPort},
+		{Desc: "http body match", Probe: `{"type":"http","http":{"path":"/ok","body":"\"status\":\\s*\"ok\""}}`, Port: srvPort, Expectation: true},
+		{Desc: "http body mismatch", Probe: `{"type":"http","http":{"path":"/starting","body":"\"status\":\\s*\"ok\""}}`, Port: srvPort},
+		{Desc: "tcp open", Probe: `{"type":"tcp"}`, Port: srvPort, Expectation: true},
+		{Desc: "tcp closed", Probe: `{"type":"tcp"}`, Port: closedPort},
+		{Desc: "exec success", Probe: `{"type":"exec","exec":{"command":["/bin/sh","-c","exit 0"]}}`, Expectation: true},
+		{Desc: "exec failure", Probe: `{"type":"exec","exec":{"command":["/bin/sh","-c","exit 1"]}}`},
+		{Desc: "exec timeout", Probe: `{"type":"exec","timeout":"50ms","exec":{"command":["sleep","10"]}}`},
+		{Desc: "grpc serving", Probe: `{"type":"grpc-health","grpcHealth":{"service":"editor"}}`, Port: grpcPort, Expectation: true},
+		{Desc: "grpc server", Probe: `{"type":"grpc-health"}`, Port: grpcPort, Expectation: true},
+		{Desc: "grpc not serving", Probe: `{"type":"grpc-health","grpcHealth":{"service":"stopping"}}`, Port: grpcPort},
+		{Desc: "grpc unknown service", Probe: `{"type":"grpc-health","grpcHealth":{"service":"unknown"}}`, Port: grpcPort},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			cfg := &config.Config{}
+			if err := json.Unmarshal([]byte(test.Probe), &cfg.Editor.ReadinessProbe); err != nil {
+				t.Fatal(err)
+			}
+			cfg.Editor.ReadinessProbe.HTTPProbe.Port = test.Port
+			cfg.Editor.ReadinessProbe.TCPProbe.Port = test.Port
+			cfg.Editor.ReadinessProbe.GRPCHealthProbe.Port = test.Port
+
+			err := runProbe(context.Background(), cfg, newProbe(cfg))
+			if ok := err == nil; ok != test.Expectation {
+				t.Errorf("expected probe success to be %v, got error: %v", test.Expectation, err)
+			}
+		})
+	}
+}
+
+func TestLivenessProbe(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	healthy := t.TempDir() + "/healthy"
+	cfg := &config.Config{}
+	cfg.Editor.Entrypoint = "/bin/sh"
+	cfg.Editor.EntrypointArgs = []string{"-c", "touch " + healthy + " && exec sleep 60"}
+	if err := json.Unmarshal([]byte(`{
+		"type": "exec",
+		"period": "10ms",
+		"liveness": true,
+		"livenessPeriod": "10ms",
+		"failureThreshold": 2,
+		"exec": {"command": ["test", "-f", "`+healthy+`"]}
+	}`), &cfg.Editor.ReadinessProbe); err != nil {
+		t.Fatal(err)
+	}
+
+	ideReady := NewEditorReadyState()
+	ideStatus := NewEditorStatusState()
+	updates, stopWatching := ideStatus.Watch()
+	defer stopWatching()
+	ideControl := NewEditorControl()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchEditor(ctx, cfg, ideReady, ideStatus, ideControl, newLogs(100, nil), restartPolicy{
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     10 * time.Millisecond,
+			MaxRestarts:    3,
+			Window:         time.Minute,
+		})
+	}()
+
+	var status Status
+	for status.Phase != PhaseRunning {
+		select {
+		case status = <-updates:
+		case <-ctx.Done():
+			t.Fatalf("editor did not become ready, last status: %+v", status)
+		}
+	}
+	hung := status.Pid
+
+	// the editor is hung as soon as the probe fails
+	if err := os.Remove(healthy); err != nil {
+		t.Fatal(err)
+	}
+	for status.Phase != PhaseRunning || status.Pid == hung {
+		select {
+		case status = <-updates:
+		case <-ctx.Done():
+			t.Fatalf("hung editor was not restarted, last status: %+v", status)
+		}
+	}
+	if diff := cmp.Diff(1, status.Restarts); diff != "" {
+		t.Errorf("unexpected restarts (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("liveness probe failed: 2 liveness probes failed in a row: exited with code 1", status.Reason); diff != "" {
+		t.Errorf("unexpected reason (-want +got):\n%s", diff)
+	}
+
+	if err := ideControl.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,172 @@
+package editor
+
+import (
+	"context"
+	"errors"
+	"supervisor/api"
+	"supervisor/pkg/config"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// controlTimeout bounds the time Restart and Stop wait for the editor, which may
+// take up to timeBudgetIDEShutdown to stop.
+const controlTimeout = timeBudgetIDEShutdown + 5*time.Second
+
+// EditorService implements the editor service API on top of StartAndWatchEditor.
+type EditorService struct {
+	Cfg     *config.Config
+	Ready   *ReadyState
+	Status  *StatusState
+	Control *Control
+	Output  *Logs
+
+	api.UnimplementedEditorServiceServer
+}
+
+// RegisterGRPC registers the gRPC editor service.
+func (es *EditorService) RegisterGRPC(srv *grpc.Server) {
+	api.RegisterEditorServiceServer(srv, es)
+}
+
+// GetStatus returns the status of the editor process.
+func (es *EditorService) GetStatus(ctx context.Context, req *api.GetEditorStatusRequest) (*api.EditorStatus, error) {
+	return es.toAPI(es.Status.Get()), nil
+}
+
+// WatchStatus streams the status of the editor on every change.
+func (es *EditorService) WatchStatus(req *api.WatchEditorStatusRequest, srv api.EditorService_WatchStatusServer) error {
+	updates, cancel := es.Status.Watch()
+	defer cancel()
+
+	for {
+		select {
+		case <-srv.Context().Done():
+			return nil
+		case s := <-updates:
+			if err := srv.Send(es.toAPI(s)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Restart stops the editor process and starts it again.
+func (es *EditorService) Restart(ctx context.Context, req *api.RestartEditorRequest) (*api.RestartEditorResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, controlTimeout)
+	defer cancel()
+	if err := es.Control.Restart(ctx); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	return &api.RestartEditorResponse{}, nil
+}
+
+// Stop stops the editor process until it is restarted.
+func (es *EditorService) Stop(ctx context.Context, req *api.StopEditorRequest) (*api.StopEditorResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, controlTimeout)
+	defer cancel()
+	if err := es.Control.Stop(ctx); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	return &api.StopEditorResponse{}, nil
+}
+
+// Logs streams the output of the editor process.
+func (es *EditorService) Logs(req *api.EditorLogsRequest, srv api.EditorService_LogsServer) error {
+	var since time.Time
+	if req.Since > 0 {
+		since = time.Unix(req.Since, 0)
+	}
+	send := func(line LogLine) error {
+		return srv.Send(toAPILogLine(line))
+	}
+
+	lines, seq := es.Output.Tail(int(req.Tail), since)
+	for _, line := range lines {
+		if err := send(line); err != nil {
+			return err
+		}
+	}
+	if !req.Follow {
+		return nil
+	}
+
+	err := es.Output.Follow(srv.Context(), seq, send)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+func (es *EditorService) toAPI(s Status) *api.EditorStatus {
+	cfg := es.Control.config(es.Cfg)
+	res := &api.EditorStatus{
+		Name:     cfg.Editor.Name,
+		Version:  cfg.Editor.Version,
+		Phase:    toAPIPhase(s.Phase),
+		Ready:    es.Ready.Get(),
+		Pid:      int64(s.Pid),
+		Restarts: uint32(s.Restarts),
+		Reason:   s.Reason,
+		Since:    s.Since.Unix(),
+	}
+	if s.Pid != 0 {
+		res.Uptime = int64(time.Since(s.StartedAt) / time.Second)
+	}
+	if !s.NextRestart.IsZero() {
+		res.NextRestart = s.NextRestart.Unix()
+	}
+	for _, ext := range s.Extensions {
+		res.Extensions = append(res.Extensions, &api.EditorExtension{
+			Ref:   ext.Ref,
+			Id:    ext.ID,
+			State: toAPIExtensionState(ext.State),
+			Error: ext.Error,
+		})
+	}
+	return res
+}
+
+func toAPIPhase(phase Phase) api.EditorPhase {
+	switch phase {
+	case PhaseRunning:
+		return api.EditorPhase_running
+	case PhaseBackoff:
+		return api.EditorPhase_backoff
+	case PhaseFailed:
+		return api.EditorPhase_failed
+	case PhaseStopped:
+		return api.EditorPhase_stopped
+	default:
+		return api.EditorPhase_starting
+	}
+}
+
+func toAPIExtensionState(state ExtensionState) api.ExtensionState {
+	switch state {
+	case ExtensionInstalling:
+		return api.ExtensionState_installing
+	case ExtensionInstalled:
+		return api.ExtensionState_installed
+	case ExtensionSkipped:
+		return api.ExtensionState_skipped
+	case ExtensionFailed:
+		return api.ExtensionState_install_failed
+	default:
+		return api.ExtensionState_queued
+	}
+}
+
+func toAPILogLine(line LogLine) *api.EditorLogLine {
+	stream := api.EditorLogStream_stdout
+	if line.Stream == LogStderr {
+		stream = api.EditorLogStream_stderr
+	}
+	return &api.EditorLogLine{
+		Time:   line.Time.UnixMilli(),
+		Stream: stream,
+		Text:   line.Text,
+	}
+}
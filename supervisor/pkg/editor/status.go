@@ -0,0 +1,157 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Phase is the lifecycle phase of the editor process.
+type Phase string
+
+const (
+	// PhaseStarting means the editor process runs but is not ready yet.
+	PhaseStarting Phase = "starting"
+	// PhaseRunning means the editor is ready.
+	PhaseRunning Phase = "running"
+	// PhaseBackoff means the editor stopped and waits to be restarted.
+	PhaseBackoff Phase = "backoff"
+	// PhaseFailed means the editor stopped too often and is not restarted anymore.
+	PhaseFailed Phase = "failed"
+	// PhaseStopped means the editor has been stopped on request or shut down with supervisor.
+	PhaseStopped Phase = "stopped"
+)
+
+// Status describes the state of the editor process.
+type Status struct {
+	Phase Phase
+	// Since is the time the editor entered the phase
+	Since time.Time
+	// Pid of the editor process, 0 if it doesn't run
+	Pid int
+	// StartedAt is the time the editor process was started
+	StartedAt time.Time
+	// Restarts is the number of times the editor has been restarted
+	Restarts int
+	// Reason explains why the editor last stopped, e.g. "exited with code 1"
+	Reason string
+	// NextRestart is the time the editor is restarted at while in PhaseBackoff
+	NextRestart time.Time
+	// Extensions reports the installation of the extensions of the runtime config
+	Extensions []ExtensionStatus
+}
+
+// StatusState holds the status of the editor and notifies watchers of changes.
+type StatusState struct {
+	mu       sync.Mutex
+	status   Status
+	watchers map[chan Status]struct{}
+}
+
+// NewEditorStatusState creates a status state with the editor starting.
+func NewEditorStatusState() *StatusState {
+	return &StatusState{
+		status:   Status{Phase: PhaseStarting, Since: time.Now()},
+		watchers: make(map[chan Status]struct{}),
+	}
+}
+
+// Get returns the current status of the editor.
+func (s *StatusState) Get() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// Watch returns a channel receiving the status on every change, starting with the
+// current one. Intermediate updates are dropped if the watcher doesn't keep up,
+// but the latest status is always delivered. Call cancel to stop watching.
+func (s *StatusState) Watch() (updates <-chan Status, cancel func()) {
+	ch := make(chan Status, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch <- s.status
+	s.watchers[ch] = struct{}{}
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.watchers, ch)
+	}
+}
+
+// update applies change to the status and notifies all watchers.
+func (s *StatusState) update(change func(status *Status)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	phase := s.status.Phase
+	change(&s.status)
+	if s.status.Phase != phase {
+		s.status.Since = time.Now()
+	}
+	for ch := range s.watchers {
+		// replace a pending update nobody has read yet with the latest one
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- s.status
+	}
+}
+
+// restartPolicy determines how long to wait before restarting the editor and
+// when to give up because it is crash looping.
+type restartPolicy struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MaxRestarts is the number of restarts within Window after which the editor is failed
+	MaxRestarts int
+	Window      time.Duration
+}
+
+// crashHistory records when the editor stopped.
+type crashHistory struct {
+	policy restartPolicy
+	stops  []time.Time
+}
+
+// record adds a stop and returns the delay before the next restart, or false
+// if the editor stopped more than MaxRestarts times within Window.
+func (h *crashHistory) record(now time.Time) (time.Duration, bool) {
+	recent := h.stops[:0]
+	for _, t := range h.stops {
+		if now.Sub(t) < h.policy.Window {
+			recent = append(recent, t)
+		}
+	}
+	h.stops = append(recent, now)
+
+	if len(h.stops) > h.policy.MaxRestarts {
+		return 0, false
+	}
+	delay := h.policy.InitialBackoff
+	for i := 1; i < len(h.stops) && delay < h.policy.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, h.policy.MaxBackoff), true
+}
+
+// exitReason describes why the editor process stopped.
+func exitReason(err error) string {
+	if err == nil {
+		return "exited with code 0"
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err.Error()
+	}
+	if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return fmt.Sprintf("killed by signal %s", ws.Signal())
+	}
+	return fmt.Sprintf("exited with code %d", exitErr.ExitCode())
+}
@@ -1,5 +1,7 @@
 package editor
 
+import "time"
+
 // defaultIfEmpty returns defaultValue if the given string is empty
 func defaultIfEmpty(value, defaultValue string) string {
 	if value == "" {
@@ -15,3 +17,11 @@ func defaultIfZero(value, defaultValue int) int {
 	}
 	return value
 }
+
+// defaultIfZeroDuration returns defaultValue if the given duration is zero
+func defaultIfZeroDuration(value, defaultValue time.Duration) time.Duration {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -19,11 +19,11 @@ func ConfigureGit(cfg *config.Config) {
 	}
 
 	// Add user-specific settings if provided
-	if cfg.Workspace.GitUsername != "" {
-		defaultSettings = append(defaultSettings, []string{"user.name", cfg.Workspace.GitUsername})
+	if cfg.GitUsername != "" {
+		defaultSettings = append(defaultSettings, []string{"user.name", cfg.GitUsername})
 	}
-	if cfg.Workspace.GitEmail != "" {
-		defaultSettings = append(defaultSettings, []string{"user.email", cfg.Workspace.GitEmail})
+	if cfg.GitEmail != "" {
+		defaultSettings = append(defaultSettings, []string{"user.email", cfg.GitEmail})
 	}
 
 	applyGitSettings(defaultSettings)
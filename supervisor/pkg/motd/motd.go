@@ -0,0 +1,177 @@
+// Package motd renders the welcome banner printed at the top of new interactive terminals.
+package motd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"supervisor/api"
+)
+
+const (
+	colorReset  = "\x1b[0m"
+	colorBold   = "\x1b[1m"
+	colorDim    = "\x1b[2m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+)
+
+// TaskState is the state of a workspace task shown in the banner.
+type TaskState int
+
+const (
+	TaskRunning TaskState = iota
+	TaskSucceeded
+	TaskFailed
+)
+
+// TaskStatus describes a workspace task shown in the banner.
+type TaskStatus struct {
+	Name  string
+	State TaskState
+}
+
+// Status is the workspace status summarized in the banner.
+// Sections without information are left out.
+type Status struct {
+	WorkspaceURL string
+	EditorName   string
+	EditorReady  bool
+	// Tasks is nil if task states are unknown
+	Tasks []TaskStatus
+	// Ports are the ports workspace processes listen on
+	Ports     []uint32
+	Resources *api.ResourcesStatusResponse
+}
+
+// Render returns the banner for the given message and status. Lines end with CRLF,
+// as the banner is written to the terminal as-is.
+func Render(message string, status Status) string {
+	var b strings.Builder
+	line := func(label, value string) {
+		fmt.Fprintf(&b, "  %s%-10s%s %s\r\n", colorDim, label, colorReset, value)
+	}
+
+	b.WriteString("\r\n")
+	if message != "" {
+		for _, l := range strings.Split(strings.TrimRight(message, "\n"), "\n") {
+			fmt.Fprintf(&b, "%s%s%s\r\n", colorBold, l, colorReset)
+		}
+		b.WriteString("\r\n")
+	}
+
+	if status.WorkspaceURL != "" {
+		line("URL", status.WorkspaceURL)
+	}
+	if status.EditorName != "" {
+		state := colorYellow + "starting" + colorReset
+		if status.EditorReady {
+			state = colorGreen + "ready" + colorReset
+		}
+		line("Editor", fmt.Sprintf("%s %s", status.EditorName, state))
+	}
+	if status.Tasks != nil {
+		line("Tasks", renderTasks(status.Tasks))
+	}
+	if len(status.Ports) > 0 {
+		ports := make([]string, len(status.Ports))
+		for i, port := range status.Ports {
+			ports[i] = strconv.FormatUint(uint64(port), 10)
+		}
+		line("Ports", strings.Join(ports, ", "))
+	}
+	if res := status.Resources; res != nil {
+		var parts []string
+		for _, r := range []struct {
+			name   string
+			status *api.ResourceStatus
+		}{{"CPU", res.Cpu}, {"Memory", res.Memory}, {"Disk", res.Disk}} {
+			if r.status != nil {
+				parts = append(parts, fmt.Sprintf("%s %s", r.name, renderSeverity(r.status.Severity)))
+			}
+		}
+		line("Resources", strings.Join(parts, ", "))
+	}
+
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+func renderTasks(tasks []TaskStatus) string {
+	if len(tasks) == 0 {
+		return "none"
+	}
+	var (
+		running, succeeded int
+		failed             []string
+	)
+	for _, task := range tasks {
+		switch task.State {
+		case TaskRunning:
+			running++
+		case TaskSucceeded:
+			succeeded++
+		case TaskFailed:
+			failed = append(failed, task.Name)
+		}
+	}
+	parts := []string{fmt.Sprintf("%d running", running), fmt.Sprintf("%d done", succeeded)}
+	if len(failed) > 0 {
+		parts = append(parts, fmt.Sprintf("%s%d failed (%s)%s", colorRed, len(failed), strings.Join(failed, ", "), colorReset))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func renderSeverity(severity api.ResourceStatusSeverity) string {
+	switch severity {
+	case api.ResourceStatusSeverity_danger:
+		return colorRed + severity.String() + colorReset
+	case api.ResourceStatusSeverity_warning:
+		return colorYellow + severity.String() + colorReset
+	default:
+		return colorGreen + severity.String() + colorReset
+	}
+}
+
+// tcpListen is the socket state of listening sockets in /proc/net/tcp.
+const tcpListen = "0A"
+
+// ListeningPorts returns the TCP ports processes listen on, read from the given
+// files in /proc/net/tcp format. Files which don't exist are skipped.
+func ListeningPorts(files ...string) []uint32 {
+	seen := make(map[uint32]struct{})
+	for _, fn := range files {
+		f, err := os.Open(fn)
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 4 || fields[3] != tcpListen {
+				continue
+			}
+			_, port, ok := strings.Cut(fields[1], ":")
+			if !ok {
+				continue
+			}
+			p, err := strconv.ParseUint(port, 16, 16)
+			if err != nil {
+				continue
+			}
+			seen[uint32(p)] = struct{}{}
+		}
+		_ = f.Close()
+	}
+
+	res := make([]uint32, 0, len(seen))
+	for port := range seen {
+		res = append(res, port)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
@@ -0,0 +1,102 @@
+package motd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"supervisor/api"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		Desc         string
+		Message      string
+		Status       Status
+		Expectations []string
+		Missing      []string
+	}{
+		{
+			Desc:         "empty status",
+			Expectations: []string{},
+			Missing:      []string{"URL", "Editor", "Tasks", "Ports", "Resources"},
+		},
+		{
+			Desc:    "complete status",
+			Message: "Welcome to the api workspace\n",
+			Status: Status{
+				WorkspaceURL: "https://ws.example.com",
+				EditorName:   "code-server",
+				EditorReady:  true,
+				Tasks: []TaskStatus{
+					{Name: "install", State: TaskSucceeded},
+					{Name: "serve", State: TaskRunning},
+					{Name: "lint", State: TaskFailed},
+				},
+				Ports: []uint32{3000, 8080},
+				Resources: &api.ResourcesStatusResponse{
+					Cpu:    &api.ResourceStatus{Severity: api.ResourceStatusSeverity_normal},
+					Memory: &api.ResourceStatus{Severity: api.ResourceStatusSeverity_danger},
+				},
+			},
+			Expectations: []string{
+				"Welcome to the api workspace",
+				"https://ws.example.com",
+				"code-server " + colorGreen + "ready",
+				"1 running, 1 done, " + colorRed + "1 failed (lint)",
+				"3000, 8080",
+				"CPU " + colorGreen + "normal" + colorReset + ", Memory " + colorRed + "danger",
+			},
+			Missing: []string{"Disk"},
+		},
+		{
+			Desc:         "editor starting and no tasks",
+			Status:       Status{EditorName: "code-server", Tasks: []TaskStatus{}},
+			Expectations: []string{"code-server " + colorYellow + "starting", "Tasks     " + colorReset + " none"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			banner := Render(test.Message, test.Status)
+			for _, expectation := range test.Expectations {
+				if !strings.Contains(banner, expectation) {
+					t.Errorf("expected %q in banner %q", expectation, banner)
+				}
+			}
+			for _, missing := range test.Missing {
+				if strings.Contains(banner, missing) {
+					t.Errorf("unexpected %q in banner %q", missing, banner)
+				}
+			}
+			if strings.Contains(strings.ReplaceAll(banner, "\r\n", ""), "\n") {
+				t.Errorf("banner contains a line feed without carriage return: %q", banner)
+			}
+		})
+	}
+}
+
+func TestListeningPorts(t *testing.T) {
+	const tcp = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0BB8 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 1 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 2 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:1F90 0100007F:A2C4 01 00000000:00000000 00:00000000 00000000  1000        0 3 1 0000000000000000 20 4 30 10 -1
+   3: 0100007F:0016 0100007F:A2C6 01 00000000:00000000 00:00000000 00000000  1000        0 4 1 0000000000000000 20 4 30 10 -1
+`
+	const tcp6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:0BB8 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 5 1 0000000000000000 100 0 0 10 0
+   1: 00000000000000000000000000000000:0050 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 6 1 0000000000000000 100 0 0 10 0
+`
+	dir := t.TempDir()
+	for name, content := range map[string]string{"tcp": tcp, "tcp6": tcp6} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ports := ListeningPorts(filepath.Join(dir, "tcp"), filepath.Join(dir, "tcp6"), filepath.Join(dir, "missing"))
+	if diff := cmp.Diff([]uint32{80, 3000, 8080}, ports); diff != "" {
+		t.Errorf("unexpected ports (-want +got):\n%s", diff)
+	}
+}
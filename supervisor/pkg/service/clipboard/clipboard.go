@@ -0,0 +1,102 @@
+package clipboard
+
+import (
+	"context"
+	"supervisor/api"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxContentSize limits the size of the clipboard content.
+const maxContentSize = 1 << 20
+
+// ClipboardService holds the workspace clipboard, which is shared between the
+// programs running in terminals and the clients connected to the workspace.
+type ClipboardService struct {
+	mu       sync.Mutex
+	content  *api.ClipboardContent
+	watchers map[chan *api.ClipboardContent]struct{}
+
+	api.UnimplementedClipboardServiceServer
+}
+
+// RegisterGRPC registers the gRPC clipboard service.
+func (cs *ClipboardService) RegisterGRPC(srv *grpc.Server) {
+	api.RegisterClipboardServiceServer(srv, cs)
+}
+
+// Set replaces the clipboard content and notifies all watchers.
+func (cs *ClipboardService) Set(content []byte, source string) {
+	clipboard := &api.ClipboardContent{
+		Content:   content,
+		Source:    source,
+		UpdatedAt: time.Now().Unix(),
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.content = clipboard
+	for ch := range cs.watchers {
+		// watchers only care about the latest content
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- clipboard
+	}
+}
+
+// Copy sets the content of the workspace clipboard.
+func (cs *ClipboardService) Copy(ctx context.Context, req *api.CopyClipboardRequest) (*api.CopyClipboardResponse, error) {
+	if len(req.Content) > maxContentSize {
+		return nil, status.Errorf(codes.InvalidArgument, "clipboard content exceeds %d bytes", maxContentSize)
+	}
+	cs.Set(req.Content, req.Source)
+	return &api.CopyClipboardResponse{}, nil
+}
+
+// Paste returns the content of the workspace clipboard.
+func (cs *ClipboardService) Paste(ctx context.Context, req *api.PasteClipboardRequest) (*api.PasteClipboardResponse, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.content == nil {
+		return &api.PasteClipboardResponse{Clipboard: &api.ClipboardContent{}}, nil
+	}
+	return &api.PasteClipboardResponse{Clipboard: cs.content}, nil
+}
+
+// Watch streams the content of the workspace clipboard.
+func (cs *ClipboardService) Watch(req *api.WatchClipboardRequest, stream api.ClipboardService_WatchServer) error {
+	ch := make(chan *api.ClipboardContent, 1)
+
+	cs.mu.Lock()
+	if cs.watchers == nil {
+		cs.watchers = make(map[chan *api.ClipboardContent]struct{})
+	}
+	cs.watchers[ch] = struct{}{}
+	if cs.content != nil {
+		ch <- cs.content
+	}
+	cs.mu.Unlock()
+
+	defer func() {
+		cs.mu.Lock()
+		delete(cs.watchers, ch)
+		cs.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case content := <-ch:
+			if err := stream.Send(content); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package system
+
+import (
+	"context"
+	"supervisor/api"
+	"sync"
+	"time"
+)
+
+// historySize is the number of resource samples kept, i.e. one hour at one sample per second
+const historySize = 3600
+
+// resourceSample is the resource usage of the workspace at a point in time.
+type resourceSample struct {
+	time   time.Time
+	status *api.ResourcesStatusResponse
+	// pressure is nil if the kernel doesn't report pressure stall information
+	pressure *pressureTotals
+}
+
+// resourceHistory keeps the latest resource samples in a fixed-size ring buffer
+// and notifies watchers of every new sample.
+type resourceHistory struct {
+	mu       sync.Mutex
+	samples  []resourceSample
+	next     int
+	full     bool
+	watchers map[chan *api.ResourcesStatusResponse]struct{}
+}
+
+func newResourceHistory(size int) *resourceHistory {
+	return &resourceHistory{
+		samples:  make([]resourceSample, size),
+		watchers: make(map[chan *api.ResourcesStatusResponse]struct{}),
+	}
+}
+
+// add records a sample, replacing the oldest one if the history is full, and notifies all watchers.
+func (h *resourceHistory) add(sample resourceSample) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.samples[h.next] = sample
+	h.next = (h.next + 1) % len(h.samples)
+	if h.next == 0 {
+		h.full = true
+	}
+
+	for ch := range h.watchers {
+		// replace a pending sample nobody has read yet with the latest one
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- sample.status
+	}
+}
+
+// since returns the samples taken at or after t, ordered from the oldest to the latest one.
+func (h *resourceHistory) since(t time.Time) []resourceSample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var ordered []resourceSample
+	if h.full {
+		ordered = append(ordered, h.samples[h.next:]...)
+	}
+	ordered = append(ordered, h.samples[:h.next]...)
+
+	res := make([]resourceSample, 0, len(ordered))
+	for _, sample := range ordered {
+		if !sample.time.Before(t) {
+			res = append(res, sample)
+		}
+	}
+	return res
+}
+
+// latest returns the latest sample, false if there is none.
+func (h *resourceHistory) latest() (resourceSample, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.full && h.next == 0 {
+		return resourceSample{}, false
+	}
+	return h.samples[(h.next+len(h.samples)-1)%len(h.samples)], true
+}
+
+// pressureAt returns the latest sample with pressure stall information taken at or before t,
+// or the oldest one if there is none, false if no sample has pressure stall information.
+func (h *resourceHistory) pressureAt(t time.Time) (resourceSample, bool) {
+	var (
+		res resourceSample
+		ok  bool
+	)
+	for _, sample := range h.since(time.Time{}) {
+		if sample.pressure == nil {
+			continue
+		}
+		if ok && sample.time.After(t) {
+			break
+		}
+		res, ok = sample, true
+	}
+	return res, ok
+}
+
+// watch returns a channel receiving the status of every new sample. Intermediate samples are
+// dropped if the watcher doesn't keep up. Call cancel to stop watching.
+func (h *resourceHistory) watch() (updates <-chan *api.ResourcesStatusResponse, cancel func()) {
+	ch := make(chan *api.ResourcesStatusResponse, 1)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.watchers[ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.watchers, ch)
+	}
+}
+
+// ResourcesHistory returns the resource usage sampled by SampleResources within the requested window.
+func (is *SystemService) ResourcesHistory(ctx context.Context, req *api.ResourcesHistoryRequest) (*api.ResourcesHistoryResponse, error) {
+	var since time.Time
+	if req.Window > 0 {
+		since = time.Now().Add(-time.Duration(req.Window) * time.Second)
+	}
+	samples := is.resourceHistory().since(since)
+	return &api.ResourcesHistoryResponse{
+		Samples: downsample(samples, time.Duration(req.Step)*time.Second),
+	}, nil
+}
+
+// WatchResources streams the resource usage every time SampleResources samples it, starting with the latest sample.
+func (is *SystemService) WatchResources(req *api.WatchResourcesRequest, srv api.SystemService_WatchResourcesServer) error {
+	history := is.resourceHistory()
+	updates, cancel := history.watch()
+	defer cancel()
+
+	if sample, ok := history.latest(); ok {
+		if err := srv.Send(sample.status); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-srv.Context().Done():
+			return nil
+		case status := <-updates:
+			if err := srv.Send(status); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// downsample merges the samples within each step into one sample holding the highest usage and
+// severity, and the latest limit and time. The samples are returned as they are if step is 0.
+func downsample(samples []resourceSample, step time.Duration) []*api.ResourcesSample {
+	var (
+		res    []*api.ResourcesSample
+		bucket time.Time
+	)
+	for _, sample := range samples {
+		status := sample.status
+		if step > 0 && len(res) > 0 && sample.time.Sub(bucket) < step {
+			last := res[len(res)-1]
+			last.Time = sample.time.Unix()
+			last.Cpu = peak(last.Cpu, status.Cpu)
+			last.Memory = peak(last.Memory, status.Memory)
+			last.Disk = peak(last.Disk, status.Disk)
+			continue
+		}
+		bucket = sample.time
+		res = append(res, &api.ResourcesSample{
+			Time:   sample.time.Unix(),
+			Cpu:    peak(nil, status.Cpu),
+			Memory: peak(nil, status.Memory),
+			Disk:   peak(nil, status.Disk),
+		})
+	}
+	return res
+}
+
+// peak returns a copy of a with the highest usage and severity of a and b, and the limit of b.
+func peak(a, b *api.ResourceStatus) *api.ResourceStatus {
+	res := &api.ResourceStatus{
+		Used:     b.GetUsed(),
+		Limit:    b.GetLimit(),
+		Severity: b.GetSeverity(),
+	}
+	if a.GetUsed() > res.Used {
+		res.Used = a.GetUsed()
+	}
+	if a.GetSeverity() > res.Severity {
+		res.Severity = a.GetSeverity()
+	}
+	return res
+}
@@ -0,0 +1,111 @@
+package system
+
+import (
+	"supervisor/api"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestResourceHistory(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	sample := func(i int, cpu int64) resourceSample {
+		return resourceSample{
+			time: start.Add(time.Duration(i) * time.Second),
+			status: &api.ResourcesStatusResponse{
+				Cpu:    &api.ResourceStatus{Used: cpu, Limit: 1000},
+				Memory: &api.ResourceStatus{Used: 1, Limit: 2},
+				Disk:   &api.ResourceStatus{Used: 3, Limit: 4},
+			},
+		}
+	}
+	cpuOf := func(samples []resourceSample) []int64 {
+		res := make([]int64, 0, len(samples))
+		for _, s := range samples {
+			res = append(res, s.status.Cpu.Used)
+		}
+		return res
+	}
+
+	history := newResourceHistory(4)
+	if _, ok := history.latest(); ok {
+		t.Error("empty history has a latest sample")
+	}
+	updates, cancel := history.watch()
+	defer cancel()
+	for i := 0; i < 6; i++ {
+		history.add(sample(i, int64(i*100)))
+	}
+
+	if diff := cmp.Diff([]int64{200, 300, 400, 500}, cpuOf(history.since(time.Time{}))); diff != "" {
+		t.Errorf("unexpected samples (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{400, 500}, cpuOf(history.since(start.Add(4*time.Second)))); diff != "" {
+		t.Errorf("unexpected samples in window (-want +got):\n%s", diff)
+	}
+	if latest, ok := history.latest(); !ok || latest.status.Cpu.Used != 500 {
+		t.Errorf("unexpected latest sample: %v", latest.status)
+	}
+	select {
+	case status := <-updates:
+		if diff := cmp.Diff(sample(5, 500).status, status, protocmp.Transform()); diff != "" {
+			t.Errorf("watcher did not receive the latest sample (-want +got):\n%s", diff)
+		}
+	default:
+		t.Error("watcher did not receive a sample")
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	samples := make([]resourceSample, 0, 5)
+	for i, cpu := range []int64{100, 900, 200, 300, 100} {
+		severity := api.ResourceStatusSeverity_normal
+		if cpu > 800 {
+			severity = api.ResourceStatusSeverity_warning
+		}
+		samples = append(samples, resourceSample{
+			time: start.Add(time.Duration(i) * time.Second),
+			status: &api.ResourcesStatusResponse{
+				Cpu:    &api.ResourceStatus{Used: cpu, Limit: 1000, Severity: severity},
+				Memory: &api.ResourceStatus{Used: int64(i), Limit: 10},
+				Disk:   &api.ResourceStatus{Used: 5, Limit: 10},
+			},
+		})
+	}
+
+	tests := []struct {
+		Desc        string
+		Step        time.Duration
+		Expectation []*api.ResourcesSample
+	}{
+		{
+			Desc: "every sample",
+			Expectation: []*api.ResourcesSample{
+				{Time: 1700000000, Cpu: &api.ResourceStatus{Used: 100, Limit: 1000}, Memory: &api.ResourceStatus{Used: 0, Limit: 10}, Disk: &api.ResourceStatus{Used: 5, Limit: 10}},
+				{Time: 1700000001, Cpu: &api.ResourceStatus{Used: 900, Limit: 1000, Severity: api.ResourceStatusSeverity_warning}, Memory: &api.ResourceStatus{Used: 1, Limit: 10}, Disk: &api.ResourceStatus{Used: 5, Limit: 10}},
+				{Time: 1700000002, Cpu: &api.ResourceStatus{Used: 200, Limit: 1000}, Memory: &api.ResourceStatus{Used: 2, Limit: 10}, Disk: &api.ResourceStatus{Used: 5, Limit: 10}},
+				{Time: 1700000003, Cpu: &api.ResourceStatus{Used: 300, Limit: 1000}, Memory: &api.ResourceStatus{Used: 3, Limit: 10}, Disk: &api.ResourceStatus{Used: 5, Limit: 10}},
+				{Time: 1700000004, Cpu: &api.ResourceStatus{Used: 100, Limit: 1000}, Memory: &api.ResourceStatus{Used: 4, Limit: 10}, Disk: &api.ResourceStatus{Used: 5, Limit: 10}},
+			},
+		},
+		{
+			Desc: "peaks",
+			Step: 2 * time.Second,
+			Expectation: []*api.ResourcesSample{
+				{Time: 1700000001, Cpu: &api.ResourceStatus{Used: 900, Limit: 1000, Severity: api.ResourceStatusSeverity_warning}, Memory: &api.ResourceStatus{Used: 1, Limit: 10}, Disk: &api.ResourceStatus{Used: 5, Limit: 10}},
+				{Time: 1700000003, Cpu: &api.ResourceStatus{Used: 300, Limit: 1000}, Memory: &api.ResourceStatus{Used: 3, Limit: 10}, Disk: &api.ResourceStatus{Used: 5, Limit: 10}},
+				{Time: 1700000004, Cpu: &api.ResourceStatus{Used: 100, Limit: 1000}, Memory: &api.ResourceStatus{Used: 4, Limit: 10}, Disk: &api.ResourceStatus{Used: 5, Limit: 10}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			if diff := cmp.Diff(test.Expectation, downsample(samples, test.Step), protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected samples (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
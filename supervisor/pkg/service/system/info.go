@@ -6,14 +6,15 @@ import (
 )
 
 func (is *SystemService) WorkspaceInfo(ctx context.Context, request *api.WorkspaceInfoRequest) (*api.WorkspaceInfoResponse, error) {
+	cfg := is.ConfigProvider()
 	resp := &api.WorkspaceInfoResponse{
-		WorkspaceId:      is.Cfg.WorkspaceID,
-		CheckoutLocation: is.Cfg.WorkspaceLocation + "/devel",
-		UserHome:         is.Cfg.WorkspaceLocation,
-		OwnerId:          is.Cfg.OwnerId,
-		ClusterHost:      is.Cfg.WorkspaceClusterHost,
-		IdeAlias:         is.Cfg.Editor.Name,
-		IdePort:          3000,
+		WorkspaceId:      cfg.WorkspaceID,
+		CheckoutLocation: cfg.WorkspaceLocation + "/devel",
+		UserHome:         cfg.WorkspaceLocation,
+		OwnerId:          cfg.OwnerId,
+		ClusterHost:      cfg.WorkspaceClusterHost,
+		IdeAlias:         cfg.Editor.Name,
+		IdePort:          uint32(cfg.Editor.GetPort()),
 	}
 
 	return resp, nil
@@ -1,11 +1,14 @@
 package system
 
 import (
+	"common/log"
 	"context"
 	"os"
 	"strconv"
 	"strings"
 	"supervisor/api"
+	"sync"
+	"time"
 
 	"golang.org/x/sys/unix"
 )
@@ -15,12 +18,23 @@ func (is *SystemService) ResourcesStatus(ctx context.Context, request *api.Resou
 	return is.GetResources(ctx)
 }
 
-// calcSeverity maps a percentage to a severity level.
-func calcSeverity(percentage int64) api.ResourceStatusSeverity {
+// Shares of the last minute in percent in which tasks were stalled on a resource, above which
+// its severity is raised regardless of its usage
+const (
+	// pressureWarning applies to the time in which some tasks were stalled
+	pressureWarning = 20
+	// pressureDanger applies to the time in which all tasks were stalled at the same time
+	pressureDanger = 10
+)
+
+// calcSeverity maps a percentage and the pressure stall information of a resource to a
+// severity level, s.t. a resource the workspace constantly stalls on is reported even if
+// it is far from its limit. pressure may be nil.
+func calcSeverity(percentage int64, pressure *api.ResourcePressure) api.ResourceStatusSeverity {
 	switch {
-	case percentage >= 95:
+	case percentage >= 95 || pressure.GetFullAvg60() >= pressureDanger:
 		return api.ResourceStatusSeverity_danger
-	case percentage >= 80:
+	case percentage >= 80 || pressure.GetSomeAvg60() >= pressureWarning:
 		return api.ResourceStatusSeverity_warning
 	default:
 		return api.ResourceStatusSeverity_normal
@@ -29,41 +43,254 @@ func calcSeverity(percentage int64) api.ResourceStatusSeverity {
 
 // GetResources collects and returns current CPU and Memory usage with severity levels.
 func (is *SystemService) GetResources(ctx context.Context) (*api.ResourcesStatusResponse, error) {
-	memory, err := resolveMemoryStatus()
+	sample, err := is.collectResources(time.Now())
 	if err != nil {
 		return nil, err
 	}
+	return sample.status, nil
+}
 
-	cpu, err := resolveCPUStatus()
+// collectResources collects the resource usage at now, including the pressure stall totals
+// from which the stall rates of later samples are computed.
+func (is *SystemService) collectResources(now time.Time) (resourceSample, error) {
+	cfg := is.ConfigProvider()
+	memory, err := resolveMemoryStatus()
 	if err != nil {
-		return nil, err
+		return resourceSample{}, err
 	}
 
-	disk, err := getDiskUsage(is.Cfg.WorkspaceLocation)
+	cpu, err := is.cpuSampler().status(now)
 	if err != nil {
-		return nil, err
+		return resourceSample{}, err
+	}
+
+	disk, err := getDiskUsage(cfg.WorkspaceLocation)
+	if err != nil {
+		return resourceSample{}, err
 	}
 
 	cpuPct := int64(float64(cpu.Used) / float64(cpu.Limit) * 100)
 	memPct := int64(float64(memory.Used) / float64(memory.Limit) * 100)
 	diskPct := int64(float64(disk.Used) / float64(disk.Limit) * 100)
 
-	cpu.Severity = calcSeverity(cpuPct)
-	memory.Severity = calcSeverity(memPct)
-	disk.Severity = calcSeverity(diskPct)
+	var (
+		pressure                             *pressureTotals
+		cpuPressure, memPressure, ioPressure *api.ResourcePressure
+	)
+	if totals, err := resolvePressure(); err != nil {
+		log.WithError(err).Debug("cannot read pressure stall information")
+	} else {
+		pressure = &totals
+		cpuPressure, memPressure, ioPressure = is.pressureStatus(now, totals)
+	}
+
+	cpu.Severity = calcSeverity(cpuPct, cpuPressure)
+	memory.Severity = calcSeverity(memPct, memPressure)
+	disk.Severity = calcSeverity(diskPct, ioPressure)
 
-	return &api.ResourcesStatusResponse{
-		Flavor: is.Cfg.FlavorName,
-		Memory: memory,
-		Cpu:    cpu,
-		Disk:   disk,
+	var groups []*api.ResourceGroupStatus
+	if is.GroupsProvider != nil {
+		groups, err = is.GroupsProvider()
+		if err != nil {
+			log.WithError(err).Warn("cannot read the resource usage of the cgroups")
+		}
+		for _, group := range groups {
+			if group.Memory.Limit > 0 {
+				group.Memory.Severity = calcSeverity(int64(float64(group.Memory.Used)/float64(group.Memory.Limit)*100), nil)
+			}
+		}
+	}
+
+	return resourceSample{
+		time: now,
+		status: &api.ResourcesStatusResponse{
+			Flavor:         cfg.FlavorName,
+			Memory:         memory,
+			Cpu:            cpu,
+			Disk:           disk,
+			Groups:         groups,
+			CpuPressure:    cpuPressure,
+			MemoryPressure: memPressure,
+			IoPressure:     ioPressure,
+		},
+		pressure: pressure,
 	}, nil
 }
 
-// cpuStat represents a snapshot of CPU usage at a given uptime.
-type cpuStat struct {
-	usage  float64
-	uptime float64
+// SampleResources samples the resource usage every interval until ctx is cancelled, s.t.
+// ResourcesStatus returns the CPU usage of the latest interval right away, and records
+// the samples in the history of ResourcesHistory and WatchResources.
+// Until the first interval has been sampled, the CPU usage is the average since startup.
+func (is *SystemService) SampleResources(ctx context.Context, interval time.Duration) {
+	sampler := is.cpuSampler()
+	history := is.resourceHistory()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			sampler.sample(now)
+			sample, err := is.collectResources(now)
+			if err != nil {
+				log.WithError(err).Debug("cannot sample resource usage")
+				continue
+			}
+			history.add(sample)
+		}
+	}
+}
+
+// cpuSampler returns the CPU sampler, which is created on first use.
+func (is *SystemService) cpuSampler() *cpuSampler {
+	is.initSampling()
+	return is.cpu
+}
+
+// resourceHistory returns the history of the resource usage, which is created on first use.
+func (is *SystemService) resourceHistory() *resourceHistory {
+	is.initSampling()
+	return is.history
+}
+
+func (is *SystemService) initSampling() {
+	is.samplingOnce.Do(func() {
+		acct, err := newCPUAccounting()
+		is.cpu = newCPUSampler(acct, err, time.Now())
+		is.history = newResourceHistory(historySize)
+	})
+}
+
+// pressureTotals are the total stall times of the workspace in microseconds, as reported by
+// the pressure stall information of the cpu, memory and io controllers.
+type pressureTotals struct {
+	cpu, memory, io stallTotals
+}
+
+// stallTotals are the times in which some or all tasks were stalled on a resource.
+type stallTotals struct {
+	some, full uint64
+}
+
+// pressureStatus returns the cpu, memory and io pressure of the totals at now, with the stall
+// rates of the last 10 and 60 seconds computed from the samples in the history. The rates cover
+// a shorter time as long as the history is shorter.
+func (is *SystemService) pressureStatus(now time.Time, totals pressureTotals) (cpu, memory, io *api.ResourcePressure) {
+	history := is.resourceHistory()
+	base10, ok10 := history.pressureAt(now.Add(-10 * time.Second))
+	base60, ok60 := history.pressureAt(now.Add(-60 * time.Second))
+
+	status := func(resource func(pressureTotals) stallTotals) *api.ResourcePressure {
+		current := resource(totals)
+		res := &api.ResourcePressure{
+			SomeTotal: current.some,
+			FullTotal: current.full,
+		}
+		if ok10 {
+			prev, elapsed := resource(*base10.pressure), now.Sub(base10.time)
+			res.SomeAvg10 = stallRate(current.some, prev.some, elapsed)
+			res.FullAvg10 = stallRate(current.full, prev.full, elapsed)
+		}
+		if ok60 {
+			prev, elapsed := resource(*base60.pressure), now.Sub(base60.time)
+			res.SomeAvg60 = stallRate(current.some, prev.some, elapsed)
+			res.FullAvg60 = stallRate(current.full, prev.full, elapsed)
+		}
+		return res
+	}
+	return status(func(p pressureTotals) stallTotals { return p.cpu }),
+		status(func(p pressureTotals) stallTotals { return p.memory }),
+		status(func(p pressureTotals) stallTotals { return p.io })
+}
+
+// stallRate returns the share in percent of elapsed in which tasks were stalled, given the
+// total stall times in microseconds at its end and at its start.
+func stallRate(total, prev uint64, elapsed time.Duration) float64 {
+	if elapsed <= 0 || total < prev {
+		return 0
+	}
+	return min(float64(total-prev)/float64(elapsed.Microseconds())*100, 100)
+}
+
+// cpuAccounting reads the CPU usage and limit of the workspace.
+type cpuAccounting interface {
+	// usage returns the CPU time used by the workspace so far
+	usage() (time.Duration, error)
+	// limit returns the CPUs available to the workspace in millicores
+	limit() (int64, error)
+}
+
+// cpuSampler computes the CPU usage from the difference of consecutive samples.
+type cpuSampler struct {
+	acct cpuAccounting
+
+	mu        sync.Mutex
+	lastUsage time.Duration
+	lastTime  time.Time
+	used      int64
+	// sampled is true once used covers an interval
+	sampled bool
+	err     error
+}
+
+// newCPUSampler creates a sampler which takes its first sample at now. err is the
+// error of creating acct, which status reports.
+func newCPUSampler(acct cpuAccounting, err error, now time.Time) *cpuSampler {
+	s := &cpuSampler{acct: acct, err: err}
+	if err == nil {
+		s.lastUsage, s.err = acct.usage()
+		s.lastTime = now
+	}
+	return s
+}
+
+// sample takes a sample at now and updates the CPU usage with the usage since the previous one.
+func (s *cpuSampler) sample(now time.Time) {
+	if s.acct == nil {
+		return
+	}
+	usage, err := s.acct.usage()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+	if err != nil {
+		return
+	}
+	if elapsed := now.Sub(s.lastTime); !s.lastTime.IsZero() && elapsed > 0 && usage >= s.lastUsage {
+		s.used = int64(float64(usage-s.lastUsage) / float64(elapsed) * 1000)
+		s.sampled = true
+	}
+	s.lastUsage, s.lastTime = usage, now
+}
+
+// status returns the CPU usage of the latest interval and the current limit in millicores.
+// If no interval has been sampled yet, it takes a sample at now to report the usage since
+// the first sample instead of none.
+func (s *cpuSampler) status(now time.Time) (*api.ResourceStatus, error) {
+	s.mu.Lock()
+	sampled := s.sampled
+	s.mu.Unlock()
+	if !sampled {
+		s.sample(now)
+	}
+
+	s.mu.Lock()
+	used, err := s.used, s.err
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := s.acct.limit()
+	if err != nil {
+		return nil, err
+	}
+	return &api.ResourceStatus{
+		Limit: limit,
+		Used:  used,
+	}, nil
 }
 
 // readIntFile reads a file and parses it as an integer.
@@ -85,11 +312,11 @@ func getDiskUsage(path string) (*api.ResourceStatus, error) {
 	// Total size = total blocks * block size
 	total := stat.Blocks * uint64(stat.Bsize)
 
-	// Available size = available blocks * block size
-	available := stat.Bavail * uint64(stat.Bsize)
+	// Used size = blocks not available to the workspace * block size
+	used := (stat.Blocks - stat.Bavail) * uint64(stat.Bsize)
 
 	return &api.ResourceStatus{
 		Limit: int64(total),
-		Used:  int64(available),
+		Used:  int64(used),
 	}, nil
 }
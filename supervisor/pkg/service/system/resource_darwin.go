@@ -1,8 +1,10 @@
 package system
 
 import (
+	"errors"
 	"math/rand"
 	"supervisor/api"
+	"time"
 )
 
 // ⚠️ Compatibility note:
@@ -10,14 +12,6 @@ import (
 // as a whole, will not run on macOS in any case.
 // Only Linux environments are supported.
 
-func resolveCPUStat() (*cpuStat, error) {
-	// Fake CPU usage: 0–100%, uptime: up to ~30 days
-	return &cpuStat{
-		usage:  rand.Float64() * 100,                // % CPU usage
-		uptime: float64(rand.Intn(30*24*60*60 + 1)), // seconds
-	}, nil
-}
-
 func resolveMemoryStatus() (*api.ResourceStatus, error) {
 	// Fake memory: random between 4–32GB total, 10–90% used
 	limit := int64((4 + rand.Intn(29)) * 1024 * 1024 * 1024) // 4–32 GiB
@@ -28,18 +22,34 @@ func resolveMemoryStatus() (*api.ResourceStatus, error) {
 	}, nil
 }
 
-// readProcUptime returns random uptime in seconds (up to 7 days).
-func readProcUptime() (float64, error) {
-	return float64(rand.Intn(7 * 24 * 60 * 60)), nil
+// newCPUAccounting returns a fake CPU accounting.
+func newCPUAccounting() (cpuAccounting, error) {
+	// Random between 1–8 CPUs
+	return &fakeCPUAccounting{limitMillicores: int64((1 + rand.Intn(8)) * 1000)}, nil
 }
 
-// resolveCPUStatus calculates fake CPU quota limits.
-func resolveCPUStatus() (*api.ResourceStatus, error) {
-	// Random between 1–8 CPUs, with 5–95% usage
-	limit := int64((1 + rand.Intn(8)) * 1000) // millicores
-	used := int64(float64(limit) * (0.05 + rand.Float64()*0.9))
-	return &api.ResourceStatus{
-		Limit: limit,
-		Used:  used,
-	}, nil
+// fakeCPUAccounting reports 5–95% usage of its limit.
+type fakeCPUAccounting struct {
+	limitMillicores int64
+	total           time.Duration
+	last            time.Time
+}
+
+func (c *fakeCPUAccounting) usage() (time.Duration, error) {
+	now := time.Now()
+	if !c.last.IsZero() {
+		cpus := float64(c.limitMillicores) / 1000 * (0.05 + rand.Float64()*0.9)
+		c.total += time.Duration(float64(now.Sub(c.last)) * cpus)
+	}
+	c.last = now
+	return c.total, nil
+}
+
+func (c *fakeCPUAccounting) limit() (int64, error) {
+	return c.limitMillicores, nil
+}
+
+// resolvePressure fails, since pressure stall information is a feature of the Linux kernel.
+func resolvePressure() (pressureTotals, error) {
+	return pressureTotals{}, errors.New("pressure stall information is not available on darwin")
 }
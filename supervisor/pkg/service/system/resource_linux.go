@@ -2,39 +2,19 @@ package system
 
 import (
 	"fmt"
-	"os"
-	"strconv"
-	"strings"
+	"math"
+	"path/filepath"
+	"runtime"
 	"supervisor/api"
-	cgroups "supervisor/pkg/cgroups/v2"
+	"supervisor/pkg/cgroups"
+	cgroups_v2 "supervisor/pkg/cgroups/v2"
+	"time"
 
 	linuxproc "github.com/c9s/goprocinfo/linux"
-	"golang.org/x/xerrors"
 )
 
-func resolveCPUStat() (*cpuStat, error) {
-	cpu := cgroups.NewCpuController("/sys/fs/cgroups")
-
-	stats, err := cpu.Stat()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to parse cpu.stat: %w", err)
-	}
-
-	usage := float64(stats.UsageTotal) * 1e-9
-
-	uptime, err := readProcUptime()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to parse uptime: %w", err)
-	}
-
-	return &cpuStat{
-		usage:  usage,
-		uptime: uptime,
-	}, nil
-}
-
 func resolveMemoryStatus() (*api.ResourceStatus, error) {
-	memory := cgroups.NewMemoryController("/sys/fs/cgroup")
+	memory := cgroups_v2.NewMemoryController(cgroups.DefaultMountPoint)
 
 	limit, err := memory.Max()
 	if err != nil {
@@ -73,62 +53,90 @@ func resolveMemoryStatus() (*api.ResourceStatus, error) {
 	}, nil
 }
 
-// readProcUptime returns system uptime in seconds.
-func readProcUptime() (float64, error) {
-	content, err := os.ReadFile("/proc/uptime")
+// newCPUAccounting returns the CPU accounting of the cgroup version the workspace uses.
+func newCPUAccounting() (cpuAccounting, error) {
+	unified, err := cgroups.IsUnifiedCgroupSetup()
 	if err != nil {
-		return 0, fmt.Errorf("failed to read uptime: %w", err)
+		return nil, err
 	}
-	fields := strings.Fields(strings.TrimSpace(string(content)))
-	uptime, err := strconv.ParseFloat(fields[0], 64)
-	if err != nil {
-		return 0, fmt.Errorf("failed to parse uptime: %w", err)
+	if unified {
+		return &cpuV2{cpu: cgroups_v2.NewCpuController(cgroups.DefaultMountPoint)}, nil
 	}
-	return uptime, nil
+	return &cpuV1{path: filepath.Join(cgroups.DefaultMountPoint, "cpu")}, nil
+}
+
+// cpuV2 reads the CPU usage and limit from the cpu.stat and cpu.max files of cgroup v2.
+type cpuV2 struct {
+	cpu *cgroups_v2.Cpu
 }
 
-// resolveCPUStatus calculates CPU usage and quota limits.
-func resolveCPUStatus() (*api.ResourceStatus, error) {
-	// Capture two snapshots with delay to measure CPU usage.
-	sample1, err := resolveCPUStat()
+func (c *cpuV2) usage() (time.Duration, error) {
+	stats, err := c.cpu.Stat()
 	if err != nil {
-		return nil, err
+		return 0, fmt.Errorf("failed to parse cpu.stat: %w", err)
 	}
-	time.Sleep(time.Second)
+	return time.Duration(stats.UsageTotal) * time.Microsecond, nil
+}
 
-	sample2, err := resolveCPUStat()
+func (c *cpuV2) limit() (int64, error) {
+	quota, period, err := c.cpu.Max()
 	if err != nil {
-		return nil, err
+		return 0, fmt.Errorf("failed to parse cpu.max: %w", err)
+	}
+	if quota == math.MaxUint64 || period == 0 {
+		return int64(runtime.NumCPU()) * 1000, nil
 	}
+	return int64(quota * 1000 / period), nil
+}
 
-	// Calculate usage.
-	cpuUsage := sample2.usage - sample1.usage
-	totalTime := sample2.uptime - sample1.uptime
-	used := cpuUsage / totalTime * 1000
+// cpuV1 reads the CPU usage and limit from the cpuacct and CFS files of cgroup v1.
+type cpuV1 struct {
+	path string
+}
 
-	// Determine CPU quota.
-	quota, err := readIntFile("/sys/fs/cgroup/cpu/cpu.cfs_quota_us")
+func (c *cpuV1) usage() (time.Duration, error) {
+	usage, err := cgroups.ReadSingleValue(filepath.Join(c.path, "cpuacct.usage"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read cpu.cfs_quota_us: %w", err)
+		return 0, fmt.Errorf("failed to read cpuacct.usage: %w", err)
 	}
+	return time.Duration(usage), nil
+}
 
-	var limit int
-	if quota > 0 {
-		period, err := readIntFile("/sys/fs/cgroup/cpu/cpu.cfs_period_us")
-		if err != nil {
-			return nil, fmt.Errorf("failed to read cpu.cfs_period_us: %w", err)
-		}
-		limit = quota / period * 1000
-	} else {
-		content, err := os.ReadFile("/sys/fs/cgroup/cpu/cpuacct.usage_percpu")
-		if err != nil {
-			return nil, fmt.Errorf("failed to read cpuacct.usage_percpu: %w", err)
-		}
-		limit = len(strings.Fields(strings.TrimSpace(string(content)))) * 1000
+func (c *cpuV1) limit() (int64, error) {
+	quota, err := readIntFile(filepath.Join(c.path, "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cpu.cfs_quota_us: %w", err)
 	}
+	if quota <= 0 {
+		return int64(runtime.NumCPU()) * 1000, nil
+	}
+	period, err := readIntFile(filepath.Join(c.path, "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cpu.cfs_period_us: %w", err)
+	}
+	if period <= 0 {
+		return 0, fmt.Errorf("invalid cpu.cfs_period_us: %d", period)
+	}
+	return int64(quota) * 1000 / int64(period), nil
+}
 
-	return &api.ResourceStatus{
-		Limit: int64(limit),
-		Used:  int64(used),
+// resolvePressure reads the total stall times of the workspace cgroup.
+func resolvePressure() (pressureTotals, error) {
+	cpu, err := cgroups_v2.NewCpuController(cgroups.DefaultMountPoint).PSI()
+	if err != nil {
+		return pressureTotals{}, fmt.Errorf("failed to read cpu.pressure: %w", err)
+	}
+	memory, err := cgroups_v2.NewMemoryController(cgroups.DefaultMountPoint).PSI()
+	if err != nil {
+		return pressureTotals{}, fmt.Errorf("failed to read memory.pressure: %w", err)
+	}
+	io, err := cgroups_v2.NewIOController(cgroups.DefaultMountPoint).PSI()
+	if err != nil {
+		return pressureTotals{}, fmt.Errorf("failed to read io.pressure: %w", err)
+	}
+	return pressureTotals{
+		cpu:    stallTotals{some: cpu.Some, full: cpu.Full},
+		memory: stallTotals{some: memory.Some, full: memory.Full},
+		io:     stallTotals{some: io.Some, full: io.Full},
 	}, nil
 }
@@ -0,0 +1,92 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	cgroups_v2 "supervisor/pkg/cgroups/v2"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCPUAccounting(t *testing.T) {
+	numCPU := int64(runtime.NumCPU()) * 1000
+	tests := []struct {
+		Desc       string
+		V2         bool
+		Files      map[string]string
+		Usage      time.Duration
+		Limit      int64
+		LimitError bool
+	}{
+		{
+			Desc:  "v2 with quota",
+			V2:    true,
+			Files: map[string]string{"cpu.stat": "usage_usec 1500000\nuser_usec 1000000\nsystem_usec 500000\n", "cpu.max": "150000 100000\n"},
+			Usage: 1500 * time.Millisecond,
+			Limit: 1500,
+		},
+		{
+			Desc:  "v2 without quota",
+			V2:    true,
+			Files: map[string]string{"cpu.stat": "usage_usec 42\n", "cpu.max": "max 100000\n"},
+			Usage: 42 * time.Microsecond,
+			Limit: numCPU,
+		},
+		{
+			Desc:  "v1 with quota",
+			Files: map[string]string{"cpuacct.usage": "2000000000\n", "cpu.cfs_quota_us": "50000\n", "cpu.cfs_period_us": "100000\n"},
+			Usage: 2 * time.Second,
+			Limit: 500,
+		},
+		{
+			Desc:  "v1 without quota",
+			Files: map[string]string{"cpuacct.usage": "7\n", "cpu.cfs_quota_us": "-1\n"},
+			Usage: 7 * time.Nanosecond,
+			Limit: numCPU,
+		},
+		{
+			Desc:       "v1 without period",
+			Files:      map[string]string{"cpuacct.usage": "7\n", "cpu.cfs_quota_us": "50000\n"},
+			Usage:      7 * time.Nanosecond,
+			LimitError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range test.Files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var acct cpuAccounting = &cpuV1{path: dir}
+			if test.V2 {
+				acct = &cpuV2{cpu: cgroups_v2.NewCpuController(dir)}
+			}
+
+			usage, err := acct.usage()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Usage, usage); diff != "" {
+				t.Errorf("unexpected usage (-want +got):\n%s", diff)
+			}
+			limit, err := acct.limit()
+			if test.LimitError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Limit, limit); diff != "" {
+				t.Errorf("unexpected limit (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package system
+
+import (
+	"errors"
+	"supervisor/api"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/sys/unix"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+type staticCPUAccounting struct {
+	used time.Duration
+	err  error
+}
+
+func (c *staticCPUAccounting) usage() (time.Duration, error) { return c.used, c.err }
+
+func (c *staticCPUAccounting) limit() (int64, error) { return 4000, nil }
+
+func TestCPUSampler(t *testing.T) {
+	start := time.Now()
+	acct := &staticCPUAccounting{used: 10 * time.Second}
+	sampler := newCPUSampler(acct, nil, start)
+
+	// the usage since the first sample is reported before an interval has been sampled
+	acct.used = 11 * time.Second
+	act, err := sampler.status(start.Add(500 * time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&api.ResourceStatus{Used: 2000, Limit: 4000}, act, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected initial cpu status (-want +got):\n%s", diff)
+	}
+
+	tests := []struct {
+		Desc        string
+		Usage       time.Duration
+		Err         error
+		At          time.Duration
+		Expectation *api.ResourceStatus
+		Error       bool
+	}{
+		{Desc: "two CPUs", Usage: 12 * time.Second, At: time.Second, Expectation: &api.ResourceStatus{Used: 2000, Limit: 4000}},
+		{Desc: "half a CPU", Usage: 13 * time.Second, At: 3 * time.Second, Expectation: &api.ResourceStatus{Used: 500, Limit: 4000}},
+		{Desc: "error", Err: errors.New("cpu.stat missing"), At: 4 * time.Second, Error: true},
+		{Desc: "recovered", Usage: 13*time.Second + 100*time.Millisecond, At: 5 * time.Second, Expectation: &api.ResourceStatus{Used: 50, Limit: 4000}},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			acct.used, acct.err = test.Usage, test.Err
+			sampler.sample(start.Add(test.At))
+
+			act, err := sampler.status(start.Add(test.At))
+			if test.Error {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expectation, act, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected cpu status (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCalcSeverity(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Percentage  int64
+		Pressure    *api.ResourcePressure
+		Expectation api.ResourceStatusSeverity
+	}{
+		{Desc: "normal usage", Percentage: 60, Expectation: api.ResourceStatusSeverity_normal},
+		{Desc: "high usage", Percentage: 85, Expectation: api.ResourceStatusSeverity_warning},
+		{Desc: "usage at the limit", Percentage: 95, Expectation: api.ResourceStatusSeverity_danger},
+		{
+			Desc:        "short stalls",
+			Percentage:  60,
+			Pressure:    &api.ResourcePressure{SomeAvg10: 50, SomeAvg60: 5, FullAvg60: 1},
+			Expectation: api.ResourceStatusSeverity_normal,
+		},
+		{
+			Desc:        "thrashing",
+			Percentage:  60,
+			Pressure:    &api.ResourcePressure{SomeAvg60: 30},
+			Expectation: api.ResourceStatusSeverity_warning,
+		},
+		{
+			Desc:        "all tasks stalled",
+			Percentage:  60,
+			Pressure:    &api.ResourcePressure{SomeAvg60: 30, FullAvg60: 15},
+			Expectation: api.ResourceStatusSeverity_danger,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			if got := calcSeverity(test.Percentage, test.Pressure); got != test.Expectation {
+				t.Errorf("unexpected severity: want %v, got %v", test.Expectation, got)
+			}
+		})
+	}
+}
+
+func TestPressureStatus(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	now := start.Add(60 * time.Second)
+	// memory stalls some tasks 300ms per second during the last 10 seconds
+	totalsAt := func(i int) pressureTotals {
+		var memory uint64
+		if i > 50 {
+			memory = uint64(i-50) * 300000
+		}
+		return pressureTotals{memory: stallTotals{some: memory}}
+	}
+
+	tests := []struct {
+		Desc string
+		// First is the second of the first sample in the history, -1 for no samples
+		First       int
+		Expectation *api.ResourcePressure
+	}{
+		{
+			Desc:        "no history",
+			First:       -1,
+			Expectation: &api.ResourcePressure{SomeTotal: 3000000},
+		},
+		{
+			Desc:        "short history",
+			First:       55,
+			Expectation: &api.ResourcePressure{SomeTotal: 3000000, SomeAvg10: 30, SomeAvg60: 30},
+		},
+		{
+			Desc:        "full history",
+			First:       0,
+			Expectation: &api.ResourcePressure{SomeTotal: 3000000, SomeAvg10: 30, SomeAvg60: 5},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			is := &SystemService{}
+			history := is.resourceHistory()
+			for i := test.First; i >= 0 && i < 60; i++ {
+				totals := totalsAt(i)
+				history.add(resourceSample{time: start.Add(time.Duration(i) * time.Second), pressure: &totals})
+			}
+
+			cpu, memory, _ := is.pressureStatus(now, totalsAt(60))
+			if diff := cmp.Diff(test.Expectation, memory, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected memory pressure (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(&api.ResourcePressure{}, cpu, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected cpu pressure (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiskUsage(t *testing.T) {
+	dir := t.TempDir()
+	disk, err := getDiskUsage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		t.Fatal(err)
+	}
+	if disk.Used <= 0 || disk.Used > disk.Limit {
+		t.Errorf("used disk space %d is not within the disk size %d", disk.Used, disk.Limit)
+	}
+	if diff := cmp.Diff(disk.Limit, disk.Used+int64(stat.Bavail)*int64(stat.Bsize)); diff != "" {
+		t.Errorf("used and available disk space don't add up to the disk size (-want +got):\n%s", diff)
+	}
+}
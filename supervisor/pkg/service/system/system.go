@@ -3,13 +3,22 @@ package system
 import (
 	"supervisor/api"
 	"supervisor/pkg/config"
+	"sync"
 
 	"google.golang.org/grpc"
 )
 
 type SystemService struct {
-	Cfg *config.Config
+	// ConfigProvider returns the current configuration, which changes when it is reloaded.
+	ConfigProvider func() *config.Config
+	// GroupsProvider returns the usage of the cgroups the editor, tasks and terminals run in.
+	// If nil, no groups are reported.
+	GroupsProvider func() ([]*api.ResourceGroupStatus, error)
 	api.SystemServiceServer
+
+	samplingOnce sync.Once
+	cpu          *cpuSampler
+	history      *resourceHistory
 }
 
 // RegisterGRPC registers the gRPC info service.
@@ -0,0 +1,53 @@
+package supervisor
+
+import (
+	"common/util"
+	"context"
+	"crypto/subtle"
+	"strings"
+	"supervisor/api"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ownerAuth requires the owner token in the metadata of every API call. Calls of the terminal
+// service are left to it, since it also grants access to shared terminals with share tokens.
+type ownerAuth struct {
+	token string
+}
+
+// authorize checks that a call of method passed the owner token.
+func (a *ownerAuth) authorize(ctx context.Context, method string) error {
+	if strings.HasPrefix(method, "/"+api.TerminalService_ServiceDesc.ServiceName+"/") {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(util.OwnerTokenMetadataKey)
+	if len(values) == 0 || subtle.ConstantTimeCompare([]byte(values[0]), []byte(a.token)) != 1 {
+		return status.Error(codes.Unauthenticated, "owner token required")
+	}
+	return nil
+}
+
+// unaryInterceptor rejects calls without the owner token.
+func (a *ownerAuth) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamInterceptor rejects streams without the owner token.
+func (a *ownerAuth) streamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
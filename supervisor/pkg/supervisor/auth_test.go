@@ -0,0 +1,42 @@
+package supervisor
+
+import (
+	"common/util"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestOwnerAuth(t *testing.T) {
+	auth := &ownerAuth{token: "owner"}
+	tests := []struct {
+		Desc        string
+		Method      string
+		Token       string
+		Expectation codes.Code
+	}{
+		{Desc: "owner", Method: "/supervisor.EditorService/Restart", Token: "owner", Expectation: codes.OK},
+		{Desc: "no token", Method: "/supervisor.EditorService/Restart", Expectation: codes.Unauthenticated},
+		{Desc: "wrong token", Method: "/supervisor.ClipboardService/Get", Token: "share", Expectation: codes.Unauthenticated},
+		{Desc: "terminal service", Method: "/supervisor.TerminalService/Listen", Token: "share", Expectation: codes.OK},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			ctx := context.Background()
+			if test.Token != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(util.OwnerTokenMetadataKey, test.Token))
+			}
+			_, err := auth.unaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: test.Method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			})
+			if diff := cmp.Diff(test.Expectation, status.Code(err)); diff != "" {
+				t.Errorf("unexpected status code (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
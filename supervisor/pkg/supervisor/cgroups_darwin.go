@@ -0,0 +1,37 @@
+package supervisor
+
+import (
+	"common/log"
+	"os/exec"
+	"supervisor/api"
+	"supervisor/pkg/config"
+)
+
+// workspaceCgroups is not supported on Darwin.
+type workspaceCgroups struct{}
+
+// setupCgroups is not supported on Darwin, all processes run in the workspace.
+func setupCgroups(cfg *config.Config) *workspaceCgroups {
+	if cfg.Cgroups {
+		log.Warn("cgroups are not supported on macOS")
+	}
+	return nil
+}
+
+func (c *workspaceCgroups) prepareEditor(cmd *exec.Cmd) (release func()) {
+	return func() {}
+}
+
+func (c *workspaceCgroups) prepareTerminal(cmd *exec.Cmd, annotations map[string]string) (release func()) {
+	return func() {}
+}
+
+func (c *workspaceCgroups) removeTask(name string) {}
+
+func (c *workspaceCgroups) freeze(frozen bool) error {
+	return nil
+}
+
+func (c *workspaceCgroups) usage() ([]*api.ResourceGroupStatus, error) {
+	return nil, nil
+}
@@ -0,0 +1,136 @@
+package supervisor
+
+import (
+	"common/log"
+	"math"
+	"os/exec"
+	"supervisor/api"
+	"supervisor/pkg/cgroups"
+	cgroups_v2 "supervisor/pkg/cgroups/v2"
+	"supervisor/pkg/config"
+	"supervisor/pkg/task"
+	"syscall"
+)
+
+const (
+	defaultEditorCPUWeight    = 200
+	defaultTasksCPUWeight     = 50
+	defaultTerminalsCPUWeight = 100
+)
+
+// workspaceCgroups runs the editor, tasks and terminals in dedicated sub-groups of the workspace cgroup.
+type workspaceCgroups struct {
+	groups *cgroups_v2.Groups
+}
+
+// setupCgroups moves supervisor into its own sub-group of the workspace cgroup and creates the
+// sub-groups of the editor, tasks and terminals. It returns nil if cgroups are disabled or cannot
+// be set up, in which case all processes keep running in the workspace cgroup.
+func setupCgroups(cfg *config.Config) *workspaceCgroups {
+	if !cfg.Cgroups {
+		return nil
+	}
+	if unified, err := cgroups.IsUnifiedCgroupSetup(); err != nil || !unified {
+		log.WithError(err).Warn("cgroups require cgroup v2, running all processes in the workspace cgroup")
+		return nil
+	}
+	path, err := cgroups_v2.CurrentGroup("/proc/self/cgroup")
+	if err != nil {
+		log.WithError(err).Warn("cannot determine the workspace cgroup, running all processes in it")
+		return nil
+	}
+
+	const mib = 1 << 20
+	groups := cgroups_v2.NewGroups(cgroups.DefaultMountPoint, path, map[string]cgroups_v2.GroupLimits{
+		cgroups_v2.GroupEditor: {
+			CPUWeight:  defaultIfZero(cfg.EditorCPUWeight, defaultEditorCPUWeight),
+			MemoryHigh: cfg.EditorMemoryHigh * mib,
+		},
+		cgroups_v2.GroupTasks: {
+			CPUWeight:  defaultIfZero(cfg.TasksCPUWeight, defaultTasksCPUWeight),
+			MemoryHigh: cfg.TasksMemoryHigh * mib,
+		},
+		cgroups_v2.GroupTerminals: {
+			CPUWeight:  defaultIfZero(cfg.TerminalsCPUWeight, defaultTerminalsCPUWeight),
+			MemoryHigh: cfg.TerminalsMemoryHigh * mib,
+		},
+	})
+	if err := groups.Setup(); err != nil {
+		log.WithError(err).WithField("cgroup", path).Warn("cannot set up cgroups, running all processes in the workspace cgroup")
+		return nil
+	}
+	log.WithField("cgroup", path).Info("running the editor, tasks and terminals in dedicated cgroups")
+	return &workspaceCgroups{groups: groups}
+}
+
+// prepareEditor makes an editor process start in the editor group.
+func (c *workspaceCgroups) prepareEditor(cmd *exec.Cmd) (release func()) {
+	return c.prepare(cgroups_v2.GroupEditor, cmd)
+}
+
+// prepareTerminal makes the process of a terminal start in the group of its task, or the terminals group.
+func (c *workspaceCgroups) prepareTerminal(cmd *exec.Cmd, annotations map[string]string) (release func()) {
+	if name := annotations[task.Annotation]; name != "" {
+		return c.prepare(cgroups_v2.TaskGroup(name), cmd)
+	}
+	return c.prepare(cgroups_v2.GroupTerminals, cmd)
+}
+
+// removeTask removes the group of a stopped task. It is kept if processes of the task still run.
+func (c *workspaceCgroups) removeTask(name string) {
+	group := cgroups_v2.TaskGroup(name)
+	if err := c.groups.Remove(group); err != nil {
+		log.WithError(err).WithField("cgroup", group).Warn("cannot remove the cgroup of a stopped task")
+	}
+}
+
+// prepare makes cmd start directly in group, so that no child it forks early escapes the group.
+// The process starts in the workspace cgroup if the group cannot be opened.
+func (c *workspaceCgroups) prepare(group string, cmd *exec.Cmd) (release func()) {
+	dir, err := c.groups.Open(group)
+	if err != nil {
+		log.WithError(err).WithField("cgroup", group).WithField("cmd", cmd.Path).Warn("cannot start process in cgroup")
+		return func() {}
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(dir.Fd())
+	return func() { _ = dir.Close() }
+}
+
+// freeze freezes the processes of the tasks and terminals if frozen is true, and thaws them otherwise.
+// The editor keeps running.
+func (c *workspaceCgroups) freeze(frozen bool) error {
+	return c.groups.Freeze(frozen, cgroups_v2.GroupTasks, cgroups_v2.GroupTerminals)
+}
+
+// usage returns the resource usage of the groups.
+func (c *workspaceCgroups) usage() ([]*api.ResourceGroupStatus, error) {
+	usage, err := c.groups.Usage()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*api.ResourceGroupStatus, 0, len(usage))
+	for _, group := range usage {
+		status := &api.ResourceGroupStatus{
+			Name:      group.Name,
+			Memory:    &api.ResourceStatus{Used: int64(group.Memory)},
+			CpuUsage:  group.CPUUsage,
+			CpuWeight: group.CPUWeight,
+		}
+		if group.MemoryHigh != math.MaxUint64 {
+			status.Memory.Limit = int64(group.MemoryHigh)
+		}
+		res = append(res, status)
+	}
+	return res, nil
+}
+
+func defaultIfZero(value, fallback uint64) uint64 {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,140 @@
+package supervisor
+
+import (
+	"common/log"
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// hibernateCheckInterval is the interval at which the workspace is checked for being idle
+const hibernateCheckInterval = 10 * time.Second
+
+// hibernator freezes the processes of the tasks and terminals once the workspace has been idle,
+// i.e. supervisor received no API calls, stream messages or connections and no terminal wrote
+// output, and thaws them on the next API call, stream message or connection. Streams which are merely open,
+// e.g. of an IDE left in a background tab, don't keep the workspace from hibernating.
+type hibernator struct {
+	idleTimeout time.Duration
+	// freeze freezes the processes if frozen is true and thaws them otherwise
+	freeze func(frozen bool) error
+
+	mu           sync.Mutex
+	lastActivity time.Time
+	frozen       bool
+}
+
+func newHibernator(idleTimeout time.Duration, freeze func(frozen bool) error) *hibernator {
+	return &hibernator{
+		idleTimeout:  idleTimeout,
+		freeze:       freeze,
+		lastActivity: time.Now(),
+	}
+}
+
+// activity records that the workspace is in use and thaws it if it is frozen.
+func (h *hibernator) activity() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastActivity = time.Now()
+	if !h.frozen {
+		return
+	}
+	if err := h.freeze(false); err != nil {
+		log.WithError(err).Error("cannot thaw workspace")
+		return
+	}
+	h.frozen = false
+	log.Info("workspace is in use again, thawed tasks and terminals")
+}
+
+// hibernateIfIdle freezes the workspace if it has been idle for the idle timeout.
+func (h *hibernator) hibernateIfIdle() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.frozen || time.Since(h.lastActivity) < h.idleTimeout {
+		return
+	}
+	if err := h.freeze(true); err != nil {
+		log.WithError(err).Error("cannot freeze idle workspace")
+		// don't retry on every check
+		h.lastActivity = time.Now()
+		return
+	}
+	h.frozen = true
+	log.WithField("idle", h.idleTimeout).Info("workspace is idle, froze tasks and terminals")
+}
+
+// run checks whether the workspace is idle every interval until ctx is cancelled.
+// A frozen workspace is thawed once ctx is cancelled, s.t. its processes can be stopped.
+func (h *hibernator) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			h.activity()
+			return
+		case <-ticker.C:
+			h.hibernateIfIdle()
+		}
+	}
+}
+
+// unaryInterceptor thaws the workspace before every API call.
+func (h *hibernator) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		h.activity()
+		return handler(ctx, req)
+	}
+}
+
+// streamInterceptor thaws the workspace when a stream is opened and records every message
+// the client sends on it as activity. Messages supervisor sends don't count, since e.g.
+// status updates are sent periodically while nobody uses the workspace.
+func (h *hibernator) streamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		h.activity()
+		return handler(srv, &activityStream{ServerStream: ss, activity: h.activity})
+	}
+}
+
+// activityStream calls activity for every message received on the stream.
+type activityStream struct {
+	grpc.ServerStream
+	activity func()
+}
+
+func (s *activityStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.activity()
+	}
+	return err
+}
+
+// listener thaws the workspace when l accepts a connection. The calls and stream messages
+// of the connection are recorded by the interceptors, reads of the connection itself don't
+// count since clients e.g. acknowledge the messages of idle streams. Only the API listener is
+// wrapped, supervisor doesn't serve the ports of the editor or of tasks itself.
+func (h *hibernator) listener(l net.Listener) net.Listener {
+	return &activityListener{Listener: l, activity: h.activity}
+}
+
+// activityListener calls activity for every accepted connection.
+type activityListener struct {
+	net.Listener
+	activity func()
+}
+
+func (l *activityListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	l.activity()
+	return conn, nil
+}
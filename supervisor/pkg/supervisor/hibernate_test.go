@@ -0,0 +1,167 @@
+package supervisor
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+)
+
+func TestHibernator(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls []bool
+	)
+	frozen := func() []bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]bool(nil), calls...)
+	}
+	h := newHibernator(50*time.Millisecond, func(frozen bool) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, frozen)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		h.run(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+
+	// API calls keep the workspace from hibernating
+	interceptor := h.unaryInterceptor()
+	for i := 0; i < 10; i++ {
+		_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		time.Sleep(10 * time.Millisecond)
+	}
+	if diff := cmp.Diff([]bool(nil), frozen()); diff != "" {
+		t.Fatalf("workspace in use was frozen (-want +got):\n%s", diff)
+	}
+
+	// an idle workspace is frozen once
+	time.Sleep(150 * time.Millisecond)
+	if diff := cmp.Diff([]bool{true}, frozen()); diff != "" {
+		t.Fatalf("unexpected freezes (-want +got):\n%s", diff)
+	}
+
+	// the next API call thaws it before it is handled
+	_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if diff := cmp.Diff([]bool{true, false}, frozen()); diff != "" {
+			t.Errorf("workspace was not thawed before the call (-want +got):\n%s", diff)
+		}
+		return nil, nil
+	})
+
+	// shutting down thaws a frozen workspace
+	time.Sleep(150 * time.Millisecond)
+	cancel()
+	<-done
+	if diff := cmp.Diff([]bool{true, false, true, false}, frozen()); diff != "" {
+		t.Errorf("unexpected freezes (-want +got):\n%s", diff)
+	}
+}
+
+func TestHibernatorActivity(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		frozen bool
+	)
+	isFrozen := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return frozen
+	}
+	h := newHibernator(50*time.Millisecond, func(f bool) error {
+		mu.Lock()
+		defer mu.Unlock()
+		frozen = f
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.run(ctx, 10*time.Millisecond)
+
+	// an open stream doesn't keep the workspace from hibernating, its messages do
+	stream := &testServerStream{msgs: make(chan struct{})}
+	received := make(chan struct{})
+	streamDone := make(chan struct{})
+	go func() {
+		defer close(streamDone)
+		_ = h.streamInterceptor()(nil, stream, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+			for {
+				if err := stream.RecvMsg(nil); err != nil {
+					return nil
+				}
+				received <- struct{}{}
+			}
+		})
+	}()
+	defer func() {
+		close(stream.msgs)
+		<-streamDone
+	}()
+	time.Sleep(150 * time.Millisecond)
+	if !isFrozen() {
+		t.Fatal("idle workspace with an open stream was not frozen")
+	}
+	for i := 0; i < 10; i++ {
+		stream.msgs <- struct{}{}
+		<-received
+		if isFrozen() {
+			t.Fatal("workspace was not thawed by a stream message")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if isFrozen() {
+		t.Error("workspace with stream messages was frozen")
+	}
+
+	// connections thaw the workspace
+	time.Sleep(150 * time.Millisecond)
+	if !isFrozen() {
+		t.Fatal("idle workspace was not frozen")
+	}
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	l = h.listener(l)
+	client, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if isFrozen() {
+		t.Fatal("workspace was not thawed by a connection")
+	}
+}
+
+// testServerStream receives a message for every value of msgs until it is closed.
+type testServerStream struct {
+	grpc.ServerStream
+	msgs chan struct{}
+}
+
+func (s *testServerStream) RecvMsg(m interface{}) error {
+	if _, ok := <-s.msgs; !ok {
+		return io.EOF
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package supervisor
+
+import (
+	"common/log"
+	"context"
+	"supervisor/pkg/config"
+	"supervisor/pkg/editor"
+	"supervisor/pkg/motd"
+	"supervisor/pkg/service/system"
+	"supervisor/pkg/task"
+	"time"
+)
+
+// motdResourcesTimeout bounds the time spent collecting resources for the welcome banner.
+const motdResourcesTimeout = time.Second
+
+// workspaceBanner renders the welcome banner of new terminals from the current workspace status.
+func workspaceBanner(cfg *config.Config, ideReady *editor.ReadyState, systemSrv *system.SystemService, tasks *task.Manager) string {
+	status := motd.Status{
+		WorkspaceURL: cfg.WorkspaceUrl,
+		EditorName:   cfg.Editor.Name,
+		EditorReady:  ideReady.Get(),
+		Tasks:        []motd.TaskStatus{},
+	}
+	for _, t := range tasks.Status() {
+		state := motd.TaskRunning
+		switch t.State {
+		case task.Succeeded:
+			state = motd.TaskSucceeded
+		case task.Failed:
+			state = motd.TaskFailed
+		}
+		status.Tasks = append(status.Tasks, motd.TaskStatus{Name: t.Name, State: state})
+	}
+
+	for _, port := range motd.ListeningPorts("/proc/net/tcp", "/proc/net/tcp6") {
+		if int(port) != cfg.APIEndpointPort {
+			status.Ports = append(status.Ports, port)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), motdResourcesTimeout)
+	defer cancel()
+	resources, err := systemSrv.GetResources(ctx)
+	if err != nil {
+		log.WithError(err).Debug("cannot resolve resources for the welcome banner")
+	} else {
+		status.Resources = resources
+	}
+
+	return motd.Render(cfg.Runtime.Motd.Message, status)
+}
@@ -0,0 +1,51 @@
+package supervisor
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"supervisor/pkg/config"
+	"supervisor/pkg/editor"
+	"supervisor/pkg/service/system"
+	"supervisor/pkg/task"
+	"supervisor/pkg/terminal"
+	"testing"
+	"time"
+)
+
+func TestWorkspaceBannerTasks(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	str := func(s string) *string { return &s }
+	cfg := &config.Config{}
+	cfg.Runtime.Tasks = []config.TaskConfig{
+		{Name: str("build"), Command: str("exit 0")},
+		{Name: str("lint"), Command: str("exit 1")},
+		{Name: str("serve"), Command: str("exec cat")},
+	}
+	systemSrv := &system.SystemService{ConfigProvider: func() *config.Config { return cfg }}
+
+	mux := terminal.NewMux()
+	defer mux.Close(ctx)
+	tasks := task.NewManager(cfg, mux)
+	mux.OnExit = tasks.Exited
+	for _, tc := range cfg.Runtime.Tasks {
+		_, err := mux.Start(exec.Command("/bin/sh", "-c", *tc.Command), terminal.TermOptions{
+			Annotations: map[string]string{task.Annotation: *tc.Name},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var banner string
+	for !strings.Contains(banner, "1 running, 1 done, \x1b[31m1 failed (lint)") {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("banner does not show the task states: %q", banner)
+		case <-time.After(10 * time.Millisecond):
+		}
+		banner = workspaceBanner(cfg, editor.NewEditorReadyState(), systemSrv, tasks)
+	}
+}
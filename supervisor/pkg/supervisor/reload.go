@@ -0,0 +1,49 @@
+package supervisor
+
+import (
+	"common/log"
+	"context"
+	"supervisor/pkg/config"
+	"supervisor/pkg/editor"
+	"supervisor/pkg/task"
+)
+
+// applyConfigChanges applies the changes of a reloaded configuration to the running workspace.
+// Terminal profiles, the runtime environment and the welcome banner are read from the
+// configuration store whenever a terminal is opened and need no further action.
+func applyConfigChanges(ctx context.Context, cfg *config.Config, diff config.Diff, ideReady *editor.ReadyState, ideStatus *editor.StatusState, ideControl *editor.Control, tasks *task.Manager) {
+	if len(diff.Env) > 0 {
+		log.WithField("env", diff.Env).Info("runtime environment changed, applies to new terminals")
+	}
+	if diff.Terminals {
+		log.Info("terminal profiles changed, apply to new terminals")
+	}
+	if len(diff.TasksAdded) > 0 || len(diff.TasksChanged) > 0 || len(diff.TasksRemoved) > 0 {
+		log.WithField("added", diff.TasksAdded).
+			WithField("changed", diff.TasksChanged).
+			WithField("removed", diff.TasksRemoved).
+			Info("tasks changed, stopping removed tasks")
+		tasks.Apply(ctx, cfg, diff)
+	}
+
+	if diff.Editor || diff.EditorEntrypoint {
+		ideControl.Configure(cfg)
+	}
+	if diff.EditorEntrypoint {
+		log.WithField("entrypoint", cfg.Editor.Entrypoint).
+			WithField("args", cfg.Editor.EntrypointArgs).
+			Info("editor entrypoint changed, restarting the editor")
+		go func() {
+			if err := ideControl.Restart(ctx); err != nil {
+				log.WithError(err).Warn("cannot restart editor")
+			}
+		}()
+	} else if diff.Editor {
+		log.Info("editor settings changed, apply once the editor restarts")
+	}
+
+	if len(diff.Extensions) > 0 {
+		log.WithField("extensions", diff.Extensions).Info("extensions added, installing them")
+		go editor.InstallExtensions(ctx, cfg, ideReady, ideStatus)
+	}
+}
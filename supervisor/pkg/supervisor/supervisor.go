@@ -2,21 +2,29 @@ package supervisor
 
 import (
 	"common/log"
+	"common/util"
 	"context"
+	"crypto/rand"
 	"fmt"
 	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime/debug"
+	"strings"
 	"supervisor/pkg/config"
 	"supervisor/pkg/editor"
 	"supervisor/pkg/service"
+	"supervisor/pkg/service/clipboard"
 	"supervisor/pkg/service/pkg"
 	"supervisor/pkg/service/system"
 	"supervisor/pkg/service/utility"
+	"supervisor/pkg/task"
 	"supervisor/pkg/terminal"
+	"supervisor/pkg/variable"
 	"sync"
 	"syscall"
+	"time"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
@@ -31,6 +39,21 @@ var (
 	Version = ""
 )
 
+const (
+	// terminalStoreLocation is where terminals are persisted if they should be restored after a restart.
+	terminalStoreLocation = "/tmp/opencoder/terminals"
+	// terminalPersistInterval is the interval in which terminal snapshots are written.
+	terminalPersistInterval = 5 * time.Second
+	// terminalSpillLocation is where terminal output is spilled to if enabled.
+	terminalSpillLocation = "/tmp/opencoder/backlogs"
+	// terminalReapInterval is the interval in which terminals are checked for their idle timeout.
+	terminalReapInterval = 15 * time.Second
+	// configWatchInterval is the interval in which the configuration files are checked for changes.
+	configWatchInterval = 2 * time.Second
+	// resourceSampleInterval is the interval in which the CPU usage of the workspace is sampled.
+	resourceSampleInterval = time.Second
+)
+
 // Run serves as main entrypoint to the supervisor.
 func Run() {
 	exitCode := 0
@@ -63,30 +86,153 @@ func Run() {
 	//configureGit(cfg)
 
 	ctx, cancel := context.WithCancel(context.Background())
+	cfgStore := config.NewStore(cfg)
+
+	// Run the editor, tasks and terminals in dedicated cgroups
+	workspaceCgroups := setupCgroups(cfg)
+
+	// Freeze the tasks and terminals while the workspace is idle
+	var hibernate *hibernator
+	if cfg.HibernateIdleTimeout > 0 {
+		if workspaceCgroups != nil {
+			hibernate = newHibernator(time.Duration(cfg.HibernateIdleTimeout)*time.Second, workspaceCgroups.freeze)
+			go hibernate.run(ctx, hibernateCheckInterval)
+		} else {
+			log.Warn("hibernation requires cgroups, the workspace keeps running while idle")
+		}
+	}
+
+	// Identify the workspace owner to the API, the editor and terminals get the token in their environment
+	ownerToken := rand.Text()
+	if err := writeOwnerToken(util.GetOwnerTokenLocation(), ownerToken); err != nil {
+		log.WithError(err).Error("cannot write the owner token, only the editor and terminals can pass it")
+	}
+	if err := os.Setenv(util.OwnerTokenEnv, ownerToken); err != nil {
+		log.WithError(err).Error("cannot pass the owner token to the editor and terminals")
+	}
+	var auth *ownerAuth
+	if cfg.OwnerAuth {
+		auth = &ownerAuth{token: ownerToken}
+	}
 
 	// Start editor
 	var ideWG sync.WaitGroup
 	var ideReady = editor.NewEditorReadyState()
+	var ideStatus = editor.NewEditorStatusState()
+	var ideControl = editor.NewEditorControl()
+	var ideLogs = editor.NewEditorLogs(cfg)
+	defer ideLogs.Close()
+	if workspaceCgroups != nil {
+		ideControl.PrepareStart(workspaceCgroups.prepareEditor)
+	}
 	ideWG.Add(1)
-	go editor.StartAndWatchEditor(ctx, cfg, &ideWG, ideReady)
+	go editor.StartAndWatchEditor(ctx, cfg, &ideWG, ideReady, ideStatus, ideControl, ideLogs)
+	go editor.InstallExtensions(ctx, cfg, ideReady, ideStatus)
+
+	// Prepare terminal service
+	termMux := terminal.NewMux()
+	termMux.Limits = terminal.TerminalLimits{
+		MaxTerminals:     cfg.TerminalMax,
+		AnnotationKey:    cfg.TerminalLimitAnnotation,
+		MaxPerAnnotation: cfg.TerminalMaxPerAnnotation,
+	}
+	if workspaceCgroups != nil {
+		termMux.PrepareStart = workspaceCgroups.prepareTerminal
+	}
+	if hibernate != nil {
+		// terminal output means a task or terminal is still doing work
+		termMux.OnOutput = hibernate.activity
+	}
+	termSrv := terminal.NewMuxTerminalService(termMux)
+	termSrv.OwnerToken = ownerToken
+	termSrv.RequireOwnerToken = cfg.OwnerAuth
+	if cfg.WorkspaceLocation != "" {
+		termSrv.DefaultWorkdir = cfg.WorkspaceLocation
+	}
+	termSrv.ProfileProvider = func(name string) (config.TerminalProfileConfig, bool) {
+		return cfgStore.Get().Runtime.TerminalProfile(name)
+	}
+	termSrv.EnvProvider = func() []string {
+		return variable.RuntimeEnv(cfgStore.Get())
+	}
+	clipboardSrv := &clipboard.ClipboardService{}
+	termSrv.OnClipboard = func(alias string, event terminal.ClipboardEvent) {
+		// the primary selection and cut buffers are not synced with clients
+		if strings.ContainsRune(event.Selection, 'c') {
+			clipboardSrv.Set(event.Content, "terminal/"+alias)
+		}
+	}
+	systemSrv := &system.SystemService{ConfigProvider: cfgStore.Get}
+	if workspaceCgroups != nil {
+		systemSrv.GroupsProvider = workspaceCgroups.usage
+	}
+	go systemSrv.SampleResources(ctx, resourceSampleInterval)
+	tasks := task.NewManager(cfg, termMux)
+	termMux.OnExit = tasks.Exited
+	if workspaceCgroups != nil {
+		tasks.OnStop = workspaceCgroups.removeTask
+	}
+	termSrv.Banner = func() string {
+		cfg := cfgStore.Get()
+		if !cfg.Runtime.Motd.Enabled {
+			return ""
+		}
+		return workspaceBanner(cfg, ideReady, systemSrv, tasks)
+	}
+	termSrv.BacklogSize = cfg.TerminalBacklogSize << 10
+	if cfg.TerminalBacklogSpill {
+		// spill files of a previous run belong to terminals which don't exist anymore
+		_ = os.RemoveAll(terminalSpillLocation)
+		termSrv.Spill = &terminal.SpillStore{
+			Dir:     terminalSpillLocation,
+			MaxSize: cfg.TerminalBacklogDiskLimit << 20,
+		}
+	}
+	if cfg.RestoreTerminals {
+		store := &terminal.TerminalStore{Dir: terminalStoreLocation}
+		restored, err := termSrv.RestoreTerminals(store)
+		if err != nil {
+			log.WithError(err).Warn("cannot restore terminals")
+		} else if restored > 0 {
+			log.WithField("count", restored).Info("restored terminals")
+		}
+		go termSrv.PersistTerminals(ctx, store, terminalPersistInterval)
+	}
+	go termMux.ReapIdleTerminals(ctx, terminalReapInterval)
 
 	//
 	var wg sync.WaitGroup
 	wg.Add(1)
 	services := []service.RegisterableService{
-		&system.SystemService{Cfg: cfg},
+		systemSrv,
+		&editor.EditorService{Cfg: cfg, Ready: ideReady, Status: ideStatus, Control: ideControl, Output: ideLogs},
 		&utility.UtilityService{},
-		&terminal.MuxTerminalService{},
+		termSrv,
+		clipboardSrv,
 		&pkg.PackageService{},
 	}
-	services = append(services)
-	go startGrpcEndpoint(ctx, cfg, &wg, services)
 
-	// to shutdown
+	go startGrpcEndpoint(ctx, cfg, &wg, services, auth, hibernate)
+
+	// Apply changes of the editor and runtime configuration
+	cfgStore.OnReload(func(cfg *config.Config, diff config.Diff) {
+		applyConfigChanges(ctx, cfg, diff, ideReady, ideStatus, ideControl, tasks)
+	})
+	go cfgStore.Watch(ctx, configWatchInterval)
+
+	// to shutdown, SIGHUP reloads the configuration
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	select {
-	case <-sigChan:
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range sigChan {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		diff, err := cfgStore.Reload()
+		if err != nil {
+			log.WithError(err).Warn("cannot reload configuration on SIGHUP, keeping the current one")
+			continue
+		}
+		log.WithField("changes", diff.String()).Info("reloaded configuration on SIGHUP")
 	}
 
 	log.Info("received SIGTERM (or shutdown) - tearing down")
@@ -96,7 +242,9 @@ func Run() {
 	wg.Wait()
 }
 
-func startGrpcEndpoint(ctx context.Context, cfg *config.Config, wg *sync.WaitGroup, services []service.RegisterableService) {
+// startGrpcEndpoint serves the API of services. Calls require the owner token if auth is not nil,
+// and thaw the workspace if hibernate is not nil.
+func startGrpcEndpoint(ctx context.Context, cfg *config.Config, wg *sync.WaitGroup, services []service.RegisterableService, auth *ownerAuth, hibernate *hibernator) {
 	defer wg.Done()
 	defer log.Debug("startGrpcEndpoint shutdown")
 
@@ -110,6 +258,19 @@ func startGrpcEndpoint(ctx context.Context, cfg *config.Config, wg *sync.WaitGro
 	var unaryInterceptors []grpc.UnaryServerInterceptor
 	var streamInterceptors []grpc.StreamServerInterceptor
 
+	// Reject calls without the owner token before they thaw the workspace
+	if auth != nil {
+		unaryInterceptors = append(unaryInterceptors, auth.unaryInterceptor())
+		streamInterceptors = append(streamInterceptors, auth.streamInterceptor())
+	}
+
+	// Thaw the workspace before the call is handled, stream messages and connections keep it active
+	if hibernate != nil {
+		l = hibernate.listener(l)
+		unaryInterceptors = append(unaryInterceptors, hibernate.unaryInterceptor())
+		streamInterceptors = append(streamInterceptors, hibernate.streamInterceptor())
+	}
+
 	//if cfg.DebugEnable {
 	unaryInterceptors = append(unaryInterceptors, grpc_logrus.UnaryServerInterceptor(log.Log))
 	streamInterceptors = append(streamInterceptors, grpc_logrus.StreamServerInterceptor(log.Log))
@@ -160,6 +321,28 @@ func startGrpcEndpoint(ctx context.Context, cfg *config.Config, wg *sync.WaitGro
 	grpcServer.GracefulStop()
 }
 
+// writeOwnerToken writes the token identifying the workspace owner to a file only the
+// workspace user can read, s.t. clients running in the workspace can pass it.
+func writeOwnerToken(path, token string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".token-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(token)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	// replace the file instead of writing to it, s.t. a file created by someone else is not reused
+	return os.Rename(tmp.Name(), path)
+}
+
 func handleExit(ec *int) {
 	exitCode := *ec
 	log.WithField("exitCode", exitCode).Debug("supervisor exit")
@@ -1,32 +1,145 @@
 package task
 
 import (
+	"common/log"
+	"context"
 	"supervisor/pkg/config"
 	"supervisor/pkg/terminal"
+	"sync"
 )
 
-type tasksManager struct {
-	config          *config.Config
-	storeLocation   string
-	tasks           []*task
-	terminalService *terminal.MuxTerminalService
+// Annotation is the annotation of terminals which run a task, its value is the name of the task.
+const Annotation = terminal.TaskAnnotation
+
+// State is the state of a task.
+type State int
+
+const (
+	Running State = iota
+	Succeeded
+	Failed
+)
+
+// Status describes a task of the runtime configuration.
+type Status struct {
+	Name  string
+	State State
+	// ExitCode is the exit code of a task which has stopped
+	ExitCode int
+}
+
+// Manager tracks the state of the tasks of the runtime configuration. Tasks run in the
+// terminals clients open for them with Annotation, supervisor doesn't start them itself.
+type Manager struct {
+	mux *terminal.Mux
+
+	// OnStop is called with the name of a removed task once its terminals have been closed,
+	// e.g. to clean up its resources.
+	OnStop func(name string)
+
+	mu sync.Mutex
+	// names are the names of the configured tasks in configuration order
+	names []string
+	// exitCodes are the exit codes of the tasks whose terminal exited last
+	exitCodes map[string]int
 }
 
-type taskSuccess string
+// NewManager creates a manager of the tasks of cfg, which run in terminals of mux.
+func NewManager(cfg *config.Config, mux *terminal.Mux) *Manager {
+	m := &Manager{
+		mux:       mux,
+		exitCodes: make(map[string]int),
+	}
+	m.names = taskNames(cfg)
+	return m
+}
+
+// Exited records the exit code of a task terminal. It is meant to be set as Mux.OnExit.
+func (m *Manager) Exited(alias string, annotations map[string]string, exitCode int) {
+	name, ok := annotations[Annotation]
+	if !ok {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.configured(name) {
+		return
+	}
+	m.exitCodes[name] = exitCode
+	log.WithField("task", name).WithField("alias", alias).WithField("exitCode", exitCode).Info("task exited")
+}
 
-type task struct {
-	//api.TaskStatus
-	config      config.TaskConfig
-	command     string
-	successChan chan taskSuccess
-	title       string
-	lastOutput  string
+// Apply updates the tasks to the configuration cfg, which diff led to. The terminals of removed
+// tasks are closed, and the results of changed tasks are dropped since they ran the previous
+// configuration. Added and changed tasks show up once a terminal runs them.
+func (m *Manager) Apply(ctx context.Context, cfg *config.Config, diff config.Diff) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, name := range diff.TasksRemoved {
+		m.stop(ctx, name)
+	}
+	for _, name := range diff.TasksChanged {
+		delete(m.exitCodes, name)
+	}
+	m.names = taskNames(cfg)
+}
+
+// Status returns the status of the tasks which run or have run, in configuration order.
+func (m *Manager) Status() []Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := make([]Status, 0, len(m.names))
+	for _, name := range m.names {
+		if len(m.mux.Select(map[string]string{Annotation: name})) > 0 {
+			res = append(res, Status{Name: name, State: Running})
+			continue
+		}
+		exitCode, ok := m.exitCodes[name]
+		if !ok {
+			continue
+		}
+		status := Status{Name: name, State: Succeeded, ExitCode: exitCode}
+		if exitCode != 0 {
+			status.State = Failed
+		}
+		res = append(res, status)
+	}
+	return res
+}
+
+// stop closes the terminals of a task, forgets its result and calls OnStop.
+// Callers are expected to hold mu.
+func (m *Manager) stop(ctx context.Context, name string) {
+	delete(m.exitCodes, name)
+	for _, alias := range m.mux.Select(map[string]string{Annotation: name}) {
+		if err := m.mux.CloseTerminal(ctx, alias, false); err != nil && err != terminal.ErrNotFound {
+			log.WithError(err).WithField("task", name).WithField("alias", alias).Warn("cannot stop task")
+			continue
+		}
+		log.WithField("task", name).WithField("alias", alias).Info("task stopped")
+	}
+	if m.OnStop != nil {
+		m.OnStop(name)
+	}
+}
+
+// configured returns true if name is a task of the current configuration. Callers are expected to hold mu.
+func (m *Manager) configured(name string) bool {
+	for _, n := range m.names {
+		if n == name {
+			return true
+		}
+	}
+	return false
 }
 
-func newTasksManager(config *config.Config, terminalService *terminal.MuxTerminalService) *tasksManager {
-	return &tasksManager{
-		config:          config,
-		storeLocation:   "/tmp/.opencoder",
-		terminalService: terminalService,
+func taskNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Runtime.Tasks))
+	for i, tc := range cfg.Runtime.Tasks {
+		names = append(names, config.TaskKey(i, tc))
 	}
+	return names
 }
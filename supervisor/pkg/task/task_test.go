@@ -0,0 +1,87 @@
+package task
+
+import (
+	"context"
+	"os/exec"
+	"supervisor/pkg/config"
+	"supervisor/pkg/terminal"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestManager(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	str := func(s string) *string { return &s }
+	newConfig := func(names ...string) *config.Config {
+		cfg := &config.Config{}
+		for _, name := range names {
+			cfg.Runtime.Tasks = append(cfg.Runtime.Tasks, config.TaskConfig{Name: str(name), Command: str("true")})
+		}
+		return cfg
+	}
+
+	mux := terminal.NewMux()
+	defer mux.Close(ctx)
+	cfg := newConfig("build", "lint", "serve", "test")
+	manager := NewManager(cfg, mux)
+	mux.OnExit = manager.Exited
+	var stopped []string
+	manager.OnStop = func(name string) { stopped = append(stopped, name) }
+
+	run := func(name, script string) {
+		t.Helper()
+		_, err := mux.Start(exec.Command("/bin/sh", "-c", script), terminal.TermOptions{
+			Annotations: map[string]string{Annotation: name},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor := func(expectation []Status) {
+		t.Helper()
+		var status []Status
+		for {
+			status = manager.Status()
+			if cmp.Diff(expectation, status) == "" {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				t.Fatalf("unexpected task status (-want +got):\n%s", cmp.Diff(expectation, status))
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}
+
+	run("build", "exit 0")
+	run("lint", "exit 3")
+	run("serve", "exec cat")
+	run("unknown", "exit 1")
+	waitFor([]Status{
+		{Name: "build", State: Succeeded},
+		{Name: "lint", State: Failed, ExitCode: 3},
+		{Name: "serve", State: Running},
+	})
+
+	// a removed task is stopped, the result of a changed one dropped
+	reloaded := newConfig("build", "lint", "test")
+	reloaded.Runtime.Tasks[1].Command = str("false")
+	diff := config.Compare(cfg, reloaded)
+	if diff := cmp.Diff([]string{"lint"}, diff.TasksChanged); diff != "" {
+		t.Fatalf("unexpected changed tasks (-want +got):\n%s", diff)
+	}
+	manager.Apply(ctx, reloaded, diff)
+	waitFor([]Status{
+		{Name: "build", State: Succeeded},
+	})
+	if aliases := mux.Select(map[string]string{Annotation: "serve"}); len(aliases) > 0 {
+		t.Errorf("terminals of a removed task still exist: %v", aliases)
+	}
+	if diff := cmp.Diff([]string{"serve"}, stopped); diff != "" {
+		t.Errorf("unexpected stopped tasks (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,301 @@
+package terminal
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+const (
+	asciiESC = 0x1b
+	asciiBEL = 0x07
+)
+
+// ansiToken is a single piece of terminal output: either printable text
+// or a complete escape sequence.
+type ansiToken struct {
+	// text is set for printable output
+	text []byte
+	// pos is the index of the token in the tokenized output
+	pos int
+	// csi holds the parameters of a CSI sequence, final is its final byte
+	csi   []byte
+	final byte
+	// escape is true for any escape sequence, including CSI
+	escape bool
+}
+
+// tokenizeANSI splits terminal output into printable text and escape sequences.
+// Incomplete sequences at the end of the input are treated as escape sequences.
+func tokenizeANSI(b []byte, fn func(tok ansiToken)) {
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			fn(ansiToken{text: b[start:end], pos: start})
+		}
+	}
+	for i := 0; i < len(b); {
+		if b[i] != asciiESC {
+			i++
+			continue
+		}
+		flush(i)
+
+		end := skipEscape(b, i)
+		tok := ansiToken{escape: true, pos: i}
+		if i+1 < len(b) && b[i+1] == '[' && end-1 > i+1 {
+			tok.csi = b[i+2 : end-1]
+			tok.final = b[end-1]
+		}
+		fn(tok)
+
+		i = end
+		start = end
+	}
+	flush(len(b))
+}
+
+// skipEscape returns the index right after the escape sequence starting at b[i].
+func skipEscape(b []byte, i int) int {
+	if i+1 >= len(b) {
+		return len(b)
+	}
+	switch b[i+1] {
+	case '[':
+		// CSI: parameters and intermediates followed by a final byte in 0x40-0x7E
+		for j := i + 2; j < len(b); j++ {
+			if b[j] >= 0x40 && b[j] <= 0x7e {
+				return j + 1
+			}
+		}
+		return len(b)
+	case ']', 'P', 'X', '^', '_':
+		// OSC, DCS, SOS, PM and APC are terminated by BEL or ST (ESC \)
+		for j := i + 2; j < len(b); j++ {
+			if b[j] == asciiBEL {
+				return j + 1
+			}
+			if b[j] == asciiESC && j+1 < len(b) && b[j+1] == '\\' {
+				return j + 2
+			}
+		}
+		return len(b)
+	case '(', ')', '*', '+', '#', '%':
+		// character set designation carries one more byte
+		return min(i+3, len(b))
+	default:
+		return i + 2
+	}
+}
+
+// StripANSI removes escape sequences and control characters other than
+// newline and tab from terminal output. Carriage returns are dropped so that
+// CRLF line endings become plain newlines.
+func StripANSI(b []byte) []byte {
+	res, _ := stripANSI(b, false)
+	return res
+}
+
+// stripANSI works like StripANSI. If withOffsets is set, it also returns
+// the index in b of every byte of the stripped output.
+func stripANSI(b []byte, withOffsets bool) (res []byte, offsets []int) {
+	res = make([]byte, 0, len(b))
+	if withOffsets {
+		offsets = make([]int, 0, len(b))
+	}
+	tokenizeANSI(b, func(tok ansiToken) {
+		for i, c := range tok.text {
+			if (c < 0x20 && c != '\n' && c != '\t') || c == 0x7f {
+				continue
+			}
+			res = append(res, c)
+			if withOffsets {
+				offsets = append(offsets, tok.pos+i)
+			}
+		}
+	})
+	return res, offsets
+}
+
+// ansiStyle is the graphic rendition state of the terminal.
+type ansiStyle struct {
+	fg, bg                   string
+	bold, italic, underline  bool
+	dim, inverse, crossedOut bool
+}
+
+func (s ansiStyle) css() string {
+	fg, bg := s.fg, s.bg
+	if s.inverse {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = "#000"
+		}
+		if bg == "" {
+			bg = "#ccc"
+		}
+	}
+
+	var decls []string
+	if fg != "" {
+		decls = append(decls, "color:"+fg)
+	}
+	if bg != "" {
+		decls = append(decls, "background-color:"+bg)
+	}
+	if s.bold {
+		decls = append(decls, "font-weight:bold")
+	}
+	if s.dim {
+		decls = append(decls, "opacity:0.7")
+	}
+	if s.italic {
+		decls = append(decls, "font-style:italic")
+	}
+	if s.underline && s.crossedOut {
+		decls = append(decls, "text-decoration:underline line-through")
+	} else if s.underline {
+		decls = append(decls, "text-decoration:underline")
+	} else if s.crossedOut {
+		decls = append(decls, "text-decoration:line-through")
+	}
+	return strings.Join(decls, ";")
+}
+
+// ansiPalette are the 16 standard terminal colors (xterm defaults).
+var ansiPalette = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// ansi256Color returns the CSS color for an index of the xterm 256 color palette.
+func ansi256Color(n int) string {
+	switch {
+	case n < 16:
+		return ansiPalette[n]
+	case n < 232:
+		n -= 16
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		return fmt.Sprintf("#%02x%02x%02x", level(n/36), level(n/6%6), level(n%6))
+	default:
+		g := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", g, g, g)
+	}
+}
+
+// apply updates the style using the parameters of an SGR sequence.
+func (s *ansiStyle) apply(params []byte) {
+	var codes []int
+	for _, p := range strings.FieldsFunc(string(params), func(r rune) bool { return r == ';' || r == ':' }) {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return
+		}
+		codes = append(codes, v)
+	}
+	if len(codes) == 0 {
+		codes = []int{0}
+	}
+
+	// extendedColor parses 5;n and 2;r;g;b color specifications.
+	extendedColor := func(i int) (string, int) {
+		if i+1 < len(codes) && codes[i+1] == 5 && i+2 < len(codes) && codes[i+2] >= 0 && codes[i+2] < 256 {
+			return ansi256Color(codes[i+2]), i + 2
+		}
+		if i+1 < len(codes) && codes[i+1] == 2 && i+4 < len(codes) {
+			return fmt.Sprintf("#%02x%02x%02x", codes[i+2]&0xff, codes[i+3]&0xff, codes[i+4]&0xff), i + 4
+		}
+		return "", len(codes)
+	}
+
+	for i := 0; i < len(codes); i++ {
+		c := codes[i]
+		switch {
+		case c == 0:
+			*s = ansiStyle{}
+		case c == 1:
+			s.bold = true
+		case c == 2:
+			s.dim = true
+		case c == 3:
+			s.italic = true
+		case c == 4:
+			s.underline = true
+		case c == 7:
+			s.inverse = true
+		case c == 9:
+			s.crossedOut = true
+		case c == 22:
+			s.bold, s.dim = false, false
+		case c == 23:
+			s.italic = false
+		case c == 24:
+			s.underline = false
+		case c == 27:
+			s.inverse = false
+		case c == 29:
+			s.crossedOut = false
+		case c >= 30 && c <= 37:
+			s.fg = ansiPalette[c-30]
+		case c == 38:
+			s.fg, i = extendedColor(i)
+		case c == 39:
+			s.fg = ""
+		case c >= 40 && c <= 47:
+			s.bg = ansiPalette[c-40]
+		case c == 48:
+			s.bg, i = extendedColor(i)
+		case c == 49:
+			s.bg = ""
+		case c >= 90 && c <= 97:
+			s.fg = ansiPalette[c-90+8]
+		case c >= 100 && c <= 107:
+			s.bg = ansiPalette[c-100+8]
+		}
+	}
+}
+
+// RenderHTML renders terminal output as a standalone HTML document.
+// Colors and text attributes set by SGR sequences are preserved, all other
+// escape sequences are dropped.
+func RenderHTML(b []byte, title string) []byte {
+	var (
+		res   bytes.Buffer
+		style ansiStyle
+		open  bool
+	)
+	res.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&res, "<title>%s</title>\n", html.EscapeString(title))
+	res.WriteString("<style>pre.terminal{background-color:#000;color:#e5e5e5;padding:1em;font-family:monospace;white-space:pre-wrap;}</style>\n")
+	res.WriteString("</head>\n<body>\n<pre class=\"terminal\">")
+	tokenizeANSI(b, func(tok ansiToken) {
+		if tok.escape {
+			if tok.final != 'm' {
+				return
+			}
+			style.apply(tok.csi)
+			if open {
+				res.WriteString("</span>")
+				open = false
+			}
+			if css := style.css(); css != "" {
+				fmt.Fprintf(&res, "<span style=\"%s\">", css)
+				open = true
+			}
+			return
+		}
+		res.WriteString(html.EscapeString(string(StripANSI(tok.text))))
+	})
+	if open {
+		res.WriteString("</span>")
+	}
+	res.WriteString("</pre>\n</body>\n</html>\n")
+	return res.Bytes()
+}
@@ -0,0 +1,182 @@
+package terminal
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Input       string
+		Expectation string
+	}{
+		{
+			Desc:        "plain text",
+			Input:       "hello world\r\n",
+			Expectation: "hello world\n",
+		},
+		{
+			Desc:        "colors",
+			Input:       "\x1b[1;31merror\x1b[0m: boom",
+			Expectation: "error: boom",
+		},
+		{
+			Desc:        "window title",
+			Input:       "\x1b]0;user@host: ~\x07$ ls",
+			Expectation: "$ ls",
+		},
+		{
+			Desc:        "osc terminated by st",
+			Input:       "\x1b]52;c;aGVsbG8=\x1b\\done",
+			Expectation: "done",
+		},
+		{
+			Desc:        "cursor movement and charset",
+			Input:       "\x1b[2J\x1b[H\x1b(Btop",
+			Expectation: "top",
+		},
+		{
+			Desc:        "incomplete sequence",
+			Input:       "abc\x1b[31",
+			Expectation: "abc",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			if diff := cmp.Diff(test.Expectation, string(StripANSI([]byte(test.Input)))); diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	out := string(RenderHTML([]byte("\x1b[31m<red>\x1b[0m plain \x1b[38;5;196mx\x1b[m"), "test"))
+	for _, expectation := range []string{
+		`<span style="color:#cd0000">&lt;red&gt;</span> plain `,
+		`<span style="color:#ff0000">x</span>`,
+		`<title>test</title>`,
+	} {
+		if !strings.Contains(out, expectation) {
+			t.Errorf("expected %q in output:\n%s", expectation, out)
+		}
+	}
+}
+
+func TestCastRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rec", "session.cast")
+	rec, err := newCastRecorder(path, castHeader{Width: 80, Height: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// split a multi-byte rune across two writes
+	euro := []byte("€")
+	if err := rec.Output(append([]byte("price: "), euro[:1]...)); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Output(euro[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Resize(100, 30); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 4 {
+		t.Fatalf("expected header and three events, got %d lines", len(lines))
+	}
+
+	var header castHeader
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatal(err)
+	}
+	if header.Version != 2 || header.Width != 80 || header.Height != 24 {
+		t.Errorf("unexpected header: %+v", header)
+	}
+
+	var events []string
+	for _, line := range lines[1:] {
+		var event []interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event[1].(string)+":"+event[2].(string))
+	}
+	if diff := cmp.Diff([]string{"o:price: ", "o:€", "r:100x30"}, events); diff != "" {
+		t.Errorf("unexpected events (-want +got):\n%s", diff)
+	}
+}
+
+func TestOSC52Scanner(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Writes      []string
+		Expectation []ClipboardEvent
+	}{
+		{
+			Desc:        "terminated by bel",
+			Writes:      []string{"before\x1b]52;c;aGVsbG8=\x07after"},
+			Expectation: []ClipboardEvent{{Selection: "c", Content: []byte("hello")}},
+		},
+		{
+			Desc:        "terminated by st without selection",
+			Writes:      []string{"\x1b]52;;d29ybGQ=\x1b\\"},
+			Expectation: []ClipboardEvent{{Selection: "c", Content: []byte("world")}},
+		},
+		{
+			Desc:        "split across writes",
+			Writes:      []string{"abc\x1b]5", "2;p;aGVs", "bG8=\x1b", "\\"},
+			Expectation: []ClipboardEvent{{Selection: "p", Content: []byte("hello")}},
+		},
+		{
+			Desc: "multiple sequences",
+			Writes: []string{
+				"\x1b]52;c;YQ==\x07\x1b]0;title\x07\x1b]52;c;Yg==\x07",
+			},
+			Expectation: []ClipboardEvent{{Selection: "c", Content: []byte("a")}, {Selection: "c", Content: []byte("b")}},
+		},
+		{
+			Desc:   "query is ignored",
+			Writes: []string{"\x1b]52;c;?\x07"},
+		},
+		{
+			Desc:   "invalid base64 is ignored",
+			Writes: []string{"\x1b]52;c;!!!\x07"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			var (
+				scanner osc52Scanner
+				events  []ClipboardEvent
+			)
+			for _, write := range test.Writes {
+				events = append(events, scanner.Scan([]byte(write))...)
+			}
+			if diff := cmp.Diff(test.Expectation, events); diff != "" {
+				t.Errorf("unexpected events (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package terminal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// castHeader is the first line of an asciicast v2 file.
+// See https://docs.asciinema.org/manual/asciicast/v2/
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// castRecorder writes terminal output with timing information into an asciicast v2 file.
+type castRecorder struct {
+	Path string
+
+	mu      sync.Mutex
+	file    *os.File
+	w       *bufio.Writer
+	start   time.Time
+	pending []byte
+}
+
+// newCastRecorder creates the asciicast file at path and writes its header.
+func newCastRecorder(path string, header castHeader) (*castRecorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create recording directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create recording: %w", err)
+	}
+
+	start := time.Now()
+	header.Version = 2
+	header.Timestamp = start.Unix()
+	rec := &castRecorder{
+		Path:  path,
+		file:  f,
+		w:     bufio.NewWriter(f),
+		start: start,
+	}
+	if err := writeCastLine(rec.w, header); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return rec, rec.w.Flush()
+}
+
+// Output records terminal output. Incomplete UTF-8 sequences at the end of p
+// are held back until the next call, as asciicast events must be valid UTF-8.
+func (r *castRecorder) Output(p []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data := append(r.pending, p...)
+	cut := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	r.pending = append([]byte(nil), data[cut:]...)
+	if cut == 0 {
+		return nil
+	}
+	return r.event("o", string(data[:cut]))
+}
+
+// Resize records a change of the terminal size.
+func (r *castRecorder) Resize(cols, rows uint16) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.event("r", fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// event writes a single event line. Callers are expected to hold mu.
+func (r *castRecorder) event(kind, data string) error {
+	if r.file == nil {
+		return os.ErrClosed
+	}
+	elapsed := time.Since(r.start).Seconds()
+	if err := writeCastLine(r.w, []interface{}{elapsed, kind, data}); err != nil {
+		return err
+	}
+	return r.w.Flush()
+}
+
+// Close flushes all pending output and closes the recording.
+func (r *castRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	if len(r.pending) > 0 {
+		_ = r.event("o", string(r.pending))
+		r.pending = nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// WriteCast writes the given output as an asciicast v2 document with a single output event.
+func WriteCast(w io.Writer, header castHeader, output []byte) error {
+	header.Version = 2
+	if err := writeCastLine(w, header); err != nil {
+		return err
+	}
+	return writeCastLine(w, []interface{}{0.0, "o", string(output)})
+}
+
+func writeCastLine(w io.Writer, v interface{}) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,141 @@
+package terminal
+
+import (
+	"common/log"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrLimitExceeded means starting a terminal would exceed the limits of the Mux.
+var ErrLimitExceeded = errors.New("terminal limit exceeded")
+
+// TerminalLimits cap the number of terminals a Mux runs. A limit of 0 means no limit.
+// Terminals running a task, i.e. with TaskAnnotation, are exempt from the limits.
+type TerminalLimits struct {
+	// MaxTerminals is the maximum number of open terminals
+	MaxTerminals int
+	// AnnotationKey groups terminals by the value of this annotation, e.g. the client that opened them.
+	// Terminals without this annotation are only subject to MaxTerminals.
+	AnnotationKey string
+	// MaxPerAnnotation is the maximum number of open terminals sharing a value of AnnotationKey
+	MaxPerAnnotation int
+}
+
+// checkLimits returns an error if starting a terminal with the given annotations exceeds the limits.
+// Callers are expected to hold mu.
+func (m *Mux) checkLimits(annotations map[string]string) error {
+	if _, task := annotations[TaskAnnotation]; task {
+		return nil
+	}
+
+	var total int
+	for _, term := range m.terms {
+		if !term.isTask() {
+			total++
+		}
+	}
+	if m.Limits.MaxTerminals > 0 && total >= m.Limits.MaxTerminals {
+		return fmt.Errorf("%w: at most %d terminals can be open", ErrLimitExceeded, m.Limits.MaxTerminals)
+	}
+
+	key := m.Limits.AnnotationKey
+	if key == "" || m.Limits.MaxPerAnnotation <= 0 {
+		return nil
+	}
+	value, ok := annotations[key]
+	if !ok {
+		return nil
+	}
+	var count int
+	for _, term := range m.terms {
+		if !term.isTask() && term.MatchAnnotations(map[string]string{key: value}) {
+			count++
+		}
+	}
+	if count >= m.Limits.MaxPerAnnotation {
+		return fmt.Errorf("%w: at most %d terminals can be open for %s=%s", ErrLimitExceeded, m.Limits.MaxPerAnnotation, key, value)
+	}
+	return nil
+}
+
+// ReapIdleTerminals periodically closes terminals which have been idle for longer than
+// their idle timeout. Terminals without an idle timeout or running a task are never closed.
+func (m *Mux) ReapIdleTerminals(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.reapIdleTerminals(ctx, now)
+		}
+	}
+}
+
+func (m *Mux) reapIdleTerminals(ctx context.Context, now time.Time) {
+	var idle []string
+	m.mu.RLock()
+	for alias, term := range m.terms {
+		if term.idle(now) {
+			idle = append(idle, alias)
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, alias := range idle {
+		log.WithField("alias", alias).Info("closing idle terminal")
+		if err := m.CloseTerminal(ctx, alias, false); err != nil && err != ErrNotFound {
+			log.WithError(err).WithField("alias", alias).Warn("cannot close idle terminal")
+		}
+	}
+}
+
+// idle returns true if the terminal has had no listeners, no output and no foreground
+// process other than its shell for longer than its idle timeout.
+func (term *Term) idle(now time.Time) bool {
+	if term.IdleTimeout <= 0 || term.isTask() {
+		return false
+	}
+
+	term.mu.Lock()
+	defer term.mu.Unlock()
+	if term.closed {
+		return false
+	}
+	if term.Stdout.ListenerCount() > 0 || term.runsForegroundProcess() {
+		term.lastActive = now
+		return false
+	}
+	lastActive := term.lastActive
+	if lastOutput := term.Stdout.lastOutput(); lastOutput.After(lastActive) {
+		lastActive = lastOutput
+	}
+	return now.Sub(lastActive) >= term.IdleTimeout
+}
+
+// runsForegroundProcess returns true if a process other than the terminal's shell
+// is in the foreground. Callers are expected to hold mu.
+func (term *Term) runsForegroundProcess() bool {
+	proc := term.Command.Process
+	if proc == nil {
+		return false
+	}
+	pgrp, err := unix.IoctlGetInt(int(term.PTY.Fd()), unix.TIOCGPGRP)
+	if err != nil {
+		return false
+	}
+	return pgrp != proc.Pid
+}
+
+// isTask returns true if the terminal runs a task.
+func (term *Term) isTask() bool {
+	term.mu.RLock()
+	defer term.mu.RUnlock()
+	_, ok := term.annotations[TaskAnnotation]
+	return ok
+}
@@ -0,0 +1,509 @@
+package terminal
+
+import (
+	"common/log"
+	"io"
+	"supervisor/api"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultMultiplexWindow is the number of bytes sent for an alias before the
+	// client has to acknowledge them, unless the client requests another window.
+	defaultMultiplexWindow = 256 * 1024
+	// multiplexFrameSize is the maximum payload of a single data frame. Every alias
+	// sends at most one frame per round, s.t. a noisy terminal cannot starve the others.
+	multiplexFrameSize = 32 * 1024
+	// multiplexMaxPending is the amount of output buffered per alias while the client
+	// has no window left. Once it is reached the terminal listener stops reading and
+	// is eventually dropped with ErrReadTimeout.
+	multiplexMaxPending = 1024 * 1024
+)
+
+// multiplexSession serves a single Multiplex stream.
+type multiplexSession struct {
+	srv    *MuxTerminalService
+	stream api.TerminalService_MultiplexServer
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	// subs are the subscriptions by the alias of their terminal
+	subs map[string]*multiplexSubscription
+	// order is the round-robin order in which subscriptions send their output
+	order []*multiplexSubscription
+	next  int
+	// control holds frames which are not flow controlled, e.g. titles and errors
+	control    []*api.MultiplexTerminalResponse
+	halfClosed bool
+	closed     bool
+
+	wake chan struct{}
+}
+
+// multiplexSubscription is a terminal subscribed to on a multiplexed stream.
+// All fields but the immutable ones are guarded by the session's mu.
+type multiplexSubscription struct {
+	alias string
+	// ref is the reference the client subscribed with, which the frames carry
+	ref          string
+	term         *Term
+	grant        *accessGrant
+	stdout       io.ReadCloser
+	removeViewer func()
+
+	pending     []byte
+	window      int64
+	title       string
+	titleSource api.TerminalTitleSource
+	// exit is sent once all pending output has been sent
+	exit   *api.MultiplexTerminalResponse
+	closed chan struct{}
+}
+
+// Multiplex listens and writes to many terminals over a single stream.
+func (srv *MuxTerminalService) Multiplex(stream api.TerminalService_MultiplexServer) error {
+	s := &multiplexSession{
+		srv:    srv,
+		stream: stream,
+		subs:   make(map[string]*multiplexSubscription),
+		wake:   make(chan struct{}, 1),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	defer s.close()
+
+	log.Info("new multiplexed terminal client")
+	defer log.Info("multiplexed terminal client left")
+
+	recvErr := make(chan error, 1)
+	go func() {
+		recvErr <- s.receive()
+	}()
+	go s.pollTitles()
+
+	for {
+		frames, finished := s.nextFrames()
+		for _, frame := range frames {
+			if err := stream.Send(frame); err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+		}
+		if finished {
+			return nil
+		}
+		if len(frames) > 0 {
+			continue
+		}
+
+		select {
+		case <-s.wake:
+		case <-stream.Context().Done():
+			return nil
+		case err := <-recvErr:
+			if err == io.EOF {
+				// the client won't send anymore requests, but still receives the
+				// output of its subscriptions until they have exited
+				s.mu.Lock()
+				s.halfClosed = true
+				s.mu.Unlock()
+				continue
+			}
+			if status.Code(err) == codes.Canceled {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// receive handles the client's requests until the client closes its side of the stream.
+func (s *multiplexSession) receive() error {
+	for {
+		req, err := s.stream.Recv()
+		if err != nil {
+			return err
+		}
+		switch r := req.Request.(type) {
+		case *api.MultiplexTerminalRequest_Subscribe:
+			s.subscribe(r.Subscribe)
+		case *api.MultiplexTerminalRequest_Unsubscribe:
+			s.mu.Lock()
+			if sub := s.find(r.Unsubscribe.Alias); sub != nil {
+				s.remove(sub)
+			}
+			s.mu.Unlock()
+		case *api.MultiplexTerminalRequest_Write:
+			s.write(r.Write)
+		case *api.MultiplexTerminalRequest_Ack:
+			s.mu.Lock()
+			if sub := s.find(r.Ack.Alias); sub != nil {
+				sub.window += int64(r.Ack.Bytes)
+			}
+			s.mu.Unlock()
+			s.signal()
+		}
+	}
+}
+
+// subscribe forwards the output of the terminal referred to by an alias, a name or a selector.
+// The frames of the subscription carry the reference the client subscribed with. A terminal
+// can only be subscribed to once per stream, no matter which reference is used.
+func (s *multiplexSession) subscribe(req *api.TerminalSubscribe) {
+	s.srv.Mux.mu.RLock()
+	alias, term, err := s.srv.lookup(req.Alias)
+	s.srv.Mux.mu.RUnlock()
+	if err != nil {
+		st := status.Convert(err)
+		s.fail(req.Alias, st.Code(), st.Message())
+		return
+	}
+	grant, err := s.srv.authorize(s.stream.Context(), term, req.Token, api.TerminalAccessScope_read_only)
+	if err != nil {
+		st := status.Convert(err)
+		s.fail(req.Alias, st.Code(), st.Message())
+		return
+	}
+	title, titleSource, _ := term.GetTitle()
+
+	window := int64(req.Window)
+	if window == 0 {
+		window = defaultMultiplexWindow
+	}
+	sub := &multiplexSubscription{
+		alias:       alias,
+		ref:         req.Alias,
+		term:        term,
+		grant:       grant,
+		window:      window,
+		title:       title,
+		titleSource: titleSource,
+		closed:      make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	if _, exists := s.subs[alias]; exists {
+		s.mu.Unlock()
+		s.fail(req.Alias, codes.AlreadyExists, "already subscribed")
+		return
+	}
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	sub.stdout = term.Stdout.Listen()
+	if grant != nil {
+		sub.removeViewer = term.addViewer(grant)
+	}
+	s.subs[alias] = sub
+	s.order = append(s.order, sub)
+	s.control = append(s.control, &api.MultiplexTerminalResponse{
+		Alias:       req.Alias,
+		Output:      &api.MultiplexTerminalResponse_Title{Title: title},
+		TitleSource: titleSource,
+	})
+	s.mu.Unlock()
+	s.signal()
+
+	go s.read(sub)
+	go s.forwardClipboard(sub)
+	if grant != nil {
+		go s.watchGrant(sub)
+	}
+}
+
+func (s *multiplexSession) write(req *api.TerminalStreamWrite) {
+	s.srv.Mux.mu.RLock()
+	alias, term, err := s.srv.lookup(req.Alias)
+	s.srv.Mux.mu.RUnlock()
+	if err != nil {
+		st := status.Convert(err)
+		s.fail(req.Alias, st.Code(), st.Message())
+		return
+	}
+
+	// writes to a subscribed terminal use the token of the subscription
+	token := req.Token
+	s.mu.Lock()
+	if sub, ok := s.subs[alias]; ok && token == "" && sub.grant != nil {
+		token = sub.grant.token
+	}
+	s.mu.Unlock()
+
+	if _, err := s.srv.authorize(s.stream.Context(), term, token, api.TerminalAccessScope_read_write); err != nil {
+		st := status.Convert(err)
+		s.fail(req.Alias, st.Code(), st.Message())
+		return
+	}
+	if _, err := term.PTY.Write(req.Stdin); err != nil {
+		s.fail(req.Alias, codes.Internal, err.Error())
+	}
+}
+
+// read copies the terminal output into the pending buffer of the subscription.
+func (s *multiplexSession) read(sub *multiplexSubscription) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := sub.stdout.Read(buf)
+		if n > 0 && !s.push(sub, buf[:n]) {
+			return
+		}
+		if err == io.EOF {
+			break
+		}
+		if err == ErrReadTimeout {
+			s.drop(sub, codes.ResourceExhausted, "output has not been acknowledged in time")
+			return
+		}
+		if err != nil {
+			s.drop(sub, codes.Internal, err.Error())
+			return
+		}
+	}
+
+	select {
+	case <-sub.closed:
+		// the client unsubscribed
+		return
+	default:
+	}
+	// a non-zero exit status is reported as an error by Wait, but is not a failure here
+	state, err := sub.term.Wait()
+	if state == nil {
+		s.drop(sub, codes.Internal, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	if s.subs[sub.alias] != sub {
+		s.mu.Unlock()
+		return
+	}
+	sub.exit = &api.MultiplexTerminalResponse{
+		Alias:  sub.ref,
+		Output: &api.MultiplexTerminalResponse_ExitCode{ExitCode: int32(state.ExitCode())},
+	}
+	s.mu.Unlock()
+	s.signal()
+}
+
+// push appends output to the pending buffer of the subscription. It blocks while the
+// buffer is full and returns false if the subscription has been closed meanwhile.
+func (s *multiplexSession) push(sub *multiplexSubscription, p []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(sub.pending) >= multiplexMaxPending && !sub.isClosed() {
+		s.cond.Wait()
+	}
+	if sub.isClosed() {
+		return false
+	}
+	sub.pending = append(sub.pending, p...)
+	s.signal()
+	return true
+}
+
+// forwardClipboard sends the clipboard updates of the terminal until the subscription is closed.
+func (s *multiplexSession) forwardClipboard(sub *multiplexSubscription) {
+	events, cancel := sub.term.ListenClipboard()
+	defer cancel()
+	for {
+		select {
+		case <-sub.closed:
+			return
+		case event := <-events:
+			s.mu.Lock()
+			s.control = append(s.control, &api.MultiplexTerminalResponse{
+				Alias: sub.ref,
+				Output: &api.MultiplexTerminalResponse_Clipboard{
+					Clipboard: &api.TerminalClipboard{Selection: event.Selection, Content: event.Content},
+				},
+			})
+			s.mu.Unlock()
+			s.signal()
+		}
+	}
+}
+
+// watchGrant drops the subscription once its access grant has been revoked or has expired.
+func (s *multiplexSession) watchGrant(sub *multiplexSubscription) {
+	expiry := time.NewTimer(time.Until(sub.grant.expires))
+	defer expiry.Stop()
+	select {
+	case <-sub.grant.revoked:
+		s.drop(sub, codes.Unauthenticated, "token has been revoked")
+	case <-expiry.C:
+		s.drop(sub, codes.Unauthenticated, "token has expired")
+	case <-sub.closed:
+	}
+}
+
+// pollTitles sends title changes of all subscribed terminals.
+func (s *multiplexSession) pollTitles() {
+	t := time.NewTicker(200 * time.Millisecond)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.stream.Context().Done():
+			return
+		case <-t.C:
+		}
+
+		s.mu.Lock()
+		subs := append([]*multiplexSubscription(nil), s.order...)
+		s.mu.Unlock()
+
+		var changed bool
+		for _, sub := range subs {
+			title, titleSource, _ := sub.term.GetTitle()
+
+			s.mu.Lock()
+			if !sub.isClosed() && (title != sub.title || titleSource != sub.titleSource) {
+				sub.title, sub.titleSource = title, titleSource
+				s.control = append(s.control, &api.MultiplexTerminalResponse{
+					Alias:       sub.ref,
+					Output:      &api.MultiplexTerminalResponse_Title{Title: title},
+					TitleSource: titleSource,
+				})
+				changed = true
+			}
+			s.mu.Unlock()
+		}
+		if changed {
+			s.signal()
+		}
+	}
+}
+
+// nextFrames returns the frames to send next: all control frames and at most one
+// data frame per alias within its window. finished is true once the client has
+// closed its side of the stream and no subscription is left.
+func (s *multiplexSession) nextFrames() (frames []*api.MultiplexTerminalResponse, finished bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	frames, s.control = s.control, nil
+
+	var exited []*multiplexSubscription
+	n := len(s.order)
+	for i := 0; i < n; i++ {
+		sub := s.order[(s.next+i)%n]
+		if len(sub.pending) > 0 && sub.window > 0 {
+			size := min(len(sub.pending), multiplexFrameSize, int(sub.window))
+			frames = append(frames, &api.MultiplexTerminalResponse{
+				Alias:  sub.ref,
+				Output: &api.MultiplexTerminalResponse_Data{Data: sub.pending[:size:size]},
+			})
+			sub.pending = sub.pending[size:]
+			sub.window -= int64(size)
+		}
+		if len(sub.pending) == 0 && sub.exit != nil {
+			frames = append(frames, sub.exit)
+			exited = append(exited, sub)
+		}
+	}
+	if n > 0 {
+		s.next = (s.next + 1) % n
+	}
+	for _, sub := range exited {
+		s.remove(sub)
+	}
+	// readers might be waiting for space in their pending buffer
+	s.cond.Broadcast()
+
+	return frames, s.halfClosed && len(s.subs) == 0 && len(frames) == 0
+}
+
+// fail reports an error for a request concerning the alias.
+func (s *multiplexSession) fail(alias string, code codes.Code, msg string) {
+	s.mu.Lock()
+	s.control = append(s.control, &api.MultiplexTerminalResponse{
+		Alias:  alias,
+		Output: &api.MultiplexTerminalResponse_Error{Error: &api.TerminalStreamError{Code: uint32(code), Message: msg}},
+	})
+	s.mu.Unlock()
+	s.signal()
+}
+
+// drop reports an error for the subscription and removes it, unless it has been removed already.
+func (s *multiplexSession) drop(sub *multiplexSubscription, code codes.Code, msg string) {
+	s.mu.Lock()
+	if s.subs[sub.alias] != sub {
+		s.mu.Unlock()
+		return
+	}
+	s.remove(sub)
+	s.control = append(s.control, &api.MultiplexTerminalResponse{
+		Alias:  sub.ref,
+		Output: &api.MultiplexTerminalResponse_Error{Error: &api.TerminalStreamError{Code: uint32(code), Message: msg}},
+	})
+	s.mu.Unlock()
+	s.signal()
+}
+
+// find returns the subscription to the terminal with the alias, or the one subscribed with
+// the reference, nil if there is none. Callers are expected to hold mu.
+func (s *multiplexSession) find(ref string) *multiplexSubscription {
+	if sub, ok := s.subs[ref]; ok {
+		return sub
+	}
+	for _, sub := range s.order {
+		if sub.ref == ref {
+			return sub
+		}
+	}
+	return nil
+}
+
+// remove drops the subscription. Callers are expected to hold mu.
+func (s *multiplexSession) remove(sub *multiplexSubscription) {
+	if s.subs[sub.alias] != sub {
+		return
+	}
+	delete(s.subs, sub.alias)
+	for i, other := range s.order {
+		if other == sub {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	sub.close()
+	s.cond.Broadcast()
+}
+
+// close drops all subscriptions once the stream has ended.
+func (s *multiplexSession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for _, sub := range append([]*multiplexSubscription(nil), s.order...) {
+		s.remove(sub)
+	}
+}
+
+// signal wakes up the sender.
+func (s *multiplexSession) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (sub *multiplexSubscription) close() {
+	close(sub.closed)
+	_ = sub.stdout.Close()
+	if sub.removeViewer != nil {
+		sub.removeViewer()
+	}
+}
+
+func (sub *multiplexSubscription) isClosed() bool {
+	select {
+	case <-sub.closed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,106 @@
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrNameConflict means another terminal already uses the requested name.
+	ErrNameConflict = errors.New("terminal name already in use")
+	// ErrInvalidName means the requested name contains unsupported characters.
+	ErrInvalidName = errors.New("invalid terminal name")
+	// ErrAmbiguous means a selector matches more than one terminal.
+	ErrAmbiguous = errors.New("selector matches more than one terminal")
+)
+
+// validName restricts names s.t. they can't be mistaken for selectors.
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// Resolve returns the alias of the terminal referred to by ref, which is either
+// an alias, a terminal name or an annotation selector (key=value[,key=value])
+// matching exactly one terminal.
+func (m *Mux) Resolve(ref string) (alias string, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.resolve(ref)
+}
+
+// resolve is Resolve for callers who hold mu.
+func (m *Mux) resolve(ref string) (string, error) {
+	if _, ok := m.terms[ref]; ok {
+		return ref, nil
+	}
+	if alias, ok := m.names[ref]; ok {
+		return alias, nil
+	}
+
+	selector, ok := parseSelector(ref)
+	if !ok {
+		return "", ErrNotFound
+	}
+	var res string
+	for _, alias := range m.aliases {
+		if !m.terms[alias].MatchAnnotations(selector) {
+			continue
+		}
+		if res != "" {
+			return "", ErrAmbiguous
+		}
+		res = alias
+	}
+	if res == "" {
+		return "", ErrNotFound
+	}
+	return res, nil
+}
+
+// Select returns the aliases of the terminals which have all annotations of selector,
+// in the order they were started.
+func (m *Mux) Select(selector map[string]string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var res []string
+	for _, alias := range m.aliases {
+		if m.terms[alias].MatchAnnotations(selector) {
+			res = append(res, alias)
+		}
+	}
+	return res
+}
+
+// reserveName checks that name can be given to a new terminal.
+// Callers are expected to hold mu.
+func (m *Mux) reserveName(name string) error {
+	if name == "" {
+		return nil
+	}
+	if !validName.MatchString(name) {
+		return fmt.Errorf("%w %q: only letters, digits, '.', '_' and '-' are allowed", ErrInvalidName, name)
+	}
+	if _, exists := m.names[name]; exists {
+		return fmt.Errorf("%w: %s", ErrNameConflict, name)
+	}
+	if _, exists := m.terms[name]; exists {
+		return fmt.Errorf("%w: %s", ErrNameConflict, name)
+	}
+	return nil
+}
+
+// parseSelector parses an annotation selector of the form key=value[,key=value].
+func parseSelector(ref string) (map[string]string, bool) {
+	if !strings.Contains(ref, "=") {
+		return nil, false
+	}
+	res := make(map[string]string)
+	for _, pair := range strings.Split(ref, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, false
+		}
+		res[key] = value
+	}
+	return res, true
+}
@@ -0,0 +1,136 @@
+package terminal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"sync"
+)
+
+// osc52Prefix starts an OSC 52 sequence, which sets the clipboard of the terminal emulator:
+// ESC ] 52 ; <selection> ; <base64 content> followed by BEL or ST.
+var osc52Prefix = []byte("\x1b]52;")
+
+// osc52MaxSize limits the size of a sequence split across writes. Longer sequences are ignored.
+const osc52MaxSize = 1 << 20
+
+// ClipboardEvent is a clipboard update a program in the terminal requested through OSC 52.
+type ClipboardEvent struct {
+	// Selection lists the targeted selections, e.g. c for the clipboard and p for the primary selection
+	Selection string
+	Content   []byte
+}
+
+// osc52Scanner detects OSC 52 sequences in terminal output, including
+// sequences split across several writes.
+type osc52Scanner struct {
+	mu      sync.Mutex
+	pending []byte
+}
+
+// Scan returns the clipboard events of all sequences completed by p.
+func (s *osc52Scanner) Scan(p []byte) []ClipboardEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := p
+	if len(s.pending) > 0 {
+		data = append(s.pending, p...)
+		s.pending = nil
+	}
+
+	var res []ClipboardEvent
+	for {
+		start := bytes.Index(data, osc52Prefix)
+		if start == -1 {
+			s.keepPrefix(data)
+			return res
+		}
+		body := data[start+len(osc52Prefix):]
+		end, next := osc52Terminator(body)
+		if end == -1 {
+			if len(data)-start <= osc52MaxSize {
+				s.pending = append([]byte(nil), data[start:]...)
+			}
+			return res
+		}
+		if ev, ok := parseOSC52(body[:end]); ok {
+			res = append(res, ev)
+		}
+		data = body[next:]
+	}
+}
+
+// keepPrefix keeps the end of data if it could be the beginning of a sequence.
+func (s *osc52Scanner) keepPrefix(data []byte) {
+	for n := min(len(osc52Prefix)-1, len(data)); n > 0; n-- {
+		if bytes.HasSuffix(data, osc52Prefix[:n]) {
+			s.pending = append([]byte(nil), data[len(data)-n:]...)
+			return
+		}
+	}
+}
+
+// osc52Terminator returns the index of the terminator of the sequence body
+// and the index right after it, or -1 if the sequence is incomplete.
+func osc52Terminator(body []byte) (end, next int) {
+	for i, c := range body {
+		switch {
+		case c == asciiBEL:
+			return i, i + 1
+		case c == asciiESC && i+1 < len(body) && body[i+1] == '\\':
+			return i, i + 2
+		}
+	}
+	return -1, -1
+}
+
+// parseOSC52 parses the body of a sequence, i.e. <selection>;<base64 content>.
+// Queries for the clipboard content (?) are ignored.
+func parseOSC52(body []byte) (ClipboardEvent, bool) {
+	selection, payload, ok := bytes.Cut(body, []byte(";"))
+	if !ok || string(payload) == "?" {
+		return ClipboardEvent{}, false
+	}
+	content, err := base64.StdEncoding.DecodeString(string(payload))
+	if err != nil {
+		content, err = base64.RawStdEncoding.DecodeString(string(payload))
+		if err != nil {
+			return ClipboardEvent{}, false
+		}
+	}
+	if len(selection) == 0 {
+		selection = []byte("c")
+	}
+	return ClipboardEvent{Selection: string(selection), Content: content}, true
+}
+
+// ListenClipboard returns the clipboard updates requested by programs in the terminal
+// through OSC 52. Updates are dropped if the listener doesn't keep up. Call cancel to
+// stop listening.
+func (term *Term) ListenClipboard() (events <-chan ClipboardEvent, cancel func()) {
+	ch := make(chan ClipboardEvent, 8)
+
+	term.clipboardMu.Lock()
+	defer term.clipboardMu.Unlock()
+	if term.clipboardListeners == nil {
+		term.clipboardListeners = make(map[chan ClipboardEvent]struct{})
+	}
+	term.clipboardListeners[ch] = struct{}{}
+
+	return ch, func() {
+		term.clipboardMu.Lock()
+		defer term.clipboardMu.Unlock()
+		delete(term.clipboardListeners, ch)
+	}
+}
+
+func (term *Term) notifyClipboard(event ClipboardEvent) {
+	term.clipboardMu.Lock()
+	defer term.clipboardMu.Unlock()
+	for ch := range term.clipboardListeners {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package terminal
+
+import (
+	"common/log"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	_pty "github.com/creack/pty"
+)
+
+const (
+	snapshotMetadataExt = ".json"
+	snapshotBacklogExt  = ".backlog"
+)
+
+// restoredBanner is printed between the previous output and the new shell of a restored terminal.
+const restoredBanner = "\r\n\x1b[2m[terminal restored after supervisor restart]\x1b[0m\r\n"
+
+// TerminalSnapshot is the persisted state of a terminal.
+type TerminalSnapshot struct {
+	Alias       string            `json:"alias"`
+	Name        string            `json:"name,omitempty"`
+	IdleTimeout time.Duration     `json:"idleTimeout,omitempty"`
+	Command     []string          `json:"command"`
+	Workdir     string            `json:"workdir"`
+	Env         []string          `json:"env"`
+	Annotations map[string]string `json:"annotations"`
+	// DefaultTitle is the title the terminal was started with
+	DefaultTitle string `json:"defaultTitle,omitempty"`
+	// Title is the title set through the API
+	Title string `json:"title,omitempty"`
+	Cols  uint16 `json:"cols,omitempty"`
+	Rows  uint16 `json:"rows,omitempty"`
+
+	// Backlog is stored in a separate file next to the metadata
+	Backlog []byte `json:"-"`
+}
+
+// TerminalStore persists terminal snapshots in a directory, s.t. terminals
+// can be restored once supervisor has been restarted.
+type TerminalStore struct {
+	Dir string
+}
+
+// Save atomically writes the snapshot to disk.
+func (s *TerminalStore) Save(snapshot *TerminalSnapshot) error {
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return err
+	}
+	metadata, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	// the backlog goes first, s.t. metadata never references a missing backlog
+	if err := writeFileAtomic(filepath.Join(s.Dir, snapshot.Alias+snapshotBacklogExt), snapshot.Backlog); err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(s.Dir, snapshot.Alias+snapshotMetadataExt), metadata)
+}
+
+// Remove deletes the snapshot of the given terminal.
+func (s *TerminalStore) Remove(alias string) error {
+	err := os.Remove(filepath.Join(s.Dir, alias+snapshotMetadataExt))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	err = os.Remove(filepath.Join(s.Dir, alias+snapshotBacklogExt))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Aliases returns the aliases of all stored terminals.
+func (s *TerminalStore) Aliases() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	for _, entry := range entries {
+		if alias, ok := strings.CutSuffix(entry.Name(), snapshotMetadataExt); ok {
+			res = append(res, alias)
+		}
+	}
+	return res, nil
+}
+
+// Load reads the snapshot of the given terminal, including its backlog.
+func (s *TerminalStore) Load(alias string) (*TerminalSnapshot, error) {
+	metadata, err := os.ReadFile(filepath.Join(s.Dir, alias+snapshotMetadataExt))
+	if err != nil {
+		return nil, err
+	}
+	var res TerminalSnapshot
+	if err := json.Unmarshal(metadata, &res); err != nil {
+		return nil, fmt.Errorf("cannot parse terminal snapshot %s: %w", alias, err)
+	}
+	res.Backlog, err = os.ReadFile(filepath.Join(s.Dir, alias+snapshotBacklogExt))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// writeFileAtomic writes a file by renaming a temporary file into place.
+func writeFileAtomic(path string, content []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// snapshot captures the current state of the terminal.
+func (term *Term) snapshot(alias string) *TerminalSnapshot {
+	res := &TerminalSnapshot{
+		Alias:        alias,
+		Name:         term.Name,
+		IdleTimeout:  term.IdleTimeout,
+		Command:      term.Command.Args,
+		Workdir:      term.Command.Dir,
+		Env:          term.Command.Env,
+		Annotations:  term.GetAnnotations(),
+		DefaultTitle: term.defaultTitle,
+		Backlog:      term.Backlog(),
+	}
+	term.mu.RLock()
+	res.Title = term.title
+	term.mu.RUnlock()
+
+	if proc := term.Command.Process; proc != nil {
+		if cwd, err := filepath.EvalSymlinks(fmt.Sprintf("/proc/%d/cwd", proc.Pid)); err == nil {
+			res.Workdir = cwd
+		}
+	}
+	if size, err := _pty.GetsizeFull(term.PTY); err == nil {
+		res.Cols = size.Cols
+		res.Rows = size.Rows
+	}
+	return res
+}
+
+// PersistTerminals periodically writes snapshots of all open terminals to the store
+// and removes the snapshots of closed terminals. It returns when ctx is cancelled.
+func (srv *MuxTerminalService) PersistTerminals(ctx context.Context, store *TerminalStore, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			srv.persistTerminals(store)
+		}
+	}
+}
+
+func (srv *MuxTerminalService) persistTerminals(store *TerminalStore) {
+	srv.Mux.mu.RLock()
+	snapshots := make(map[string]*TerminalSnapshot, len(srv.Mux.terms))
+	for alias, term := range srv.Mux.terms {
+		snapshots[alias] = term.snapshot(alias)
+	}
+	srv.Mux.mu.RUnlock()
+
+	for alias, snapshot := range snapshots {
+		if err := store.Save(snapshot); err != nil {
+			log.WithError(err).WithField("alias", alias).Warn("cannot persist terminal")
+		}
+	}
+
+	stored, err := store.Aliases()
+	if err != nil {
+		log.WithError(err).Warn("cannot list persisted terminals")
+		return
+	}
+	for _, alias := range stored {
+		if _, open := snapshots[alias]; open {
+			continue
+		}
+		if err := store.Remove(alias); err != nil {
+			log.WithError(err).WithField("alias", alias).Warn("cannot remove persisted terminal")
+		}
+	}
+}
+
+// RestoreTerminals starts a new shell for every interactive terminal found in the store, with its
+// previous working directory, environment, annotations and title. The previous output is shown
+// as history of the new terminal. Terminals which ran a task or a command instead of a shell
+// are not restored, since that would run them again. It returns the number of restored terminals.
+func (srv *MuxTerminalService) RestoreTerminals(store *TerminalStore) (int, error) {
+	aliases, err := store.Aliases()
+	if err != nil {
+		return 0, err
+	}
+
+	var restored int
+	for _, alias := range aliases {
+		log := log.WithField("alias", alias)
+
+		snapshot, err := store.Load(alias)
+		if err != nil {
+			log.WithError(err).Warn("cannot load persisted terminal")
+			continue
+		}
+		if _, task := snapshot.Annotations[TaskAnnotation]; task || len(snapshot.Command) != 1 {
+			log.Info("not restoring terminal which ran a task or command")
+			_ = store.Remove(alias)
+			continue
+		}
+		if err := srv.restoreTerminal(snapshot); err != nil {
+			log.WithError(err).Warn("cannot restore terminal")
+			_ = store.Remove(alias)
+			continue
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+func (srv *MuxTerminalService) restoreTerminal(snapshot *TerminalSnapshot) error {
+	if len(snapshot.Command) == 0 {
+		return fmt.Errorf("terminal snapshot has no command")
+	}
+
+	shell := srv.DefaultShell
+	if shell == "" {
+		shell = snapshot.Command[0]
+	}
+	cmd := exec.Command(shell)
+	if srv.DefaultCreds != nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			Credential: srv.DefaultCreds,
+		}
+	}
+	cmd.Dir = snapshot.Workdir
+	if _, err := os.Stat(cmd.Dir); err != nil {
+		cmd.Dir = srv.DefaultWorkdir
+	}
+	cmd.Env = snapshot.Env
+	if cmd.Env == nil {
+		cmd.Env = append(srv.Env, "TERM=xterm-256color")
+	}
+	srv.setAmbientCaps(cmd)
+
+	options := TermOptions{
+		ReadTimeout: 5 * time.Second,
+		Annotations: snapshot.Annotations,
+		Name:        snapshot.Name,
+		IdleTimeout: snapshot.IdleTimeout,
+		Title:       snapshot.DefaultTitle,
+		History:     append(snapshot.Backlog, restoredBanner...),
+		BacklogSize: srv.BacklogSize,
+		Spill:       srv.Spill,
+		OnClipboard: srv.OnClipboard,
+	}
+	if snapshot.Cols != 0 && snapshot.Rows != 0 {
+		options.Size = &_pty.Winsize{Cols: snapshot.Cols, Rows: snapshot.Rows}
+	}
+
+	if err := srv.Mux.startWithAlias(snapshot.Alias, cmd, options); err != nil {
+		return err
+	}
+	if term, ok := srv.Mux.Get(snapshot.Alias); ok && snapshot.Title != "" {
+		term.SetTitle(snapshot.Title)
+	}
+	log.WithField("alias", snapshot.Alias).WithField("workdir", cmd.Dir).Info("restored terminal")
+	return nil
+}
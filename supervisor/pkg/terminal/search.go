@@ -0,0 +1,89 @@
+package terminal
+
+import (
+	"bytes"
+	"regexp"
+)
+
+const (
+	// searchMaxBacklog is the amount of most recent output searched per terminal.
+	searchMaxBacklog = 16 << 20
+	// defaultSearchMaxMatches limits the number of matches if the request doesn't.
+	defaultSearchMaxMatches = 1000
+)
+
+// SearchMatch is a line of terminal output matching a search.
+type SearchMatch struct {
+	// Line is the matching line without escape sequences
+	Line string
+	// Before and After are the lines surrounding the match
+	Before []string
+	After  []string
+	// Offset is the offset of the matching line in the terminal's output
+	Offset int64
+	// MatchOffset is the offset of the match in the terminal's output
+	MatchOffset int64
+}
+
+// Search looks for lines matching re in the terminal's output with escape sequences
+// removed. It returns at most limit matches and whether more matches exist.
+func (term *Term) Search(re *regexp.Regexp, contextLines, limit int) (matches []SearchMatch, truncated bool, err error) {
+	first, total := term.BacklogRange()
+	raw, start, _, err := term.ReadBacklog(max(first, total-searchMaxBacklog), total)
+	if err != nil {
+		return nil, false, err
+	}
+
+	text, offsets := stripANSI(raw, true)
+	// rawOffset maps an index in text to the offset in the terminal's output
+	rawOffset := func(i int) int64 {
+		if i >= len(offsets) {
+			return start + int64(len(raw))
+		}
+		return start + int64(offsets[i])
+	}
+
+	type line struct {
+		text  []byte
+		start int
+	}
+	var lines []line
+	for pos := 0; pos < len(text); {
+		end := bytes.IndexByte(text[pos:], '\n')
+		if end == -1 {
+			end = len(text) - pos
+		}
+		lines = append(lines, line{text: text[pos : pos+end], start: pos})
+		pos += end + 1
+	}
+
+	for i, l := range lines {
+		loc := re.FindIndex(l.text)
+		if loc == nil {
+			continue
+		}
+		if len(matches) == limit {
+			return matches, true, nil
+		}
+
+		// the line starts right after the previous newline, s.t. escape
+		// sequences at the beginning of the line are included
+		lineOffset := start
+		if l.start > 0 {
+			lineOffset = rawOffset(l.start-1) + 1
+		}
+		match := SearchMatch{
+			Line:        string(l.text),
+			Offset:      lineOffset,
+			MatchOffset: rawOffset(l.start + loc[0]),
+		}
+		for j := max(0, i-contextLines); j < i; j++ {
+			match.Before = append(match.Before, string(lines[j].text))
+		}
+		for j := i + 1; j < len(lines) && j <= i+contextLines; j++ {
+			match.After = append(match.After, string(lines[j].text))
+		}
+		matches = append(matches, match)
+	}
+	return matches, false, nil
+}
@@ -1,14 +1,20 @@
 package terminal
 
 import (
+	"bytes"
 	"common/log"
 	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"supervisor/api"
+	"supervisor/pkg/config"
+	"sync"
 	"syscall"
 	"time"
 
@@ -29,6 +35,7 @@ func NewMuxTerminalService(m *Mux) *MuxTerminalService {
 		DefaultWorkdir: "/home/workspace",
 		DefaultShell:   shell,
 		Env:            os.Environ(),
+		OwnerToken:     rand.Text(),
 	}
 }
 
@@ -36,11 +43,43 @@ func NewMuxTerminalService(m *Mux) *MuxTerminalService {
 type MuxTerminalService struct {
 	Mux *Mux
 
+	// OwnerToken identifies the workspace owner, who has full access to all terminals.
+	// Clients with a share token only have access to the terminals shared with them through
+	// ShareTerminal. Clients pass their token in the metadata of a call, or in the token field
+	// of a request.
+	OwnerToken string
+	// RequireOwnerToken denies access to clients without a token. Otherwise they have the
+	// access of the owner, as before terminals could be shared.
+	RequireOwnerToken bool
+
 	DefaultWorkdir string
 	// DefaultWorkdirProvider allows dynamically to compute workdir
 	// if returns empty string then DefaultWorkdir is used
 	DefaultWorkdirProvider func() string
 
+	// ProfileProvider resolves named terminal profiles referenced by OpenTerminalRequest.Profile.
+	// If nil, opening a terminal with a profile fails.
+	ProfileProvider func(name string) (config.TerminalProfileConfig, bool)
+
+	// RecordingDir is the directory terminal recordings are written to.
+	// Defaults to .opencoder/recordings in DefaultWorkdir.
+	RecordingDir string
+
+	// BacklogSize is the number of bytes of output kept in memory for each terminal.
+	// Use 0 for the default of 256 KiB.
+	BacklogSize int64
+	// Spill keeps the complete output of all terminals on disk if set.
+	Spill *SpillStore
+	// OnClipboard is called for every clipboard update a program in a terminal
+	// requests through OSC 52.
+	OnClipboard func(alias string, event ClipboardEvent)
+	// Banner returns text printed before the shell starts in interactive terminals,
+	// i.e. terminals opened without shell arguments. Nothing is printed if nil.
+	Banner func() string
+	// EnvProvider returns environment variables of new terminals which override Env,
+	// s.t. they can change while supervisor runs.
+	EnvProvider func() []string
+
 	DefaultShell       string
 	Env                []string
 	DefaultCreds       *syscall.Credential
@@ -56,6 +95,9 @@ func (srv *MuxTerminalService) RegisterGRPC(s *grpc.Server) {
 
 // Open opens a new terminal running the shell.
 func (srv *MuxTerminalService) Open(ctx context.Context, req *api.OpenTerminalRequest) (*api.OpenTerminalResponse, error) {
+	if err := srv.authorizeOwner(ctx); err != nil {
+		return nil, err
+	}
 	return srv.OpenWithOptions(ctx, req, TermOptions{
 		ReadTimeout: 5 * time.Second,
 		Annotations: req.Annotations,
@@ -65,6 +107,24 @@ func (srv *MuxTerminalService) Open(ctx context.Context, req *api.OpenTerminalRe
 // OpenWithOptions opens a new terminal running the shell with given options.
 // req.Annotations override options.Annotations.
 func (srv *MuxTerminalService) OpenWithOptions(ctx context.Context, req *api.OpenTerminalRequest, options TermOptions) (*api.OpenTerminalResponse, error) {
+	if req.Profile != "" {
+		var profile config.TerminalProfileConfig
+		var found bool
+		if srv.ProfileProvider != nil {
+			profile, found = srv.ProfileProvider(req.Profile)
+		}
+		if !found {
+			return nil, status.Errorf(codes.NotFound, "terminal profile %q not found", req.Profile)
+		}
+		req = srv.applyProfile(req, profile)
+		if options.Title == "" {
+			options.Title = profile.Title
+		}
+	}
+	if options.Annotations == nil {
+		options.Annotations = make(map[string]string, len(req.Annotations))
+	}
+
 	shell := req.Shell
 	if shell == "" {
 		shell = srv.DefaultShell
@@ -84,6 +144,9 @@ func (srv *MuxTerminalService) OpenWithOptions(ctx context.Context, req *api.Ope
 		cmd.Dir = srv.DefaultWorkdir
 	}
 	cmd.Env = append(srv.Env, "TERM=xterm-256color")
+	if srv.EnvProvider != nil {
+		cmd.Env = append(cmd.Env, srv.EnvProvider()...)
+	}
 	for key, value := range req.Env {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%v=%v", key, value))
 	}
@@ -99,9 +162,42 @@ func (srv *MuxTerminalService) OpenWithOptions(ctx context.Context, req *api.Ope
 		}
 	}
 
+	if options.BacklogSize == 0 {
+		options.BacklogSize = srv.BacklogSize
+	}
+	if options.Spill == nil {
+		options.Spill = srv.Spill
+	}
+	if options.OnClipboard == nil {
+		options.OnClipboard = srv.OnClipboard
+	}
+	if options.Name == "" {
+		options.Name = req.Name
+	}
+	if options.IdleTimeout == 0 {
+		options.IdleTimeout = time.Duration(req.IdleTimeout) * time.Second
+	}
+
+	if srv.Banner != nil && len(req.ShellArgs) == 0 && len(options.History) == 0 {
+		options.History = []byte(srv.Banner())
+	}
+
+	if req.Record && options.RecordPath == "" {
+		options.RecordPath = srv.newRecordingPath()
+	}
+
 	srv.setAmbientCaps(cmd)
 
 	alias, err := srv.Mux.Start(cmd, options)
+	if errors.Is(err, ErrNameConflict) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	if errors.Is(err, ErrInvalidName) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, ErrLimitExceeded) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -123,9 +219,68 @@ func (srv *MuxTerminalService) OpenWithOptions(ctx context.Context, req *api.Ope
 	}, nil
 }
 
+// newRecordingPath returns a unique path for a new terminal recording.
+func (srv *MuxTerminalService) newRecordingPath() string {
+	dir := srv.RecordingDir
+	if dir == "" {
+		dir = filepath.Join(srv.DefaultWorkdir, ".opencoder", "recordings")
+	}
+	return filepath.Join(dir, fmt.Sprintf("terminal-%s.cast", time.Now().Format("20060102-150405.000000")))
+}
+
+// applyProfile returns a copy of req where all unset fields are filled from the profile.
+// Environment variables and annotations of the request override those of the profile.
+func (srv *MuxTerminalService) applyProfile(req *api.OpenTerminalRequest, profile config.TerminalProfileConfig) *api.OpenTerminalRequest {
+	res := &api.OpenTerminalRequest{
+		Workdir:     req.Workdir,
+		Env:         make(map[string]string, len(profile.Env)+len(req.Env)),
+		Annotations: make(map[string]string, len(profile.Annotations)+len(req.Annotations)),
+		Shell:       req.Shell,
+		ShellArgs:   req.ShellArgs,
+		Size:        req.Size,
+		Profile:     req.Profile,
+	}
+	if res.Shell == "" {
+		res.Shell = profile.Shell
+		if len(res.ShellArgs) == 0 {
+			res.ShellArgs = profile.Args
+		}
+	}
+	if res.Workdir == "" && profile.Cwd != "" {
+		res.Workdir = profile.Cwd
+		if !filepath.IsAbs(res.Workdir) {
+			res.Workdir = filepath.Join(srv.DefaultWorkdir, res.Workdir)
+		}
+	}
+	for k, v := range profile.Env {
+		res.Env[k] = v
+	}
+	for k, v := range req.Env {
+		res.Env[k] = v
+	}
+	for k, v := range profile.Annotations {
+		res.Annotations[k] = v
+	}
+	for k, v := range req.Annotations {
+		res.Annotations[k] = v
+	}
+	return res
+}
+
 // Shutdown closes a terminal for the given alias.
 func (srv *MuxTerminalService) Shutdown(ctx context.Context, req *api.ShutdownTerminalRequest) (*api.ShutdownTerminalResponse, error) {
-	err := srv.Mux.CloseTerminal(ctx, req.Alias, req.ForceSuccess)
+	if err := srv.authorizeOwner(ctx); err != nil {
+		return nil, err
+	}
+
+	srv.Mux.mu.RLock()
+	alias, _, err := srv.lookup(req.Alias)
+	srv.Mux.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	err = srv.Mux.CloseTerminal(ctx, alias, req.ForceSuccess)
 	if err == ErrNotFound {
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
@@ -135,13 +290,16 @@ func (srv *MuxTerminalService) Shutdown(ctx context.Context, req *api.ShutdownTe
 	return &api.ShutdownTerminalResponse{}, nil
 }
 
-// List lists all open terminals.
+// List lists all open terminals the caller has access to.
 func (srv *MuxTerminalService) List(ctx context.Context, req *api.ListTerminalsRequest) (*api.ListTerminalsResponse, error) {
 	srv.Mux.mu.RLock()
 	defer srv.Mux.mu.RUnlock()
 
 	res := make([]*api.Terminal, 0, len(srv.Mux.terms))
 	for _, alias := range srv.Mux.aliases {
+		if _, err := srv.authorize(ctx, srv.Mux.terms[alias], "", api.TerminalAccessScope_read_only); err != nil {
+			continue
+		}
 		term, ok := srv.get(alias)
 		if !ok {
 			continue
@@ -151,6 +309,11 @@ func (srv *MuxTerminalService) List(ctx context.Context, req *api.ListTerminalsR
 
 	return &api.ListTerminalsResponse{
 		Terminals: res,
+		Limits: &api.TerminalLimits{
+			MaxTerminals:     uint32(srv.Mux.Limits.MaxTerminals),
+			AnnotationKey:    srv.Mux.Limits.AnnotationKey,
+			MaxPerAnnotation: uint32(srv.Mux.Limits.MaxPerAnnotation),
+		},
 	}, nil
 }
 
@@ -158,13 +321,33 @@ func (srv *MuxTerminalService) List(ctx context.Context, req *api.ListTerminalsR
 func (srv *MuxTerminalService) Get(ctx context.Context, req *api.GetTerminalRequest) (*api.Terminal, error) {
 	srv.Mux.mu.RLock()
 	defer srv.Mux.mu.RUnlock()
-	term, ok := srv.get(req.Alias)
+	alias, t, err := srv.lookup(req.Alias)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := srv.authorize(ctx, t, "", api.TerminalAccessScope_read_only); err != nil {
+		return nil, err
+	}
+	term, ok := srv.get(alias)
 	if !ok {
 		return nil, status.Error(codes.NotFound, "terminal not found")
 	}
 	return term, nil
 }
 
+// lookup returns the terminal referred to by an alias, a name or an annotation selector.
+// Callers are expected to hold srv.Mux.mu.
+func (srv *MuxTerminalService) lookup(ref string) (alias string, term *Term, err error) {
+	alias, err = srv.Mux.resolve(ref)
+	if errors.Is(err, ErrAmbiguous) {
+		return "", nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return "", nil, status.Error(codes.NotFound, "terminal not found")
+	}
+	return alias, srv.Mux.terms[alias], nil
+}
+
 func (srv *MuxTerminalService) get(alias string) (*api.Terminal, bool) {
 	term, ok := srv.Mux.terms[alias]
 	if !ok {
@@ -199,22 +382,45 @@ func (srv *MuxTerminalService) get(alias string) (*api.Terminal, bool) {
 		Annotations:    term.GetAnnotations(),
 		Title:          title,
 		TitleSource:    titleSource,
+		Recording:      term.RecordingPath(),
+		Viewers:        term.GetViewers(),
+		Name:           term.Name,
+		IdleTimeout:    uint32(term.IdleTimeout / time.Second),
 	}, true
 }
 
 // Listen listens to a terminal.
 func (srv *MuxTerminalService) Listen(req *api.ListenTerminalRequest, resp api.TerminalService_ListenServer) error {
 	srv.Mux.mu.RLock()
-	term, ok := srv.Mux.terms[req.Alias]
+	alias, term, err := srv.lookup(req.Alias)
 	srv.Mux.mu.RUnlock()
-	if !ok {
-		return status.Error(codes.NotFound, "terminal not found")
+	if err != nil {
+		return err
 	}
-	stdout := term.Stdout.Listen()
+	grant, err := srv.authorize(resp.Context(), term, req.Token, api.TerminalAccessScope_read_only)
+	if err != nil {
+		return err
+	}
+	var (
+		revoked <-chan struct{}
+		expired <-chan time.Time
+	)
+	if grant != nil {
+		removeViewer := term.addViewer(grant)
+		defer removeViewer()
+
+		expiry := time.NewTimer(time.Until(grant.expires))
+		defer expiry.Stop()
+		revoked, expired = grant.revoked, expiry.C
+	}
+
+	stdout := term.Stdout.ListenWithOptions(TermListenOptions{Offset: req.Offset})
 	defer stdout.Close()
+	clipboard, stopClipboard := term.ListenClipboard()
+	defer stopClipboard()
 
-	log.WithField("alias", req.Alias).Info("new terminal client")
-	defer log.WithField("alias", req.Alias).Info("terminal client left")
+	log.WithField("alias", alias).Info("new terminal client")
+	defer log.WithField("alias", alias).Info("terminal client left")
 
 	errchan := make(chan error, 1)
 	messages := make(chan *api.ListenTerminalResponse, 1)
@@ -267,9 +473,17 @@ func (srv *MuxTerminalService) Listen(req *api.ListenTerminalRequest, resp api.T
 		select {
 		case message := <-messages:
 			err = resp.Send(message)
+		case event := <-clipboard:
+			err = resp.Send(&api.ListenTerminalResponse{Output: &api.ListenTerminalResponse_Clipboard{
+				Clipboard: &api.TerminalClipboard{Selection: event.Selection, Content: event.Content},
+			}})
 		case err = <-errchan:
 		case <-resp.Context().Done():
 			return nil
+		case <-revoked:
+			return status.Error(codes.Unauthenticated, "token has been revoked")
+		case <-expired:
+			return status.Error(codes.Unauthenticated, "token has expired")
 		}
 		if err == io.EOF {
 			// EOF isn't really an error here
@@ -284,10 +498,13 @@ func (srv *MuxTerminalService) Listen(req *api.ListenTerminalRequest, resp api.T
 // Write writes to a terminal.
 func (srv *MuxTerminalService) Write(ctx context.Context, req *api.WriteTerminalRequest) (*api.WriteTerminalResponse, error) {
 	srv.Mux.mu.RLock()
-	term, ok := srv.Mux.terms[req.Alias]
+	_, term, err := srv.lookup(req.Alias)
 	srv.Mux.mu.RUnlock()
-	if !ok {
-		return nil, status.Error(codes.NotFound, "terminal not found")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := srv.authorize(ctx, term, req.Token, api.TerminalAccessScope_read_write); err != nil {
+		return nil, err
 	}
 
 	n, err := term.PTY.Write(req.Stdin)
@@ -300,10 +517,15 @@ func (srv *MuxTerminalService) Write(ctx context.Context, req *api.WriteTerminal
 // SetSize sets the terminal's size.
 func (srv *MuxTerminalService) SetSize(ctx context.Context, req *api.SetTerminalSizeRequest) (*api.SetTerminalSizeResponse, error) {
 	srv.Mux.mu.RLock()
-	term, ok := srv.Mux.terms[req.Alias]
+	_, term, err := srv.lookup(req.Alias)
 	srv.Mux.mu.RUnlock()
-	if !ok {
-		return nil, status.Error(codes.NotFound, "terminal not found")
+	if err != nil {
+		return nil, err
+	}
+
+	// the token of the request is the starter token, not an access token
+	if _, err := srv.authorize(ctx, term, "", api.TerminalAccessScope_read_write); err != nil {
+		return nil, err
 	}
 
 	// Setting the size only works with the starter token or when forcing it.
@@ -312,7 +534,7 @@ func (srv *MuxTerminalService) SetSize(ctx context.Context, req *api.SetTerminal
 		return nil, status.Error(codes.FailedPrecondition, "wrong token or force not set")
 	}
 
-	err := pty.Setsize(term.PTY, &pty.Winsize{
+	err = term.Resize(&pty.Winsize{
 		Cols: uint16(req.Size.Cols),
 		Rows: uint16(req.Size.Rows),
 		X:    uint16(req.Size.WidthPx),
@@ -327,11 +549,15 @@ func (srv *MuxTerminalService) SetSize(ctx context.Context, req *api.SetTerminal
 
 // SetTitle sets the terminal's title.
 func (srv *MuxTerminalService) SetTitle(ctx context.Context, req *api.SetTerminalTitleRequest) (*api.SetTerminalTitleResponse, error) {
+	if err := srv.authorizeOwner(ctx); err != nil {
+		return nil, err
+	}
+
 	srv.Mux.mu.RLock()
-	term, ok := srv.Mux.terms[req.Alias]
+	_, term, err := srv.lookup(req.Alias)
 	srv.Mux.mu.RUnlock()
-	if !ok {
-		return nil, status.Error(codes.NotFound, "terminal not found")
+	if err != nil {
+		return nil, err
 	}
 	term.SetTitle(req.Title)
 	return &api.SetTerminalTitleResponse{}, nil
@@ -339,12 +565,315 @@ func (srv *MuxTerminalService) SetTitle(ctx context.Context, req *api.SetTermina
 
 // UpdateAnnotations sets the terminal's title.
 func (srv *MuxTerminalService) UpdateAnnotations(ctx context.Context, req *api.UpdateTerminalAnnotationsRequest) (*api.UpdateTerminalAnnotationsResponse, error) {
+	if err := srv.authorizeOwner(ctx); err != nil {
+		return nil, err
+	}
+
 	srv.Mux.mu.RLock()
-	term, ok := srv.Mux.terms[req.Alias]
+	_, term, err := srv.lookup(req.Alias)
 	srv.Mux.mu.RUnlock()
-	if !ok {
-		return nil, status.Error(codes.NotFound, "terminal not found")
+	if err != nil {
+		return nil, err
 	}
 	term.UpdateAnnotations(req.Changed, req.Deleted)
 	return &api.UpdateTerminalAnnotationsResponse{}, nil
 }
+
+// SetRecording starts or stops recording the terminal session.
+func (srv *MuxTerminalService) SetRecording(ctx context.Context, req *api.SetTerminalRecordingRequest) (*api.SetTerminalRecordingResponse, error) {
+	if err := srv.authorizeOwner(ctx); err != nil {
+		return nil, err
+	}
+
+	srv.Mux.mu.RLock()
+	_, term, err := srv.lookup(req.Alias)
+	srv.Mux.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.Enabled {
+		if err := term.StopRecording(); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return &api.SetTerminalRecordingResponse{}, nil
+	}
+
+	if err := term.StartRecording(srv.newRecordingPath()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &api.SetTerminalRecordingResponse{Path: term.RecordingPath()}, nil
+}
+
+// Export returns the terminal's backlog in the requested format.
+func (srv *MuxTerminalService) Export(ctx context.Context, req *api.ExportTerminalRequest) (*api.ExportTerminalResponse, error) {
+	srv.Mux.mu.RLock()
+	_, term, err := srv.lookup(req.Alias)
+	srv.Mux.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := srv.authorize(ctx, term, "", api.TerminalAccessScope_read_only); err != nil {
+		return nil, err
+	}
+
+	backlog := term.Backlog()
+	title, _, _ := term.GetTitle()
+
+	var content []byte
+	switch req.Format {
+	case api.TerminalExportFormat_text:
+		content = StripANSI(backlog)
+	case api.TerminalExportFormat_html:
+		content = RenderHTML(backlog, title)
+	case api.TerminalExportFormat_cast:
+		header := castHeader{Width: DEFAULT_COLS, Height: DEFAULT_ROWS, Title: title}
+		if size, err := pty.GetsizeFull(term.PTY); err == nil {
+			header.Width = int(size.Cols)
+			header.Height = int(size.Rows)
+		}
+		var buf bytes.Buffer
+		if err := WriteCast(&buf, header, backlog); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		content = buf.Bytes()
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported export format %v", req.Format)
+	}
+	return &api.ExportTerminalResponse{Content: content}, nil
+}
+
+// ShareTerminal grants access to a terminal through a token.
+func (srv *MuxTerminalService) ShareTerminal(ctx context.Context, req *api.ShareTerminalRequest) (*api.ShareTerminalResponse, error) {
+	if err := srv.authorizeOwner(ctx); err != nil {
+		return nil, err
+	}
+
+	srv.Mux.mu.RLock()
+	alias, term, err := srv.lookup(req.Alias)
+	srv.Mux.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if req.TtlSeconds < 0 {
+		return nil, status.Error(codes.InvalidArgument, "ttl must not be negative")
+	}
+
+	token, expires, err := term.Share(req.Name, req.Scope, time.Duration(req.TtlSeconds)*time.Second)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	log.WithField("alias", alias).WithField("name", req.Name).WithField("scope", req.Scope).Info("terminal shared")
+	return &api.ShareTerminalResponse{
+		Token:     token,
+		Scope:     req.Scope,
+		ExpiresAt: expires.Unix(),
+	}, nil
+}
+
+// RevokeTerminalShare revokes a token created by ShareTerminal.
+func (srv *MuxTerminalService) RevokeTerminalShare(ctx context.Context, req *api.RevokeTerminalShareRequest) (*api.RevokeTerminalShareResponse, error) {
+	if err := srv.authorizeOwner(ctx); err != nil {
+		return nil, err
+	}
+
+	srv.Mux.mu.RLock()
+	_, term, err := srv.lookup(req.Alias)
+	srv.Mux.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if !term.Revoke(req.Token) {
+		return nil, status.Error(codes.NotFound, "token not found")
+	}
+	return &api.RevokeTerminalShareResponse{}, nil
+}
+
+// readBacklogMaxSize is the maximum number of bytes returned by a single ReadBacklog call.
+const readBacklogMaxSize = 1 << 20
+
+// ReadBacklog returns a range of the terminal's output.
+func (srv *MuxTerminalService) ReadBacklog(ctx context.Context, req *api.ReadTerminalBacklogRequest) (*api.ReadTerminalBacklogResponse, error) {
+	srv.Mux.mu.RLock()
+	_, term, err := srv.lookup(req.Alias)
+	srv.Mux.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := srv.authorize(ctx, term, req.Token, api.TerminalAccessScope_read_only); err != nil {
+		return nil, err
+	}
+	if req.From < 0 || req.To < 0 || (req.To != 0 && req.To < req.From) {
+		return nil, status.Error(codes.InvalidArgument, "invalid backlog range")
+	}
+
+	to := req.To
+	if to == 0 || to-req.From > readBacklogMaxSize {
+		to = req.From + readBacklogMaxSize
+	}
+	data, start, total, err := term.ReadBacklog(req.From, to)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	first, _ := term.BacklogRange()
+	return &api.ReadTerminalBacklogResponse{
+		Data:  data,
+		From:  start,
+		To:    start + int64(len(data)),
+		First: first,
+		Total: total,
+	}, nil
+}
+
+// Search looks for lines matching a regular expression in the output of terminals.
+func (srv *MuxTerminalService) Search(ctx context.Context, req *api.SearchTerminalsRequest) (*api.SearchTerminalsResponse, error) {
+	pattern := req.Pattern
+	if req.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid pattern: %v", err)
+	}
+	limit := int(req.MaxMatches)
+	if limit == 0 {
+		limit = defaultSearchMaxMatches
+	}
+
+	srv.Mux.mu.RLock()
+	aliases := srv.Mux.aliases
+	if req.Alias != "" {
+		alias, term, err := srv.lookup(req.Alias)
+		if err != nil {
+			srv.Mux.mu.RUnlock()
+			return nil, err
+		}
+		if _, err := srv.authorize(ctx, term, "", api.TerminalAccessScope_read_only); err != nil {
+			srv.Mux.mu.RUnlock()
+			return nil, err
+		}
+		aliases = []string{alias}
+	}
+	// only the terminals the caller has access to are searched
+	var (
+		terms    = make([]*Term, 0, len(aliases))
+		readable = make([]string, 0, len(aliases))
+	)
+	for _, alias := range aliases {
+		term := srv.Mux.terms[alias]
+		if term == nil {
+			continue
+		}
+		if _, err := srv.authorize(ctx, term, "", api.TerminalAccessScope_read_only); err != nil {
+			continue
+		}
+		terms = append(terms, term)
+		readable = append(readable, alias)
+	}
+	aliases = readable
+	srv.Mux.mu.RUnlock()
+
+	res := &api.SearchTerminalsResponse{}
+	for i, term := range terms {
+		if term == nil {
+			continue
+		}
+		matches, truncated, err := term.Search(re, int(req.Context), limit-len(res.Matches))
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		title, _, _ := term.GetTitle()
+		for _, match := range matches {
+			res.Matches = append(res.Matches, &api.TerminalSearchMatch{
+				Alias:       aliases[i],
+				Title:       title,
+				Line:        match.Line,
+				Before:      match.Before,
+				After:       match.After,
+				Offset:      match.Offset,
+				MatchOffset: match.MatchOffset,
+			})
+		}
+		if truncated {
+			res.Truncated = true
+			break
+		}
+	}
+	return res, nil
+}
+
+// Broadcast writes the same input to several terminals.
+func (srv *MuxTerminalService) Broadcast(ctx context.Context, req *api.BroadcastTerminalRequest) (*api.BroadcastTerminalResponse, error) {
+	if len(req.Aliases) == 0 && len(req.Selector) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "either aliases or a selector is required")
+	}
+
+	// select the terminals, keeping the order of the aliases and then of the mux.
+	// Names and selectors in the aliases are resolved, unresolved ones are reported as failed.
+	type target struct {
+		alias string
+		term  *Term
+		err   error
+	}
+	srv.Mux.mu.RLock()
+	var (
+		targets  []target
+		selected = make(map[string]bool)
+	)
+	for _, ref := range req.Aliases {
+		alias, term, err := srv.lookup(ref)
+		if err != nil {
+			targets = append(targets, target{alias: ref, err: err})
+			continue
+		}
+		if !selected[alias] {
+			targets = append(targets, target{alias: alias, term: term})
+			selected[alias] = true
+		}
+	}
+	for _, alias := range srv.Mux.aliases {
+		term, ok := srv.Mux.terms[alias]
+		if !ok || selected[alias] || !term.MatchAnnotations(req.Selector) {
+			continue
+		}
+		// the selector only matches terminals the caller may write to
+		if _, err := srv.authorize(ctx, term, "", api.TerminalAccessScope_read_write); err != nil {
+			continue
+		}
+		targets = append(targets, target{alias: alias, term: term})
+		selected[alias] = true
+	}
+	srv.Mux.mu.RUnlock()
+
+	// write to all terminals concurrently, s.t. a blocked terminal doesn't delay the others
+	results := make([]*api.TerminalBroadcastResult, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		results[i] = &api.TerminalBroadcastResult{Alias: t.alias}
+		err := t.err
+		if err == nil {
+			_, err = srv.authorize(ctx, t.term, "", api.TerminalAccessScope_read_write)
+		}
+		if err != nil {
+			st := status.Convert(err)
+			results[i].Code = uint32(st.Code())
+			results[i].Error = st.Message()
+			continue
+		}
+		term := t.term
+		wg.Add(1)
+		go func(res *api.TerminalBroadcastResult, term *Term) {
+			defer wg.Done()
+			n, err := term.PTY.Write(req.Stdin)
+			res.BytesWritten = uint32(n)
+			if err != nil {
+				res.Code = uint32(codes.Internal)
+				res.Error = err.Error()
+			}
+		}(results[i], term)
+	}
+	wg.Wait()
+
+	log.WithField("terminals", len(results)).Debug("broadcast input to terminals")
+	return &api.BroadcastTerminalResponse{Results: results}, nil
+}
@@ -1,5 +1,10 @@
 package terminal
 
+import (
+	"os/exec"
+	"syscall"
+)
+
 // setAmbientCaps applies DefaultAmbientCaps on Linux.
 func (srv *MuxTerminalService) setAmbientCaps(cmd *exec.Cmd) {
 	if srv.DefaultAmbientCaps != nil {
@@ -0,0 +1,197 @@
+package terminal
+
+import (
+	"common/util"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"supervisor/api"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// defaultShareTTL is the lifetime of a share token if none is requested.
+const defaultShareTTL = time.Hour
+
+var (
+	// ErrInvalidToken means the share token is unknown or has expired.
+	ErrInvalidToken = errors.New("invalid or expired token")
+	// ErrReadOnly means the share token does not allow writing to the terminal.
+	ErrReadOnly = errors.New("token does not grant write access")
+)
+
+// accessGrant is an access token for a single terminal.
+type accessGrant struct {
+	token   string
+	name    string
+	scope   api.TerminalAccessScope
+	expires time.Time
+	// revoked is closed once the grant has been revoked
+	revoked chan struct{}
+}
+
+// terminalViewer is a client connected through an access grant.
+type terminalViewer struct {
+	grant *accessGrant
+	since time.Time
+}
+
+// Share creates a new access grant for the terminal and returns its token and expiry.
+func (term *Term) Share(name string, scope api.TerminalAccessScope, ttl time.Duration) (token string, expires time.Time, err error) {
+	if ttl <= 0 {
+		ttl = defaultShareTTL
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
+	grant := &accessGrant{
+		token:   hex.EncodeToString(b),
+		name:    name,
+		scope:   scope,
+		expires: time.Now().Add(ttl),
+		revoked: make(chan struct{}),
+	}
+
+	term.mu.Lock()
+	defer term.mu.Unlock()
+	term.removeExpiredGrants()
+	if term.grants == nil {
+		term.grants = make(map[string]*accessGrant)
+	}
+	term.grants[grant.token] = grant
+	return grant.token, grant.expires, nil
+}
+
+// Revoke removes an access grant and disconnects all viewers using it.
+func (term *Term) Revoke(token string) bool {
+	term.mu.Lock()
+	defer term.mu.Unlock()
+	grant, ok := term.grants[token]
+	if !ok {
+		return false
+	}
+	delete(term.grants, token)
+	close(grant.revoked)
+	return true
+}
+
+// callerToken returns the token the client passed in the metadata of the call.
+func callerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(util.OwnerTokenMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// isOwner returns true if the token is the owner token of the service, or if it is empty
+// and the owner token is not required.
+func (srv *MuxTerminalService) isOwner(token string) bool {
+	if token == "" && !srv.RequireOwnerToken {
+		return true
+	}
+	return srv.OwnerToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(srv.OwnerToken)) == 1
+}
+
+// authorizeOwner checks that the caller passed the owner token.
+func (srv *MuxTerminalService) authorizeOwner(ctx context.Context) error {
+	if !srv.isOwner(callerToken(ctx)) {
+		return status.Error(codes.Unauthenticated, "owner token required")
+	}
+	return nil
+}
+
+// authorize checks that the token grants at least the given scope on the terminal.
+// The token of the call's metadata is used if token is empty. The grant is nil for the owner.
+func (srv *MuxTerminalService) authorize(ctx context.Context, term *Term, token string, scope api.TerminalAccessScope) (*accessGrant, error) {
+	if token == "" {
+		token = callerToken(ctx)
+	}
+	if srv.isOwner(token) {
+		return nil, nil
+	}
+	grant, err := term.authorize(token, scope)
+	if errors.Is(err, ErrReadOnly) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return grant, nil
+}
+
+// authorize checks that the share token grants at least the given scope.
+func (term *Term) authorize(token string, scope api.TerminalAccessScope) (*accessGrant, error) {
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+
+	term.mu.RLock()
+	defer term.mu.RUnlock()
+	grant, ok := term.grants[token]
+	if !ok || time.Now().After(grant.expires) {
+		return nil, ErrInvalidToken
+	}
+	if scope == api.TerminalAccessScope_read_write && grant.scope != api.TerminalAccessScope_read_write {
+		return nil, ErrReadOnly
+	}
+	return grant, nil
+}
+
+// addViewer registers a client connected through the grant and returns a function to unregister it.
+func (term *Term) addViewer(grant *accessGrant) (remove func()) {
+	viewer := &terminalViewer{grant: grant, since: time.Now()}
+
+	term.mu.Lock()
+	defer term.mu.Unlock()
+	if term.viewers == nil {
+		term.viewers = make(map[*terminalViewer]struct{})
+	}
+	term.viewers[viewer] = struct{}{}
+
+	return func() {
+		term.mu.Lock()
+		defer term.mu.Unlock()
+		delete(term.viewers, viewer)
+	}
+}
+
+// GetViewers returns the clients currently connected through an access grant.
+func (term *Term) GetViewers() []*api.TerminalViewer {
+	term.mu.RLock()
+	defer term.mu.RUnlock()
+	res := make([]*api.TerminalViewer, 0, len(term.viewers))
+	for viewer := range term.viewers {
+		res = append(res, &api.TerminalViewer{
+			Name:      viewer.grant.name,
+			Scope:     viewer.grant.scope,
+			Since:     viewer.since.Unix(),
+			ExpiresAt: viewer.grant.expires.Unix(),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Since < res[j].Since })
+	return res
+}
+
+// removeExpiredGrants drops all grants past their expiry. Callers are expected to hold mu.
+func (term *Term) removeExpiredGrants() {
+	now := time.Now()
+	for token, grant := range term.grants {
+		if now.After(grant.expires) {
+			delete(term.grants, token)
+			close(grant.revoked)
+		}
+	}
+}
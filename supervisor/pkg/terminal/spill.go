@@ -0,0 +1,308 @@
+package terminal
+
+import (
+	"common/log"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// defaultSpillMaxSize caps the disk usage of all spill files if SpillStore.MaxSize is not set.
+	defaultSpillMaxSize = 256 << 20
+	// maxSpillSegmentSize is the size at which a spill file starts a new segment.
+	// Output is evicted from disk a whole segment at a time.
+	maxSpillSegmentSize = 1 << 20
+)
+
+// SpillStore keeps the complete output of terminals on disk, s.t. it can be read
+// back once it has been evicted from the in-memory backlog. The total size of all
+// spill files is capped at MaxSize; once exceeded, the oldest output of the terminal
+// using the most disk space is dropped.
+type SpillStore struct {
+	Dir     string
+	MaxSize int64
+
+	mu    sync.Mutex
+	files map[*spillFile]struct{}
+	used  int64
+}
+
+// spillFile is the on-disk output of a single terminal. It is split into segments,
+// s.t. old output can be dropped without rewriting the file.
+type spillFile struct {
+	store *SpillStore
+	alias string
+
+	mu       sync.Mutex
+	segments []*spillSegment
+	// end is the offset right after the last byte written
+	end     int64
+	current *os.File
+	next    int
+	closed  bool
+}
+
+type spillSegment struct {
+	path  string
+	start int64
+	size  int64
+}
+
+// open creates a spill file for the terminal. Output written to it is counted
+// from offset start on.
+func (s *SpillStore) open(alias string, start int64) (*spillFile, error) {
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("cannot create spill directory: %w", err)
+	}
+	f := &spillFile{
+		store: s,
+		alias: alias,
+		end:   start,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.files == nil {
+		s.files = make(map[*spillFile]struct{})
+	}
+	s.files[f] = struct{}{}
+	return f, nil
+}
+
+// Used returns the disk space used by all spill files.
+func (s *SpillStore) Used() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.used
+}
+
+func (s *SpillStore) maxSize() int64 {
+	if s.MaxSize <= 0 {
+		return defaultSpillMaxSize
+	}
+	return s.MaxSize
+}
+
+// segmentSize keeps at least four segments within the disk limit, s.t. evicting a
+// segment doesn't drop most of the output at once.
+func (s *SpillStore) segmentSize() int64 {
+	return max(min(maxSpillSegmentSize, s.maxSize()/4), 1)
+}
+
+// grow accounts for n more bytes on disk and evicts old segments if the limit is exceeded.
+func (s *SpillStore) grow(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used += n
+
+	for s.used > s.maxSize() {
+		var (
+			victim     *spillFile
+			victimSize int64
+		)
+		for f := range s.files {
+			if size := f.size(); size > victimSize {
+				victim, victimSize = f, size
+			}
+		}
+		if victim == nil {
+			return
+		}
+		freed := victim.evictOldest()
+		if freed == 0 {
+			return
+		}
+		s.used -= freed
+	}
+}
+
+// release removes the spill file from the store and frees its disk space.
+func (s *SpillStore) release(f *spillFile, size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, f)
+	s.used -= size
+}
+
+// Write appends terminal output to the spill file.
+func (f *spillFile) Write(p []byte) (int, error) {
+	segmentSize := f.store.segmentSize()
+
+	f.mu.Lock()
+	var written int
+	for written < len(p) {
+		if f.closed {
+			f.mu.Unlock()
+			return written, os.ErrClosed
+		}
+		seg := f.last()
+		if f.current == nil || seg.size >= segmentSize {
+			if err := f.rotate(); err != nil {
+				f.mu.Unlock()
+				f.store.grow(int64(written))
+				return written, err
+			}
+			seg = f.last()
+		}
+
+		chunk := p[written:min(len(p), written+int(segmentSize-seg.size))]
+		n, err := f.current.Write(chunk)
+		seg.size += int64(n)
+		f.end += int64(n)
+		written += n
+		if err != nil {
+			f.mu.Unlock()
+			f.store.grow(int64(written))
+			return written, err
+		}
+	}
+	f.mu.Unlock()
+
+	f.store.grow(int64(written))
+	return written, nil
+}
+
+// rotate starts a new segment. Callers are expected to hold mu.
+func (f *spillFile) rotate() error {
+	if f.current != nil {
+		_ = f.current.Close()
+		f.current = nil
+	}
+	path := filepath.Join(f.store.Dir, fmt.Sprintf("%s.%d.spill", f.alias, f.next))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	f.next++
+	f.current = file
+	f.segments = append(f.segments, &spillSegment{path: path, start: f.end})
+	return nil
+}
+
+// last returns the segment written to. Callers are expected to hold mu.
+func (f *spillFile) last() *spillSegment {
+	if len(f.segments) == 0 {
+		return &spillSegment{}
+	}
+	return f.segments[len(f.segments)-1]
+}
+
+// Range returns the offsets of the output available on disk.
+func (f *spillFile) Range() (start, end int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.segments) == 0 {
+		return f.end, f.end
+	}
+	return f.segments[0].start, f.end
+}
+
+// ReadRange reads the output between the offsets from and to, clipped to the
+// output available on disk. It returns the data and the offset of its first byte.
+func (f *spillFile) ReadRange(from, to int64) ([]byte, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.segments) > 0 {
+		from = max(from, f.segments[0].start)
+	}
+	to = min(to, f.end)
+	if from >= to {
+		return nil, from, nil
+	}
+
+	res := make([]byte, 0, to-from)
+	for _, seg := range f.segments {
+		segEnd := seg.start + seg.size
+		if segEnd <= from || seg.start >= to {
+			continue
+		}
+		start, end := max(from, seg.start), min(to, segEnd)
+
+		file, err := os.Open(seg.path)
+		if err != nil {
+			return nil, from, err
+		}
+		buf := make([]byte, end-start)
+		_, err = file.ReadAt(buf, start-seg.start)
+		_ = file.Close()
+		if err != nil && err != io.EOF {
+			return nil, from, err
+		}
+		res = append(res, buf...)
+	}
+	return res, from, nil
+}
+
+// size returns the disk space used by the spill file.
+func (f *spillFile) size() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var res int64
+	for _, seg := range f.segments {
+		res += seg.size
+	}
+	return res
+}
+
+// evictOldest removes the oldest segment and returns the number of bytes freed.
+// The segment written to is truncated instead if it is the only one.
+func (f *spillFile) evictOldest() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.segments) == 0 {
+		return 0
+	}
+
+	seg := f.segments[0]
+	if len(f.segments) == 1 {
+		if f.current == nil || seg.size == 0 {
+			return 0
+		}
+		if err := f.current.Truncate(0); err != nil {
+			log.WithError(err).WithField("alias", f.alias).Warn("cannot truncate terminal spill file")
+			return 0
+		}
+		_, _ = f.current.Seek(0, io.SeekStart)
+		freed := seg.size
+		seg.start, seg.size = f.end, 0
+		return freed
+	}
+
+	if err := os.Remove(seg.path); err != nil && !os.IsNotExist(err) {
+		log.WithError(err).WithField("alias", f.alias).Warn("cannot remove terminal spill segment")
+	}
+	f.segments = f.segments[1:]
+	return seg.size
+}
+
+// Close removes the spill file from disk.
+func (f *spillFile) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+	f.closed = true
+	if f.current != nil {
+		_ = f.current.Close()
+		f.current = nil
+	}
+	var (
+		size int64
+		err  error
+	)
+	for _, seg := range f.segments {
+		size += seg.size
+		if rerr := os.Remove(seg.path); rerr != nil && !os.IsNotExist(rerr) {
+			err = rerr
+		}
+	}
+	f.segments = nil
+	f.mu.Unlock()
+
+	f.store.release(f, size)
+	return err
+}
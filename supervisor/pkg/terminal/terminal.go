@@ -35,6 +35,7 @@ const (
 func NewMux() *Mux {
 	return &Mux{
 		terms: make(map[string]*Term),
+		names: make(map[string]string),
 	}
 }
 
@@ -42,7 +43,24 @@ func NewMux() *Mux {
 type Mux struct {
 	aliases []string
 	terms   map[string]*Term
-	mu      sync.RWMutex
+	// names maps the names given to terminals to their alias
+	names map[string]string
+	mu    sync.RWMutex
+
+	// Limits cap the number of terminals Start runs
+	Limits TerminalLimits
+
+	// PrepareStart is called with the command and the annotations of every new terminal before
+	// its process is started, e.g. to start it in a cgroup. release is called once it started.
+	PrepareStart func(cmd *exec.Cmd, annotations map[string]string) (release func())
+
+	// OnOutput is called whenever the process of a terminal writes output,
+	// e.g. to keep the workspace from hibernating
+	OnOutput func()
+
+	// OnExit is called with the annotations and the exit code of every terminal whose process
+	// exited, once the terminal has been closed. The exit code is -1 if the process was killed.
+	OnExit func(alias string, annotations map[string]string, exitCode int)
 }
 
 // Get returns a terminal for the given alias.
@@ -56,21 +74,48 @@ func (m *Mux) Get(alias string) (*Term, bool) {
 // Start starts a new command in its own pseudo-terminal and returns an alias
 // for that pseudo terminal.
 func (m *Mux) Start(cmd *exec.Cmd, options TermOptions) (alias string, err error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	uid, err := uuid.NewRandom()
 	if err != nil {
 		return "", fmt.Errorf("cannot produce alias: %w", err)
 	}
 	alias = uid.String()
 
-	term, err := newTerm(alias, cmd, options)
+	err = m.startWithAlias(alias, cmd, options)
 	if err != nil {
 		return "", err
 	}
+	return alias, nil
+}
+
+// startWithAlias starts a new command in its own pseudo-terminal using the given alias.
+func (m *Mux) startWithAlias(alias string, cmd *exec.Cmd, options TermOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.terms[alias]; exists {
+		return fmt.Errorf("terminal %s already exists", alias)
+	}
+	if err := m.reserveName(options.Name); err != nil {
+		return err
+	}
+	if err := m.checkLimits(options.Annotations); err != nil {
+		return err
+	}
+
+	options.onOutput = m.OnOutput
+	if m.PrepareStart != nil {
+		release := m.PrepareStart(cmd, options.Annotations)
+		defer release()
+	}
+	term, err := newTerm(alias, cmd, options)
+	if err != nil {
+		return err
+	}
 	m.aliases = append(m.aliases, alias)
 	m.terms[alias] = term
+	if term.Name != "" {
+		m.names[term.Name] = alias
+	}
 
 	log.WithField("alias", alias).WithField("cmd", cmd.Path).Info("started new terminal")
 
@@ -78,9 +123,12 @@ func (m *Mux) Start(cmd *exec.Cmd, options TermOptions) (alias string, err error
 		term.waitErr = cmd.Wait()
 		close(term.waitDone)
 		_ = m.CloseTerminal(context.Background(), alias, false)
+		if m.OnExit != nil {
+			m.OnExit(alias, term.GetAnnotations(), cmd.ProcessState.ExitCode())
+		}
 	}()
 
-	return alias, nil
+	return nil
 }
 
 // Close closes all terminals.
@@ -108,6 +156,9 @@ func (m *Mux) Close(ctx context.Context) {
 	for k := range m.terms {
 		delete(m.terms, k)
 	}
+	for k := range m.names {
+		delete(m.names, k)
+	}
 }
 
 // CloseTerminal closes a terminal and ends the process that runs in it.
@@ -149,11 +200,14 @@ func (m *Mux) doClose(ctx context.Context, alias string, forceSuccess bool) erro
 		m.aliases = append(m.aliases[:i], m.aliases[i+1:]...)
 	}
 	delete(m.terms, alias)
+	if term.Name != "" {
+		delete(m.names, term.Name)
+	}
 
 	return nil
 }
 
-// terminalBacklogSize is the number of bytes of output we'll store in RAM for each terminal.
+// terminalBacklogSize is the default number of bytes of output we'll store in RAM for each terminal.
 // The higher this number is, the better the UX, but the higher the resource requirements are.
 // For now, we assume an average of five terminals per workspace, which makes this consume 1MiB of RAM.
 const terminalBacklogSize = 256 << 10
@@ -164,10 +218,27 @@ func newTerm(alias string, cmd *exec.Cmd, options TermOptions) (*Term, error) {
 		return nil, err
 	}
 
-	recorder, err := NewRingBuffer(terminalBacklogSize)
+	backlogSize := options.BacklogSize
+	if backlogSize <= 0 {
+		backlogSize = terminalBacklogSize
+	}
+	recorder, err := NewRingBuffer(backlogSize)
 	if err != nil {
 		return nil, err
 	}
+	var spill *spillFile
+	if options.Spill != nil {
+		spill, err = options.Spill.open(alias, 0)
+		if err != nil {
+			log.WithError(err).WithField("alias", alias).Warn("cannot spill terminal backlog to disk")
+		}
+	}
+	if len(options.History) > 0 {
+		_, _ = recorder.Write(options.History)
+		if spill != nil {
+			_, _ = spill.Write(options.History)
+		}
+	}
 
 	timeout := options.ReadTimeout
 	if timeout == 0 {
@@ -250,6 +321,9 @@ func newTerm(alias string, cmd *exec.Cmd, options TermOptions) (*Term, error) {
 	if err := cmd.Start(); err != nil {
 		_ = pts.Close()
 		_ = pty.Close()
+		if spill != nil {
+			_ = spill.Close()
+		}
 		return nil, err
 	}
 
@@ -261,22 +335,44 @@ func newTerm(alias string, cmd *exec.Cmd, options TermOptions) (*Term, error) {
 			timeout:   timeout,
 			listener:  make(map[*multiWriterListener]struct{}),
 			recorder:  recorder,
+			spill:     spill,
+			osc52:     &osc52Scanner{},
 			logStdout: options.LogToStdout,
 			logLabel:  alias,
+			onOutput:  options.onOutput,
 		},
 		annotations:  annotations,
 		defaultTitle: options.Title,
 
 		StarterToken: token.String(),
+		Name:         options.Name,
+		IdleTimeout:  options.IdleTimeout,
+		lastActive:   time.Now(),
 
 		waitDone: make(chan struct{}),
 	}
 
+	res.Stdout.onClipboard = func(event ClipboardEvent) {
+		res.notifyClipboard(event)
+		if options.OnClipboard != nil {
+			options.OnClipboard(alias, event)
+		}
+	}
+
+	if options.RecordPath != "" {
+		if err := res.StartRecording(options.RecordPath); err != nil {
+			log.WithError(err).WithField("alias", alias).Warn("cannot record terminal")
+		}
+	}
+
 	//nolint:errcheck
 	go io.Copy(res.Stdout, pty)
 	return res, nil
 }
 
+// TaskAnnotation is the annotation of terminals which run a task, its value is the name of the task.
+const TaskAnnotation = "task"
+
 // NoTimeout means that listener can block read forever
 var NoTimeout time.Duration = 1<<63 - 1
 
@@ -288,6 +384,13 @@ type TermOptions struct {
 	// Annotations are user-defined metadata that's attached to a terminal
 	Annotations map[string]string
 
+	// Name is a unique name the terminal can be resolved by in addition to its alias.
+	Name string
+
+	// IdleTimeout closes the terminal once it has been idle for that long, see Mux.ReapIdleTerminals.
+	// Use 0 to keep the terminal open.
+	IdleTimeout time.Duration
+
 	// Size describes the terminal size.
 	Size *_pty.Winsize
 
@@ -296,6 +399,29 @@ type TermOptions struct {
 
 	// LogToStdout forwards the terminal's stdout to supervisor's stdout
 	LogToStdout bool
+
+	// RecordPath is the asciicast v2 file the terminal session is recorded to.
+	// Recording is disabled if empty.
+	RecordPath string
+
+	// History is output shown to listeners before anything the process writes,
+	// e.g. the backlog of a restored terminal.
+	History []byte
+
+	// BacklogSize is the number of bytes of output kept in memory.
+	// Use 0 for the default of 256 KiB.
+	BacklogSize int64
+
+	// Spill keeps the complete output on disk, s.t. output evicted from
+	// the in-memory backlog can still be read with ReadBacklog.
+	Spill *SpillStore
+
+	// OnClipboard is called for every clipboard update a program in the terminal
+	// requests through OSC 52.
+	OnClipboard func(alias string, event ClipboardEvent)
+
+	// onOutput is set to Mux.OnOutput when the terminal is started
+	onOutput func()
 }
 
 // Term is a pseudo-terminal.
@@ -305,6 +431,10 @@ type Term struct {
 
 	Command      *exec.Cmd
 	StarterToken string
+	// Name is the unique name given when starting the terminal, if any
+	Name string
+	// IdleTimeout is the idle time after which the terminal is closed, 0 if never
+	IdleTimeout time.Duration
 
 	mu     sync.RWMutex
 	closed bool
@@ -312,6 +442,17 @@ type Term struct {
 	annotations  map[string]string
 	defaultTitle string
 	title        string
+	// lastActive is the last time the terminal had a listener or a foreground process
+	lastActive time.Time
+
+	// grants are the access tokens created through Share
+	grants  map[string]*accessGrant
+	viewers map[*terminalViewer]struct{}
+
+	// clipboardListeners receive the clipboard updates requested through OSC 52.
+	// They are guarded by their own mutex, as they are notified while writing output.
+	clipboardMu        sync.Mutex
+	clipboardListeners map[chan ClipboardEvent]struct{}
 
 	// ForceSuccess overrides the process' exit code to 0
 	ForceSuccess bool
@@ -356,6 +497,22 @@ func (term *Term) GetAnnotations() map[string]string {
 	return annotations
 }
 
+// MatchAnnotations returns true if the terminal has all annotations of the selector.
+// An empty selector matches no terminal.
+func (term *Term) MatchAnnotations(selector map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+	term.mu.RLock()
+	defer term.mu.RUnlock()
+	for k, v := range selector {
+		if actual, ok := term.annotations[k]; !ok || actual != v {
+			return false
+		}
+	}
+	return true
+}
+
 func (term *Term) UpdateAnnotations(changed map[string]string, deleted []string) {
 	term.mu.Lock()
 	defer term.mu.Unlock()
@@ -367,6 +524,103 @@ func (term *Term) UpdateAnnotations(changed map[string]string, deleted []string)
 	}
 }
 
+// StartRecording records the terminal session into an asciicast v2 file at path.
+// It is a no-op if the terminal is already being recorded.
+func (term *Term) StartRecording(path string) error {
+	header := castHeader{
+		Width:  DEFAULT_COLS,
+		Height: DEFAULT_ROWS,
+		Title:  term.defaultTitle,
+		Env: map[string]string{
+			"SHELL": term.Command.Path,
+			"TERM":  "xterm-256color",
+		},
+	}
+	if size, err := _pty.GetsizeFull(term.PTY); err == nil {
+		header.Width = int(size.Cols)
+		header.Height = int(size.Rows)
+	}
+
+	term.Stdout.mu.Lock()
+	defer term.Stdout.mu.Unlock()
+	if term.Stdout.cast != nil {
+		return nil
+	}
+	rec, err := newCastRecorder(path, header)
+	if err != nil {
+		return err
+	}
+	term.Stdout.cast = rec
+	return nil
+}
+
+// StopRecording stops recording the terminal session.
+func (term *Term) StopRecording() error {
+	term.Stdout.mu.Lock()
+	defer term.Stdout.mu.Unlock()
+	if term.Stdout.cast == nil {
+		return nil
+	}
+	err := term.Stdout.cast.Close()
+	term.Stdout.cast = nil
+	return err
+}
+
+// RecordingPath returns the path of the active recording or an empty string.
+func (term *Term) RecordingPath() string {
+	term.Stdout.mu.RLock()
+	defer term.Stdout.mu.RUnlock()
+	if term.Stdout.cast == nil {
+		return ""
+	}
+	return term.Stdout.cast.Path
+}
+
+// Resize changes the size of the pseudo-terminal.
+func (term *Term) Resize(size *_pty.Winsize) error {
+	if err := _pty.Setsize(term.PTY, size); err != nil {
+		return err
+	}
+
+	term.Stdout.mu.RLock()
+	defer term.Stdout.mu.RUnlock()
+	if term.Stdout.cast != nil {
+		_ = term.Stdout.cast.Resize(size.Cols, size.Rows)
+	}
+	return nil
+}
+
+// ReadBacklog returns the output between the offsets from and to, counted from the
+// first byte the terminal has written. The range is clipped to the output available
+// in memory or, if spilled to disk, on disk. It returns the data, the offset of its
+// first byte and the total number of bytes written so far.
+func (term *Term) ReadBacklog(from, to int64) (data []byte, start, total int64, err error) {
+	term.Stdout.mu.RLock()
+	defer term.Stdout.mu.RUnlock()
+	return term.Stdout.readBacklog(from, to)
+}
+
+// BacklogRange returns the offsets of the output which can be read with ReadBacklog.
+func (term *Term) BacklogRange() (first, total int64) {
+	term.Stdout.mu.RLock()
+	defer term.Stdout.mu.RUnlock()
+
+	total = term.Stdout.recorder.TotalWritten()
+	first = total - int64(len(term.Stdout.recorder.Bytes()))
+	if term.Stdout.spill != nil {
+		diskStart, _ := term.Stdout.spill.Range()
+		first = min(first, diskStart)
+	}
+	return first, total
+}
+
+// Backlog returns a copy of the recorded terminal output.
+func (term *Term) Backlog() []byte {
+	term.Stdout.mu.RLock()
+	defer term.Stdout.mu.RUnlock()
+	return bytes.Clone(term.Stdout.recorder.Bytes())
+}
+
 func (term *Term) resolveForegroundCommand() (string, error) {
 	pgrp, err := unix.IoctlGetInt(int(term.PTY.Fd()), unix.TIOCGPGRP)
 	if err != nil {
@@ -413,6 +667,9 @@ func (term *Term) Close(ctx context.Context) error {
 	}
 
 	writeErr := term.Stdout.Close()
+	if err := term.StopRecording(); err != nil {
+		log.WithError(err).Warn("cannot close terminal recording")
+	}
 
 	slaveErr := errors.New("Slave FD nil")
 	if term.pts != nil {
@@ -453,6 +710,17 @@ type multiWriter struct {
 	// ring buffer to record last 256kb of pty output
 	// new listener is initialized with the latest recodring first
 	recorder *RingBuffer
+	// cast records the output into an asciicast file if the session is being recorded
+	cast *castRecorder
+	// spill keeps the complete output on disk if enabled
+	spill *spillFile
+	// osc52 detects clipboard updates, which are passed to onClipboard
+	osc52       *osc52Scanner
+	onClipboard func(ClipboardEvent)
+	// onOutput is called for every write if set
+	onOutput func()
+	// lastWrite is the time the terminal last wrote output
+	lastWrite time.Time
 
 	logStdout bool
 	logLabel  string
@@ -510,6 +778,10 @@ var closedListener = io.NopCloser(closedTerminalListener{})
 type TermListenOptions struct {
 	// timeout after which a listener is dropped. Use 0 for default timeout.
 	ReadTimeout time.Duration
+
+	// Offset resumes listening at the given offset of the terminal's output instead
+	// of replaying the in-memory backlog. Use 0 to replay the in-memory backlog.
+	Offset int64
 }
 
 // Listen listens in on the multi-writer stream.
@@ -543,6 +815,14 @@ func (mw *multiWriter) ListenWithOptions(options TermListenOptions) io.ReadClose
 	}
 
 	recording := mw.recorder.Bytes()
+	if options.Offset > 0 {
+		var err error
+		recording, _, _, err = mw.readBacklog(options.Offset, 0)
+		if err != nil {
+			log.WithError(err).WithField("label", mw.logLabel).Warn("cannot read terminal backlog")
+			recording = mw.recorder.Bytes()
+		}
+	}
 	go func() {
 		_, _ = w.Write(recording)
 
@@ -583,11 +863,64 @@ func (mw *multiWriter) ListenWithOptions(options TermListenOptions) io.ReadClose
 	return res
 }
 
+// readBacklog implements Term.ReadBacklog. Callers are expected to hold mu.
+func (mw *multiWriter) readBacklog(from, to int64) (data []byte, start, total int64, err error) {
+	total = mw.recorder.TotalWritten()
+	if to <= 0 || to > total {
+		to = total
+	}
+	inMemory := mw.recorder.Bytes()
+	memStart := total - int64(len(inMemory))
+	if from < memStart && mw.spill != nil {
+		if diskStart, _ := mw.spill.Range(); diskStart < memStart {
+			// read the range from disk, completing it with the in-memory backlog
+			data, start, err := mw.spill.ReadRange(from, min(to, memStart))
+			if err != nil {
+				return nil, 0, total, err
+			}
+			if end := start + int64(len(data)); end < to && end >= memStart {
+				data = append(data, inMemory[end-memStart:to-memStart]...)
+			}
+			return data, start, total, nil
+		}
+	}
+
+	from = max(from, memStart)
+	if from >= to {
+		return nil, from, total, nil
+	}
+	return bytes.Clone(inMemory[from-memStart : to-memStart]), from, total, nil
+}
+
 func (mw *multiWriter) Write(p []byte) (n int, err error) {
+	if mw.onOutput != nil {
+		mw.onOutput()
+	}
+	if mw.osc52 != nil && mw.onClipboard != nil {
+		for _, event := range mw.osc52.Scan(p) {
+			mw.onClipboard(event)
+		}
+	}
+
 	mw.mu.Lock()
 	defer mw.mu.Unlock()
 
 	mw.recorder.Write(p)
+	mw.lastWrite = time.Now()
+	if mw.spill != nil {
+		if _, err := mw.spill.Write(p); err != nil {
+			log.WithError(err).WithField("label", mw.logLabel).Warn("cannot spill terminal output to disk, stopping spill-over")
+			_ = mw.spill.Close()
+			mw.spill = nil
+		}
+	}
+	if mw.cast != nil {
+		if err := mw.cast.Output(p); err != nil {
+			log.WithError(err).WithField("label", mw.logLabel).Warn("cannot record terminal output, stopping recording")
+			_ = mw.cast.Close()
+			mw.cast = nil
+		}
+	}
 	if mw.logStdout {
 		log.WithFields(logrus.Fields{
 			"terminalOutput": true,
@@ -620,6 +953,10 @@ func (mw *multiWriter) Close() error {
 	defer mw.mu.Unlock()
 
 	mw.closed = true
+	if mw.spill != nil {
+		_ = mw.spill.Close()
+		mw.spill = nil
+	}
 
 	var err error
 	for w := range mw.listener {
@@ -631,6 +968,13 @@ func (mw *multiWriter) Close() error {
 	return err
 }
 
+// lastOutput returns the time the terminal last wrote output.
+func (mw *multiWriter) lastOutput() time.Time {
+	mw.mu.RLock()
+	defer mw.mu.RUnlock()
+	return mw.lastWrite
+}
+
 func (mw *multiWriter) ListenerCount() int {
 	mw.mu.Lock()
 	defer mw.mu.Unlock()
@@ -1,5 +1,11 @@
 package terminal
 
+import (
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
 // setTermAttr applies terminal attributes on Linux using the TCSETS ioctl request.
 // This is a thin wrapper around unix.IoctlSetTermios to allow per-OS differences
 // (Darwin uses a different ioctl code).
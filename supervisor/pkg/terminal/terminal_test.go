@@ -2,18 +2,25 @@ package terminal
 
 import (
 	"bytes"
+	"common/util"
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"strings"
 	"supervisor/api"
+	"supervisor/pkg/config"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 func TestTitle(t *testing.T) {
@@ -55,6 +62,7 @@ func TestTitle(t *testing.T) {
 			defer os.RemoveAll(tmpWorkdir)
 
 			terminalService := NewMuxTerminalService(mux)
+			ctx = asOwner(ctx, terminalService)
 			terminalService.DefaultWorkdir = tmpWorkdir
 
 			term, err := terminalService.OpenWithOptions(ctx, &api.OpenTerminalRequest{}, TermOptions{
@@ -193,6 +201,7 @@ func TestAnnotations(t *testing.T) {
 			defer mux.Close(ctx)
 
 			terminalService := NewMuxTerminalService(mux)
+			ctx = asOwner(ctx, terminalService)
 			var err error
 			if test.Opts == nil {
 				_, err = terminalService.Open(ctx, test.Req)
@@ -244,7 +253,7 @@ func TestTerminals(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.Desc, func(t *testing.T) {
 			terminalService := NewMuxTerminalService(NewMux())
-			resp, err := terminalService.Open(context.Background(), &api.OpenTerminalRequest{})
+			resp, err := terminalService.Open(asOwner(context.Background(), terminalService), &api.OpenTerminalRequest{})
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -325,6 +334,7 @@ func TestWorkDirProvider(t *testing.T) {
 	defer mux.Close(ctx)
 
 	terminalService := NewMuxTerminalService(mux)
+	ctx = asOwner(ctx, terminalService)
 
 	type AssertWorkDirTest struct {
 		expectedWorkDir string
@@ -386,3 +396,918 @@ func TestWorkDirProvider(t *testing.T) {
 		expectedWorkDir: providedWorkDir,
 	})
 }
+
+func TestProfiles(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mux := NewMux()
+	defer mux.Close(ctx)
+
+	workspace, err := os.MkdirTemp("", "workspace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workspace)
+	if err := os.Mkdir(workspace+"/db", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	terminalService := NewMuxTerminalService(mux)
+	ctx = asOwner(ctx, terminalService)
+	terminalService.DefaultWorkdir = workspace
+	terminalService.ProfileProvider = config.RuntimeConfig{
+		Terminals: []config.TerminalProfileConfig{
+			{
+				Name:        "db-shell",
+				Shell:       "/bin/sh",
+				Cwd:         "db",
+				Annotations: map[string]string{"profile": "db-shell", "hello": "foo"},
+				Title:       "database",
+			},
+		},
+	}.TerminalProfile
+
+	_, err = terminalService.Open(ctx, &api.OpenTerminalRequest{Profile: "unknown"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for unknown profile, got %v", err)
+	}
+
+	resp, err := terminalService.Open(ctx, &api.OpenTerminalRequest{
+		Profile:     "db-shell",
+		Annotations: map[string]string{"hello": "world"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"/bin/sh"}, resp.Terminal.Command); diff != "" {
+		t.Errorf("unexpected command (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(workspace+"/db", resp.Terminal.InitialWorkdir); diff != "" {
+		t.Errorf("unexpected workdir (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"profile": "db-shell", "hello": "world"}, resp.Terminal.Annotations); diff != "" {
+		t.Errorf("unexpected annotations (-want +got):\n%s", diff)
+	}
+	if !strings.HasPrefix(resp.Terminal.Title, "database") {
+		t.Errorf("expected title to start with the profile title, got %q", resp.Terminal.Title)
+	}
+}
+
+func TestRestoreTerminals(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	workdir := t.TempDir()
+	store := &TerminalStore{Dir: t.TempDir()}
+
+	mux := NewMux()
+	terminalService := NewMuxTerminalService(mux)
+	ctx = asOwner(ctx, terminalService)
+	resp, err := terminalService.Open(ctx, &api.OpenTerminalRequest{
+		Workdir:     workdir,
+		Shell:       "/bin/sh",
+		Annotations: map[string]string{"tab": "1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alias := resp.Terminal.Alias
+	term, _ := mux.Get(alias)
+	term.SetTitle("build")
+
+	// terminals running a task or a command are not restored
+	for _, req := range []*api.OpenTerminalRequest{
+		{Shell: "/bin/sh", Annotations: map[string]string{TaskAnnotation: "build"}},
+		{Shell: "/bin/sh", ShellArgs: []string{"-c", "exec cat"}},
+	} {
+		if _, err := terminalService.Open(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err = terminalService.Write(ctx, &api.WriteTerminalRequest{Alias: alias, Stdin: []byte("echo restore-marker\n")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for !strings.Contains(string(StripANSI(term.Backlog())), "restore-marker\n") {
+		select {
+		case <-ctx.Done():
+			t.Fatal("terminal output did not arrive")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	terminalService.persistTerminals(store)
+	closeCtx, closeCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	mux.Close(closeCtx)
+	closeCancel()
+
+	restoredMux := NewMux()
+	defer restoredMux.Close(ctx)
+	restoredService := NewMuxTerminalService(restoredMux)
+	restoredService.DefaultShell = "/bin/sh"
+	restored, err := restoredService.RestoreTerminals(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored != 1 {
+		t.Fatalf("expected one restored terminal, got %d", restored)
+	}
+	if stored, err := store.Aliases(); err != nil {
+		t.Fatal(err)
+	} else if diff := cmp.Diff([]string{alias}, stored); diff != "" {
+		t.Errorf("unexpected persisted terminals (-want +got):\n%s", diff)
+	}
+
+	info, err := restoredService.Get(asOwner(ctx, restoredService), &api.GetTerminalRequest{Alias: alias})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]string{"tab": "1"}, info.Annotations); diff != "" {
+		t.Errorf("unexpected annotations (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(workdir, info.InitialWorkdir); diff != "" {
+		t.Errorf("unexpected workdir (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("build", info.Title); diff != "" {
+		t.Errorf("unexpected title (-want +got):\n%s", diff)
+	}
+
+	restoredTerm, _ := restoredMux.Get(alias)
+	if backlog := string(restoredTerm.Backlog()); !strings.Contains(backlog, "restore-marker") || !strings.Contains(backlog, restoredBanner) {
+		t.Errorf("expected previous output in backlog, got %q", backlog)
+	}
+}
+
+func TestShareTerminal(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mux := NewMux()
+	defer mux.Close(ctx)
+
+	terminalService := NewMuxTerminalService(mux)
+	terminalService.RequireOwnerToken = true
+	ctx = asOwner(ctx, terminalService)
+	resp, err := terminalService.Open(ctx, &api.OpenTerminalRequest{Shell: "/bin/sh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alias := resp.Terminal.Alias
+
+	readOnly, err := terminalService.ShareTerminal(ctx, &api.ShareTerminalRequest{Alias: alias, Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	readWrite, err := terminalService.ShareTerminal(ctx, &api.ShareTerminalRequest{Alias: alias, Name: "bob", Scope: api.TerminalAccessScope_read_write})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeTests := []struct {
+		Desc        string
+		Token       string
+		Anonymous   bool
+		Optional    bool
+		Expectation codes.Code
+	}{
+		{Desc: "owner", Token: "", Expectation: codes.OK},
+		{Desc: "read-write token", Token: readWrite.Token, Expectation: codes.OK},
+		{Desc: "read-only token", Token: readOnly.Token, Expectation: codes.PermissionDenied},
+		{Desc: "unknown token", Token: "foobar", Expectation: codes.Unauthenticated},
+		{Desc: "no token", Token: "", Anonymous: true, Expectation: codes.Unauthenticated},
+		{Desc: "no token with optional owner token", Token: "", Anonymous: true, Optional: true, Expectation: codes.OK},
+		{Desc: "read-only token with optional owner token", Token: readOnly.Token, Optional: true, Expectation: codes.PermissionDenied},
+	}
+	for _, test := range writeTests {
+		t.Run(test.Desc, func(t *testing.T) {
+			terminalService.RequireOwnerToken = !test.Optional
+			defer func() { terminalService.RequireOwnerToken = true }()
+			callCtx := ctx
+			if test.Anonymous {
+				callCtx = context.Background()
+			}
+			_, err := terminalService.Write(callCtx, &api.WriteTerminalRequest{Alias: alias, Stdin: []byte("\n"), Token: test.Token})
+			if diff := cmp.Diff(test.Expectation, status.Code(err)); diff != "" {
+				t.Errorf("unexpected status code (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	// a read-only share token passed as metadata only grants read access
+	viewerCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(util.OwnerTokenMetadataKey, readOnly.Token))
+	rpcTests := []struct {
+		Desc        string
+		Call        func(ctx context.Context) error
+		Expectation codes.Code
+	}{
+		{
+			Desc: "get",
+			Call: func(ctx context.Context) error {
+				_, err := terminalService.Get(ctx, &api.GetTerminalRequest{Alias: alias})
+				return err
+			},
+			Expectation: codes.OK,
+		},
+		{
+			Desc: "export",
+			Call: func(ctx context.Context) error {
+				_, err := terminalService.Export(ctx, &api.ExportTerminalRequest{Alias: alias})
+				return err
+			},
+			Expectation: codes.OK,
+		},
+		{
+			Desc: "set size",
+			Call: func(ctx context.Context) error {
+				_, err := terminalService.SetSize(ctx, &api.SetTerminalSizeRequest{
+					Alias:    alias,
+					Priority: &api.SetTerminalSizeRequest_Force{Force: true},
+					Size:     &api.TerminalSize{Cols: 80, Rows: 24},
+				})
+				return err
+			},
+			Expectation: codes.PermissionDenied,
+		},
+		{
+			Desc: "broadcast",
+			Call: func(ctx context.Context) error {
+				resp, err := terminalService.Broadcast(ctx, &api.BroadcastTerminalRequest{Aliases: []string{alias}, Stdin: []byte("\n")})
+				if err != nil {
+					return err
+				}
+				return status.Error(codes.Code(resp.Results[0].Code), resp.Results[0].Error)
+			},
+			Expectation: codes.PermissionDenied,
+		},
+		{
+			Desc: "share",
+			Call: func(ctx context.Context) error {
+				_, err := terminalService.ShareTerminal(ctx, &api.ShareTerminalRequest{Alias: alias, Scope: api.TerminalAccessScope_read_write})
+				return err
+			},
+			Expectation: codes.Unauthenticated,
+		},
+		{
+			Desc: "record",
+			Call: func(ctx context.Context) error {
+				_, err := terminalService.SetRecording(ctx, &api.SetTerminalRecordingRequest{Alias: alias, Enabled: true})
+				return err
+			},
+			Expectation: codes.Unauthenticated,
+		},
+		{
+			Desc: "open",
+			Call: func(ctx context.Context) error {
+				_, err := terminalService.Open(ctx, &api.OpenTerminalRequest{Shell: "/bin/sh"})
+				return err
+			},
+			Expectation: codes.Unauthenticated,
+		},
+	}
+	for _, test := range rpcTests {
+		t.Run(test.Desc, func(t *testing.T) {
+			if diff := cmp.Diff(test.Expectation, status.Code(test.Call(viewerCtx))); diff != "" {
+				t.Errorf("unexpected status code (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	listenCtx, listenCancel := context.WithCancel(ctx)
+	defer listenCancel()
+	listener := &TestTitleTerminalServiceListener{
+		ctx:   listenCtx,
+		resps: make(chan *api.ListenTerminalResponse),
+	}
+	go func() {
+		for range listener.resps {
+		}
+	}()
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- terminalService.Listen(&api.ListenTerminalRequest{Alias: alias, Token: readOnly.Token}, listener)
+	}()
+
+	var viewers []*api.TerminalViewer
+	for len(viewers) == 0 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("viewer did not show up")
+		case <-time.After(20 * time.Millisecond):
+		}
+		info, err := terminalService.Get(ctx, &api.GetTerminalRequest{Alias: alias})
+		if err != nil {
+			t.Fatal(err)
+		}
+		viewers = info.Viewers
+	}
+	if viewers[0].Name != "alice" || viewers[0].Scope != api.TerminalAccessScope_read_only {
+		t.Errorf("unexpected viewer: %v", viewers[0])
+	}
+
+	_, err = terminalService.RevokeTerminalShare(ctx, &api.RevokeTerminalShareRequest{Alias: alias, Token: readOnly.Token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-listenErr:
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("expected viewer to be disconnected with Unauthenticated, got %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("viewer was not disconnected after revoking the token")
+	}
+}
+
+func TestReadBacklog(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mux := NewMux()
+	defer mux.Close(ctx)
+
+	spill := &SpillStore{Dir: t.TempDir()}
+	alias, err := mux.Start(exec.Command("/bin/sh", "-c", "seq 1 2000; sleep 5"), TermOptions{
+		BacklogSize: 1024,
+		Spill:       spill,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	term, ok := mux.Get(alias)
+	if !ok {
+		t.Fatal("terminal is not found")
+	}
+
+	var expectation strings.Builder
+	for i := 1; i <= 2000; i++ {
+		fmt.Fprintf(&expectation, "%d\r\n", i)
+	}
+	for {
+		if _, total := term.BacklogRange(); total >= int64(expectation.Len()) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("terminal output is incomplete")
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+
+	tests := []struct {
+		Desc        string
+		From, To    int64
+		Expectation string
+	}{
+		{Desc: "in memory", From: int64(expectation.Len() - 12), To: 0, Expectation: expectation.String()[expectation.Len()-12:]},
+		{Desc: "spilled", From: 0, To: 10, Expectation: "1\r\n2\r\n3\r\n4"},
+		{Desc: "spanning disk and memory", From: 0, To: 0, Expectation: expectation.String()},
+		{Desc: "beyond the end", From: int64(expectation.Len() + 10), To: 0, Expectation: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			data, start, _, err := term.ReadBacklog(test.From, test.To)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expectation, string(data)); diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+			if test.Expectation != "" && start != test.From {
+				t.Errorf("expected data to start at %d, got %d", test.From, start)
+			}
+		})
+	}
+
+	if diff := cmp.Diff(int64(len(term.Backlog())), int64(1024)); diff != "" {
+		t.Errorf("unexpected in-memory backlog size (-want +got):\n%s", diff)
+	}
+}
+
+func TestSpillStoreLimit(t *testing.T) {
+	store := &SpillStore{Dir: t.TempDir(), MaxSize: 4096}
+	noisy, err := store.open("noisy", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quiet, err := store.open("quiet", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := quiet.Write(bytes.Repeat([]byte("q"), 1000)); err != nil {
+		t.Fatal(err)
+	}
+	output := bytes.Repeat([]byte("0123456789"), 1000)
+	if _, err := noisy.Write(output); err != nil {
+		t.Fatal(err)
+	}
+
+	if used := store.Used(); used > store.MaxSize {
+		t.Errorf("disk usage %d exceeds the limit of %d", used, store.MaxSize)
+	}
+	if start, end := quiet.Range(); start != 0 || end != 1000 {
+		t.Errorf("expected the quiet terminal to keep its output, got range %d-%d", start, end)
+	}
+
+	start, end := noisy.Range()
+	if start == 0 || end != int64(len(output)) {
+		t.Fatalf("expected the oldest output of the noisy terminal to be evicted, got range %d-%d", start, end)
+	}
+	data, from, err := noisy.ReadRange(0, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(output[from:]), string(data)); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+
+	if err := noisy.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(int64(1000), store.Used()); diff != "" {
+		t.Errorf("unexpected disk usage after close (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mux := NewMux()
+	defer mux.Close(ctx)
+
+	terminalService := NewMuxTerminalService(mux)
+	ctx = asOwner(ctx, terminalService)
+	var aliases []string
+	for _, script := range []string{
+		`printf 'build started\n\033[31mpanic: boom\033[0m\ngoroutine 1\n'; sleep 5`,
+		`printf 'all good\n'; sleep 5`,
+	} {
+		resp, err := terminalService.Open(ctx, &api.OpenTerminalRequest{Shell: "/bin/sh", ShellArgs: []string{"-c", script}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		aliases = append(aliases, resp.Terminal.Alias)
+	}
+	for _, alias := range aliases {
+		term, _ := mux.Get(alias)
+		for !bytes.Contains(term.Backlog(), []byte("\r\n")) {
+			select {
+			case <-ctx.Done():
+				t.Fatal("terminal output is incomplete")
+			case <-time.After(20 * time.Millisecond):
+			}
+		}
+	}
+
+	tests := []struct {
+		Desc        string
+		Request     *api.SearchTerminalsRequest
+		Expectation []*api.TerminalSearchMatch
+		Code        codes.Code
+	}{
+		{
+			Desc:    "all terminals with context",
+			Request: &api.SearchTerminalsRequest{Pattern: "^panic:", Context: 1},
+			Expectation: []*api.TerminalSearchMatch{{
+				Alias:       aliases[0],
+				Line:        "panic: boom",
+				Before:      []string{"build started"},
+				After:       []string{"goroutine 1"},
+				Offset:      int64(len("build started\r\n")),
+				MatchOffset: int64(len("build started\r\n\x1b[31m")),
+			}},
+		},
+		{
+			Desc:    "ignore case in a single terminal",
+			Request: &api.SearchTerminalsRequest{Alias: aliases[1], Pattern: "GOOD", IgnoreCase: true},
+			Expectation: []*api.TerminalSearchMatch{{
+				Alias:       aliases[1],
+				Line:        "all good",
+				MatchOffset: 4,
+			}},
+		},
+		{
+			Desc:    "invalid pattern",
+			Request: &api.SearchTerminalsRequest{Pattern: "("},
+			Code:    codes.InvalidArgument,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			resp, err := terminalService.Search(ctx, test.Request)
+			if diff := cmp.Diff(test.Code, status.Code(err)); diff != "" {
+				t.Fatalf("unexpected status code (-want +got):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			for _, match := range resp.Matches {
+				match.Title = ""
+			}
+			if diff := cmp.Diff(test.Expectation, resp.Matches, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected matches (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	// listening at the offset of a match starts with the matching line
+	term, _ := mux.Get(aliases[0])
+	stdout := term.Stdout.ListenWithOptions(TermListenOptions{Offset: int64(len("build started\r\n"))})
+	defer stdout.Close()
+	buf := make([]byte, 4096)
+	n, err := stdout.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(buf[:n]), "\x1b[31mpanic: boom") {
+		t.Errorf("unexpected output at offset: %q", buf[:n])
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mux := NewMux()
+	defer mux.Close(ctx)
+
+	terminalService := NewMuxTerminalService(mux)
+	ctx = asOwner(ctx, terminalService)
+	var aliases []string
+	for _, role := range []string{"service", "service", "editor"} {
+		resp, err := terminalService.Open(ctx, &api.OpenTerminalRequest{
+			Shell:       "/bin/sh",
+			Annotations: map[string]string{"role": role},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		aliases = append(aliases, resp.Terminal.Alias)
+	}
+
+	stdin := []byte("echo sync-$((1+1))\n")
+	resp, err := terminalService.Broadcast(ctx, &api.BroadcastTerminalRequest{
+		Aliases:  []string{"unknown"},
+		Selector: map[string]string{"role": "service"},
+		Stdin:    stdin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectation := []*api.TerminalBroadcastResult{
+		{Alias: "unknown", Code: uint32(codes.NotFound), Error: "terminal not found"},
+		{Alias: aliases[0], BytesWritten: uint32(len(stdin))},
+		{Alias: aliases[1], BytesWritten: uint32(len(stdin))},
+	}
+	if diff := cmp.Diff(expectation, resp.Results, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected results (-want +got):\n%s", diff)
+	}
+
+	for _, alias := range aliases[:2] {
+		term, _ := mux.Get(alias)
+		for !bytes.Contains(term.Backlog(), []byte("sync-2")) {
+			select {
+			case <-ctx.Done():
+				t.Fatalf("input was not written to terminal %s", alias)
+			case <-time.After(20 * time.Millisecond):
+			}
+		}
+	}
+	if term, _ := mux.Get(aliases[2]); bytes.Contains(term.Backlog(), []byte("sync-")) {
+		t.Error("input was written to a terminal not selected")
+	}
+
+	_, err = terminalService.Broadcast(ctx, &api.BroadcastTerminalRequest{Stdin: stdin})
+	if diff := cmp.Diff(codes.InvalidArgument, status.Code(err)); diff != "" {
+		t.Errorf("unexpected status code (-want +got):\n%s", diff)
+	}
+}
+
+func TestTerminalNames(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mux := NewMux()
+	defer mux.Close(ctx)
+
+	terminalService := NewMuxTerminalService(mux)
+	ctx = asOwner(ctx, terminalService)
+	open := func(name string, annotations map[string]string) (string, error) {
+		resp, err := terminalService.Open(ctx, &api.OpenTerminalRequest{
+			Shell:       "/bin/sh",
+			Name:        name,
+			Annotations: annotations,
+		})
+		if err != nil {
+			return "", err
+		}
+		return resp.Terminal.Alias, nil
+	}
+	apiServer, err := open("api-server", map[string]string{"role": "service", "app": "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	worker, err := open("", map[string]string{"role": "service", "app": "worker"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		Desc string
+		Name string
+		Code codes.Code
+	}{
+		{Desc: "name in use", Name: "api-server", Code: codes.AlreadyExists},
+		{Desc: "alias in use", Name: worker, Code: codes.AlreadyExists},
+		{Desc: "selector as name", Name: "app=api", Code: codes.InvalidArgument},
+	} {
+		t.Run(test.Desc, func(t *testing.T) {
+			_, err := open(test.Name, nil)
+			if diff := cmp.Diff(test.Code, status.Code(err)); diff != "" {
+				t.Errorf("unexpected status code (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	for _, test := range []struct {
+		Desc  string
+		Ref   string
+		Alias string
+		Code  codes.Code
+	}{
+		{Desc: "alias", Ref: worker, Alias: worker},
+		{Desc: "name", Ref: "api-server", Alias: apiServer},
+		{Desc: "selector", Ref: "app=worker", Alias: worker},
+		{Desc: "selector with several annotations", Ref: "role=service,app=api", Alias: apiServer},
+		{Desc: "ambiguous selector", Ref: "role=service", Code: codes.FailedPrecondition},
+		{Desc: "unknown name", Ref: "db", Code: codes.NotFound},
+		{Desc: "unmatched selector", Ref: "app=db", Code: codes.NotFound},
+	} {
+		t.Run(test.Desc, func(t *testing.T) {
+			resp, err := terminalService.Get(ctx, &api.GetTerminalRequest{Alias: test.Ref})
+			if diff := cmp.Diff(test.Code, status.Code(err)); diff != "" {
+				t.Fatalf("unexpected status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.Alias, resp.GetAlias()); diff != "" {
+				t.Errorf("unexpected alias (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	// every call resolves names and selectors
+	if _, err := terminalService.SetTitle(ctx, &api.SetTerminalTitleRequest{Alias: "api-server", Title: "api"}); err != nil {
+		t.Errorf("cannot set the title by name: %v", err)
+	}
+	if _, err := terminalService.Export(ctx, &api.ExportTerminalRequest{Alias: "app=worker"}); err != nil {
+		t.Errorf("cannot export by selector: %v", err)
+	}
+	if _, err := terminalService.ShareTerminal(ctx, &api.ShareTerminalRequest{Alias: "api-server"}); err != nil {
+		t.Errorf("cannot share by name: %v", err)
+	}
+	broadcast, err := terminalService.Broadcast(ctx, &api.BroadcastTerminalRequest{Aliases: []string{"api-server", apiServer, "db"}, Stdin: []byte("\n")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []string
+	for _, res := range broadcast.Results {
+		results = append(results, fmt.Sprintf("%s:%s", res.Alias, codes.Code(res.Code)))
+	}
+	if diff := cmp.Diff([]string{apiServer + ":OK", "db:NotFound"}, results); diff != "" {
+		t.Errorf("unexpected broadcast results (-want +got):\n%s", diff)
+	}
+
+	// the name is released once the terminal is closed
+	_, err = terminalService.Shutdown(ctx, &api.ShutdownTerminalRequest{Alias: "api-server"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := open("api-server", nil); err != nil {
+		t.Errorf("cannot reuse the name of a closed terminal: %v", err)
+	}
+}
+
+func TestTerminalLimits(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mux := NewMux()
+	mux.Limits = TerminalLimits{MaxTerminals: 3, AnnotationKey: "client", MaxPerAnnotation: 2}
+	defer mux.Close(ctx)
+
+	terminalService := NewMuxTerminalService(mux)
+	ctx = asOwner(ctx, terminalService)
+	for _, test := range []struct {
+		Desc   string
+		Client string
+		Task   string
+		Code   codes.Code
+	}{
+		{Desc: "task of client a", Client: "a", Task: "build"},
+		{Desc: "first of client a", Client: "a"},
+		{Desc: "second of client a", Client: "a"},
+		{Desc: "third of client a", Client: "a", Code: codes.ResourceExhausted},
+		{Desc: "first of client b", Client: "b"},
+		{Desc: "over the total limit", Client: "c", Code: codes.ResourceExhausted},
+		{Desc: "task over the limits", Client: "a", Task: "test"},
+	} {
+		t.Run(test.Desc, func(t *testing.T) {
+			annotations := map[string]string{"client": test.Client}
+			if test.Task != "" {
+				annotations[TaskAnnotation] = test.Task
+			}
+			_, err := terminalService.Open(ctx, &api.OpenTerminalRequest{
+				Shell:       "/bin/sh",
+				Annotations: annotations,
+			})
+			if diff := cmp.Diff(test.Code, status.Code(err)); diff != "" {
+				t.Errorf("unexpected status code (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	resp, err := terminalService.List(ctx, &api.ListTerminalsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectation := &api.TerminalLimits{MaxTerminals: 3, AnnotationKey: "client", MaxPerAnnotation: 2}
+	if diff := cmp.Diff(expectation, resp.Limits, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected limits (-want +got):\n%s", diff)
+	}
+}
+
+func TestStartHooks(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		prepared []string
+		released int
+		output   = make(chan struct{}, 1)
+	)
+	mux := NewMux()
+	mux.PrepareStart = func(cmd *exec.Cmd, annotations map[string]string) func() {
+		if cmd.Process != nil {
+			t.Error("PrepareStart was called after the process started")
+		}
+		prepared = append(prepared, annotations["task"])
+		return func() { released++ }
+	}
+	mux.OnOutput = func() {
+		select {
+		case output <- struct{}{}:
+		default:
+		}
+	}
+	defer mux.Close(ctx)
+
+	_, err := mux.Start(exec.Command("/bin/sh", "-c", "echo hello; exec cat"), TermOptions{
+		Annotations: map[string]string{"task": "build"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"build"}, prepared); diff != "" {
+		t.Errorf("unexpected prepared terminals (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(1, released); diff != "" {
+		t.Errorf("unexpected releases (-want +got):\n%s", diff)
+	}
+	select {
+	case <-output:
+	case <-ctx.Done():
+		t.Error("OnOutput was not called")
+	}
+}
+
+func TestReapIdleTerminals(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mux := NewMux()
+	defer mux.Close(ctx)
+
+	terminalService := NewMuxTerminalService(mux)
+	ctx = asOwner(ctx, terminalService)
+	open := func(idleTimeout uint32, annotations map[string]string) string {
+		resp, err := terminalService.Open(ctx, &api.OpenTerminalRequest{Shell: "/bin/sh", IdleTimeout: idleTimeout, Annotations: annotations})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Terminal.Alias
+	}
+	idle := open(60, nil)
+	listened := open(60, nil)
+	noTimeout := open(0, nil)
+	task := open(60, map[string]string{TaskAnnotation: "serve"})
+
+	openAliases := func() []string {
+		resp, err := terminalService.List(ctx, &api.ListTerminalsRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var res []string
+		for _, term := range resp.Terminals {
+			res = append(res, term.Alias)
+		}
+		return res
+	}
+
+	term, _ := mux.Get(listened)
+	listener := term.Stdout.Listen()
+	defer listener.Close()
+
+	// a reap shortly after the last output keeps all terminals open
+	mux.reapIdleTerminals(ctx, time.Now().Add(30*time.Second))
+	if diff := cmp.Diff([]string{idle, listened, noTimeout, task}, openAliases()); diff != "" {
+		t.Errorf("unexpected terminals (-want +got):\n%s", diff)
+	}
+
+	mux.reapIdleTerminals(ctx, time.Now().Add(2*time.Minute))
+	if diff := cmp.Diff([]string{listened, noTimeout, task}, openAliases()); diff != "" {
+		t.Errorf("unexpected terminals after the idle timeout (-want +got):\n%s", diff)
+	}
+}
+
+func TestBanner(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mux := NewMux()
+	defer mux.Close(ctx)
+
+	terminalService := NewMuxTerminalService(mux)
+	ctx = asOwner(ctx, terminalService)
+	terminalService.Banner = func() string { return "welcome\r\n" }
+
+	for _, test := range []struct {
+		Desc      string
+		ShellArgs []string
+		Banner    bool
+	}{
+		{Desc: "interactive shell", Banner: true},
+		{Desc: "command", ShellArgs: []string{"-c", "sleep 1"}},
+	} {
+		t.Run(test.Desc, func(t *testing.T) {
+			resp, err := terminalService.Open(ctx, &api.OpenTerminalRequest{Shell: "/bin/sh", ShellArgs: test.ShellArgs})
+			if err != nil {
+				t.Fatal(err)
+			}
+			term, _ := mux.Get(resp.Terminal.Alias)
+			if diff := cmp.Diff(test.Banner, bytes.HasPrefix(term.Backlog(), []byte("welcome\r\n"))); diff != "" {
+				t.Errorf("unexpected banner (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClipboard(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mux := NewMux()
+	defer mux.Close(ctx)
+
+	copied := make(chan string, 1)
+	terminalService := NewMuxTerminalService(mux)
+	ctx = asOwner(ctx, terminalService)
+	terminalService.OnClipboard = func(alias string, event ClipboardEvent) {
+		copied <- string(event.Content)
+	}
+	resp, err := terminalService.Open(ctx, &api.OpenTerminalRequest{Shell: "/bin/sh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	term, _ := mux.Get(resp.Terminal.Alias)
+	events, stop := term.ListenClipboard()
+	defer stop()
+
+	_, err = term.PTY.Write([]byte("printf '\\033]52;c;%s\\007' $(printf copied | base64)\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-events:
+		if diff := cmp.Diff(ClipboardEvent{Selection: "c", Content: []byte("copied")}, event); diff != "" {
+			t.Errorf("unexpected event (-want +got):\n%s", diff)
+		}
+	case <-ctx.Done():
+		t.Fatal("no clipboard event")
+	}
+	select {
+	case content := <-copied:
+		if content != "copied" {
+			t.Errorf("unexpected clipboard content: %q", content)
+		}
+	case <-ctx.Done():
+		t.Fatal("OnClipboard was not called")
+	}
+}
+
+// asOwner returns a context of a call passing the owner token of the service.
+func asOwner(ctx context.Context, srv *MuxTerminalService) context.Context {
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(util.OwnerTokenMetadataKey, srv.OwnerToken))
+}
@@ -62,6 +62,19 @@ func buildChildProcEnv(cfg *config.Config) []string {
 	return envList
 }
 
+// RuntimeEnv returns the environment variables of the runtime config, expanded
+// against the environment of supervisor.
+func RuntimeEnv(cfg *config.Config) []string {
+	var res []string
+	for name, value := range cfg.Runtime.Environment {
+		if isBlacklisted(name) {
+			continue
+		}
+		res = append(res, fmt.Sprintf("%s=%s", name, os.ExpandEnv(value)))
+	}
+	return res
+}
+
 // isBlacklisted checks whether an environment variable should be excluded from the child process.
 func isBlacklisted(name string) bool {
 	nameUpper := strings.ToUpper(name)